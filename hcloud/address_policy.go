@@ -0,0 +1,267 @@
+package hcloud
+
+import (
+	"fmt"
+	"net"
+	"slices"
+
+	hrobotmodels "github.com/syself/hrobot-go/models"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/utils"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// addressPolicyFor returns the first address policy of cfg, which matches the
+// provider and the labels of node. Without a match, the default policy is
+// returned.
+func addressPolicyFor(provider config.AddressProvider, node *corev1.Node, cfg config.HCCMConfiguration) config.AddressPolicy {
+	var nodeLabels labels.Set
+	if node != nil {
+		nodeLabels = node.Labels
+	}
+
+	for _, policy := range cfg.Instance.AddressPolicies {
+		if policy.Provider != "" && policy.Provider != provider {
+			continue
+		}
+		// The selector was already checked by [config.HCCMConfiguration.Validate].
+		selector, err := labels.Parse(policy.NodeSelector)
+		if err != nil || !selector.Matches(nodeLabels) {
+			continue
+		}
+		return policy
+	}
+	return defaultAddressPolicy(provider, cfg)
+}
+
+// defaultAddressPolicy returns the policy derived from the address family and
+// the other instance settings.
+func defaultAddressPolicy(provider config.AddressProvider, cfg config.HCCMConfiguration) config.AddressPolicy {
+	dualStack := cfg.Instance.AddressFamily == config.AddressFamilyDualStack
+	ipv4 := cfg.Instance.AddressFamily == config.AddressFamilyIPv4 || dualStack
+	ipv6 := cfg.Instance.AddressFamily == config.AddressFamilyIPv6 || dualStack
+
+	var sources []config.AddressSource
+	switch provider {
+	case config.AddressProviderCloud:
+		var floating []config.AddressSource
+		if ipv4 {
+			floating = append(floating, config.AddressSourceFloatingIPv4)
+		}
+		if ipv6 {
+			floating = append(floating, config.AddressSourceFloatingIPv6)
+		}
+
+		if cfg.Instance.FloatingIPs == config.FloatingIPsFirst {
+			sources = append(sources, floating...)
+		}
+		if ipv4 {
+			sources = append(sources, config.AddressSourcePublicIPv4)
+		}
+		if ipv6 {
+			sources = append(sources, config.AddressSourcePublicIPv6)
+		}
+		if cfg.Instance.FloatingIPs == config.FloatingIPsLast {
+			sources = append(sources, floating...)
+		}
+		sources = append(sources, config.AddressSourceNetwork)
+
+	case config.AddressProviderRobot:
+		if ipv6 {
+			sources = append(sources, config.AddressSourcePublicIPv6)
+		}
+		if ipv4 {
+			sources = append(sources, config.AddressSourcePublicIPv4)
+		}
+		if cfg.Robot.ForwardInternalIPs {
+			sources = append(sources, config.AddressSourceForwardedInternalIPs)
+		}
+	}
+
+	return config.AddressPolicy{Name: "default", Provider: provider, Sources: sources}
+}
+
+func usesFloatingIPs(policy config.AddressPolicy) bool {
+	return slices.Contains(policy.Sources, config.AddressSourceFloatingIPv4) ||
+		slices.Contains(policy.Sources, config.AddressSourceFloatingIPv6)
+}
+
+func ipv6HostSuffix(policy config.AddressPolicy) net.IP {
+	suffix := policy.IPv6HostSuffix
+	if suffix == "" {
+		suffix = config.DefaultIPv6HostSuffix
+	}
+	// The suffix was already checked by [config.HCCMConfiguration.Validate].
+	ip, _ := config.ParseIPv6HostSuffix(suffix)
+	return ip
+}
+
+// ipv6HostAddress returns the address with the /64 prefix of network and the
+// host part of suffix.
+func ipv6HostAddress(network, suffix net.IP) net.IP {
+	address := make(net.IP, net.IPv6len)
+	copy(address, network.To16()[:8])
+	copy(address[8:], suffix.To16()[8:])
+	return address
+}
+
+// cloudNodeAddresses returns the addresses of a Hetzner Cloud Server according
+// to policy. Sources only supported for Robot servers are skipped.
+func cloudNodeAddresses(
+	policy config.AddressPolicy,
+	networkID int64,
+	server *hcloud.Server,
+	floatingIPs []*hcloud.FloatingIP,
+) []corev1.NodeAddress {
+	addresses := []corev1.NodeAddress{{Type: corev1.NodeHostName, Address: server.Name}}
+	suffix := ipv6HostSuffix(policy)
+
+	for _, source := range policy.Sources {
+		switch source {
+		case config.AddressSourcePublicIPv4:
+			if !server.PublicNet.IPv4.IsUnspecified() {
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: server.PublicNet.IPv4.IP.String()})
+			}
+
+		case config.AddressSourcePublicIPv6:
+			if !server.PublicNet.IPv6.IsUnspecified() {
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: ipv6HostAddress(server.PublicNet.IPv6.IP, suffix).String()})
+			}
+
+		case config.AddressSourceFloatingIPv4, config.AddressSourceFloatingIPv6:
+			ipType := hcloud.FloatingIPTypeIPv4
+			if source == config.AddressSourceFloatingIPv6 {
+				ipType = hcloud.FloatingIPTypeIPv6
+			}
+			for _, floatingIP := range floatingIPs {
+				if floatingIP.Type != ipType || floatingIP.Blocked {
+					continue
+				}
+				address := floatingIP.IP
+				if ipType == hcloud.FloatingIPTypeIPv6 {
+					address = ipv6HostAddress(address, suffix)
+				}
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: address.String()})
+			}
+
+		case config.AddressSourceNetwork:
+			if networkID <= 0 {
+				continue
+			}
+			for _, privateNet := range server.PrivateNet {
+				if privateNet.Network.ID == networkID {
+					addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: privateNet.IP.String()})
+				}
+			}
+
+		case config.AddressSourceAdditionalNetworks:
+			for _, privateNet := range server.PrivateNet {
+				if privateNet.Network.ID == networkID {
+					continue
+				}
+				if len(policy.AdditionalNetworkIDs) > 0 && !slices.Contains(policy.AdditionalNetworkIDs, privateNet.Network.ID) {
+					continue
+				}
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: privateNet.IP.String()})
+			}
+
+		default:
+		}
+	}
+
+	return addresses
+}
+
+// robotNodeAddressesForPolicy returns the addresses of a Robot server according
+// to policy. Sources only supported for Hetzner Cloud Servers are skipped.
+func robotNodeAddressesForPolicy(
+	policy config.AddressPolicy,
+	server *hrobotmodels.Server,
+	node *corev1.Node,
+	recorder record.EventRecorder,
+) []corev1.NodeAddress {
+	addresses := []corev1.NodeAddress{{Type: corev1.NodeHostName, Address: server.Name}}
+	suffix := ipv6HostSuffix(policy)
+
+	for _, source := range policy.Sources {
+		switch source {
+		case config.AddressSourcePublicIPv4:
+			if server.ServerIP != "" {
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: server.ServerIP})
+			}
+
+		case config.AddressSourcePublicIPv6:
+			if network := net.ParseIP(server.ServerIPv6Net); network != nil {
+				addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: ipv6HostAddress(network, suffix).String()})
+			}
+
+		case config.AddressSourceForwardedInternalIPs:
+			addresses = append(addresses, forwardedInternalIPs(policy, addresses, node, recorder)...)
+
+		default:
+		}
+	}
+
+	return addresses
+}
+
+// forwardedInternalIPs returns the InternalIPs of node, which match the address
+// families of the public IPs in policy and are not already in addresses.
+func forwardedInternalIPs(
+	policy config.AddressPolicy,
+	addresses []corev1.NodeAddress,
+	node *corev1.Node,
+	recorder record.EventRecorder,
+) []corev1.NodeAddress {
+	ipv4 := slices.Contains(policy.Sources, config.AddressSourcePublicIPv4)
+	ipv6 := slices.Contains(policy.Sources, config.AddressSourcePublicIPv6)
+	dualStack := ipv4 == ipv6
+
+	var forwarded []corev1.NodeAddress
+	for _, currentAddress := range node.Status.Addresses {
+		if currentAddress.Type != corev1.NodeInternalIP {
+			continue
+		}
+
+		ip := net.ParseIP(currentAddress.Address)
+		isIPv4 := ip.To4() != nil
+
+		var warnMsg string
+		if isIPv4 && ipv6 && !dualStack {
+			warnMsg = fmt.Sprintf(
+				"Configured InternalIP is IPv4 even though IPv6 only is configured. As a result, %s is not added as an InternalIP",
+				currentAddress.Address,
+			)
+		} else if !isIPv4 && ipv4 && !dualStack {
+			warnMsg = fmt.Sprintf(
+				"Configured InternalIP is IPv6 even though IPv4 only is configured. As a result, %s is not added as an InternalIP",
+				currentAddress.Address,
+			)
+		}
+
+		if warnMsg != "" {
+			utils.WarnEventLogf(recorder, node, MisconfiguredInternalIP, "%s", warnMsg)
+			continue
+		}
+
+		if slices.ContainsFunc(slices.Concat(addresses, forwarded), func(address corev1.NodeAddress) bool {
+			return address.Address == currentAddress.Address
+		}) {
+			utils.WarnEventLogf(
+				recorder,
+				node,
+				MisconfiguredInternalIP,
+				"Configured InternalIP already exists as an ExternalIP. As a result, %s is not added as an InternalIP",
+				currentAddress.Address,
+			)
+			continue
+		}
+
+		forwarded = append(forwarded, currentAddress)
+	}
+	return forwarded
+}
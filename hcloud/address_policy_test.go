@@ -0,0 +1,180 @@
+package hcloud
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+func addressStrings(addresses []corev1.NodeAddress) []string {
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, string(address.Type)+"="+address.Address)
+	}
+	return result
+}
+
+func TestAddressPolicyFor(t *testing.T) {
+	cfg := config.HCCMConfiguration{
+		Instance: config.InstanceConfiguration{
+			AddressFamily: config.AddressFamilyIPv4,
+			AddressPolicies: []config.AddressPolicy{
+				{Name: "robot", Provider: config.AddressProviderRobot, Sources: []config.AddressSource{config.AddressSourcePublicIPv6}},
+				{Name: "gateways", NodeSelector: "role=gateway", Sources: []config.AddressSource{config.AddressSourceFloatingIPv4}},
+			},
+		},
+	}
+	gateway := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"role": "gateway"}}}
+	worker := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"role": "worker"}}}
+
+	assert.Equal(t, "gateways", addressPolicyFor(config.AddressProviderCloud, gateway, cfg).Name)
+	assert.Equal(t, "default", addressPolicyFor(config.AddressProviderCloud, worker, cfg).Name)
+	assert.Equal(t, "robot", addressPolicyFor(config.AddressProviderRobot, gateway, cfg).Name)
+}
+
+func TestCloudNodeAddresses(t *testing.T) {
+	server := &hcloud.Server{
+		Name: "foobar",
+		PublicNet: hcloud.ServerPublicNet{
+			IPv4: hcloud.ServerPublicNetIPv4{IP: net.ParseIP("203.0.113.7")},
+			IPv6: hcloud.ServerPublicNetIPv6{IP: net.ParseIP("2001:db8:1234::")},
+		},
+		PrivateNet: []hcloud.ServerPrivateNet{
+			{Network: &hcloud.Network{ID: 1}, IP: net.ParseIP("10.0.0.2")},
+			{Network: &hcloud.Network{ID: 2}, IP: net.ParseIP("10.1.0.2")},
+			{Network: &hcloud.Network{ID: 3}, IP: net.ParseIP("10.2.0.2")},
+		},
+	}
+	floatingIPs := []*hcloud.FloatingIP{
+		{Type: hcloud.FloatingIPTypeIPv6, IP: net.ParseIP("2001:db8:5678::")},
+		{Type: hcloud.FloatingIPTypeIPv4, IP: net.ParseIP("198.51.100.5")},
+		{Type: hcloud.FloatingIPTypeIPv4, IP: net.ParseIP("198.51.100.6"), Blocked: true},
+	}
+
+	tests := []struct {
+		name   string
+		policy config.AddressPolicy
+		want   []string
+	}{
+		{
+			name: "default with floating ips first",
+			policy: defaultAddressPolicy(config.AddressProviderCloud, config.HCCMConfiguration{Instance: config.InstanceConfiguration{
+				AddressFamily: config.AddressFamilyDualStack,
+				FloatingIPs:   config.FloatingIPsFirst,
+			}}),
+			want: []string{
+				"Hostname=foobar",
+				"ExternalIP=198.51.100.5",
+				"ExternalIP=2001:db8:5678::1",
+				"ExternalIP=203.0.113.7",
+				"ExternalIP=2001:db8:1234::1",
+				"InternalIP=10.0.0.2",
+			},
+		},
+		{
+			name: "default with floating ips last",
+			policy: defaultAddressPolicy(config.AddressProviderCloud, config.HCCMConfiguration{Instance: config.InstanceConfiguration{
+				AddressFamily: config.AddressFamilyIPv4,
+				FloatingIPs:   config.FloatingIPsLast,
+			}}),
+			want: []string{
+				"Hostname=foobar",
+				"ExternalIP=203.0.113.7",
+				"ExternalIP=198.51.100.5",
+				"InternalIP=10.0.0.2",
+			},
+		},
+		{
+			name: "custom order and ipv6 host suffix",
+			policy: config.AddressPolicy{
+				Sources: []config.AddressSource{
+					config.AddressSourceNetwork,
+					config.AddressSourcePublicIPv6,
+					config.AddressSourceFloatingIPv6,
+					config.AddressSourceForwardedInternalIPs,
+				},
+				IPv6HostSuffix: "::10",
+			},
+			want: []string{
+				"Hostname=foobar",
+				"InternalIP=10.0.0.2",
+				"ExternalIP=2001:db8:1234::10",
+				"ExternalIP=2001:db8:5678::10",
+			},
+		},
+		{
+			name: "all additional networks",
+			policy: config.AddressPolicy{
+				Sources: []config.AddressSource{config.AddressSourceNetwork, config.AddressSourceAdditionalNetworks},
+			},
+			want: []string{
+				"Hostname=foobar",
+				"InternalIP=10.0.0.2",
+				"InternalIP=10.1.0.2",
+				"InternalIP=10.2.0.2",
+			},
+		},
+		{
+			name: "selected additional networks",
+			policy: config.AddressPolicy{
+				Sources:              []config.AddressSource{config.AddressSourceAdditionalNetworks},
+				AdditionalNetworkIDs: []int64{3},
+			},
+			want: []string{
+				"Hostname=foobar",
+				"InternalIP=10.2.0.2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addresses := cloudNodeAddresses(tt.policy, 1, server, floatingIPs)
+			assert.Equal(t, tt.want, addressStrings(addresses))
+		})
+	}
+
+	// The IPs of the server and the Floating IPs are not modified.
+	assert.Equal(t, "2001:db8:1234::", server.PublicNet.IPv6.IP.String())
+	assert.Equal(t, "2001:db8:5678::", floatingIPs[0].IP.String())
+}
+
+func TestRobotNodeAddressesForPolicy(t *testing.T) {
+	server := &hrobotmodels.Server{
+		Name:          "foobar",
+		ServerIP:      "203.0.113.7",
+		ServerIPv6Net: "2001:db8:1234::",
+	}
+	node := &corev1.Node{
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "10.0.1.2"},
+				{Type: corev1.NodeInternalIP, Address: "fd00::2"},
+			},
+		},
+	}
+
+	policy := config.AddressPolicy{
+		Sources: []config.AddressSource{
+			config.AddressSourcePublicIPv4,
+			config.AddressSourceForwardedInternalIPs,
+			config.AddressSourcePublicIPv6,
+			config.AddressSourceNetwork,
+		},
+		IPv6HostSuffix: "::2",
+	}
+	addresses := robotNodeAddressesForPolicy(policy, server, node, &MockEventRecorder{})
+	assert.Equal(t, []string{
+		"Hostname=foobar",
+		"ExternalIP=203.0.113.7",
+		"InternalIP=10.0.1.2",
+		"InternalIP=fd00::2",
+		"ExternalIP=2001:db8:1234::2",
+	}, addressStrings(addresses))
+}
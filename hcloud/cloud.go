@@ -26,47 +26,75 @@ import (
 
 	hrobot "github.com/syself/hrobot-go"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/scheme"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
 
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/credentials"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/hcops"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/lbprofile"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/ratelimit"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/robot"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud/metadata"
 )
 
 const (
-	providerName           = "hcloud"
-	apiClientTimeout       = 15 * time.Second
-	lbTypeCacheMaxAge      = time.Hour
-	lbTypeCacheDefaultMode = cache.ModeAll
+	providerName               = "hcloud"
+	apiClientTimeout           = 15 * time.Second
+	lbTypeCacheMaxAge          = time.Hour
+	lbTypeCacheDefaultMode     = cache.ModeAll
+	floatingIPCacheMaxAge      = time.Minute
+	floatingIPCacheDefaultMode = cache.ModeAll
 )
 
 // providerVersion is set by the build process using -ldflags -X.
 var providerVersion = "unknown"
 
+// Version returns the version of the hcloud-cloud-controller-manager.
+func Version() string {
+	return providerVersion
+}
+
 type cloud struct {
-	client      *hcloud.Client
-	robotClient hrobot.RobotClient
-	serverCache *cache.Cache[hcloud.Server]
-	lbTypeCache *cache.Cache[hcloud.LoadBalancerType]
-	cfg         config.HCCMConfiguration
-	recorder    record.EventRecorder
-	networkID   int64
-	cidr        string
-	nodeLister  corelisters.NodeLister
+	client          *hcloud.Client
+	robotClient     hrobot.RobotClient
+	serverCache     *cache.Cache[hcloud.Server]
+	projects        []*project
+	lbTypeCache     *cache.Cache[hcloud.LoadBalancerType]
+	floatingIPCache *cache.Cache[hcloud.FloatingIP]
+	cfg             config.HCCMConfiguration
+	cfgStore        *config.Store
+	credentials     credentialsState
+	recorder        record.EventRecorder
+	networkID       int64
+	cidr            string
+	nodeLister      corelisters.NodeLister
+
+	namespaceLister  corelisters.NamespaceLister
+	namespacesSynced toolscache.InformerSynced
+
+	serviceLister  corelisters.ServiceLister
+	servicesSynced toolscache.InformerSynced
+	profiles       *lbprofile.Lister
+	profilesSynced toolscache.InformerSynced
+
+	debug debugCache
 }
 
-func NewCloud(cidr string, nodeLister corelisters.NodeLister) (cloudprovider.Interface, error) {
+func NewCloud(cidr string, informerFactory informers.SharedInformerFactory) (_ cloudprovider.Interface, err error) {
 	const op = "hcloud/newCloud"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
 	ctx := context.Background()
 
 	cfg, err := config.Read()
@@ -78,47 +106,34 @@ func NewCloud(cidr string, nodeLister corelisters.NodeLister) (cloudprovider.Int
 		return nil, err
 	}
 
-	opts := []hcloud.ClientOption{
-		hcloud.WithToken(cfg.HCloudClient.Token),
-		hcloud.WithApplication("hcloud-cloud-controller", providerVersion),
-		hcloud.WithHTTPClient(
-			&http.Client{
-				Timeout: apiClientTimeout,
-			},
-		),
-	}
-
-	// start metrics server if enabled (enabled by default)
-	if cfg.Metrics.Enabled {
-		go metrics.Serve(cfg.Metrics.Address)
-		opts = append(opts, hcloud.WithInstrumentation(metrics.GetRegistry()))
+	if cfg.Tracing.Enabled {
+		if err := tracing.Setup(ctx, providerVersion); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
-	if cfg.HCloudClient.Debug {
-		opts = append(opts, hcloud.WithDebugWriter(os.Stderr))
-	}
-	if cfg.HCloudClient.Endpoint != "" {
-		opts = append(opts, hcloud.WithEndpoint(cfg.HCloudClient.Endpoint))
-	}
-	client := hcloud.NewClient(opts...)
+	client, tokenTransport := newHCloudClient(cfg, cfg.HCloudClient.Token)
 
 	metadataClient := metadata.NewClient(
 		metadata.WithApplication("hcloud-cloud-controller", providerVersion),
 	)
 
 	var robotClient hrobot.RobotClient
+	var robotTransport *credentials.Transport
 	if cfg.Robot.Enabled && cfg.Robot.User != "" && cfg.Robot.Password != "" {
-		c := hrobot.NewBasicAuthClientWithCustomHttpClient(
+		robotTransport = credentials.NewBasicAuthTransport(tracing.NewTransport(nil), cfg.Robot.User, cfg.Robot.Password)
+		c := robot.NewClient(
 			cfg.Robot.User,
 			cfg.Robot.Password,
 			&http.Client{
-				Timeout: apiClientTimeout,
+				Timeout:   apiClientTimeout,
+				Transport: robotTransport,
 			},
 		)
 
 		robotClient = robot.NewRateLimitedClient(
 			cfg.Robot.RateLimitWaitTime,
-			robot.NewCachedClient(cfg.Robot.CacheTimeout, c),
+			robot.NewCachedClient(cfg.Robot.CacheTimeout, cfg.Robot.ResetCacheTimeout, c),
 		)
 	}
 
@@ -150,21 +165,107 @@ func NewCloud(cidr string, nodeLister corelisters.NodeLister) (cloudprovider.Int
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	projects, err := newProjects(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	klog.Infof("Hetzner Cloud k8s cloud controller %s started\n", providerVersion)
 
 	serverCache := cache.NewServerCache(client, cfg.ServerCache.Mode, cfg.ServerCache.MaxAge)
 	lbTypeCache := cache.NewLoadBalancerTypeCache(client, lbTypeCacheDefaultMode, lbTypeCacheMaxAge)
+	floatingIPCache := cache.NewFloatingIPCache(client, floatingIPCacheDefaultMode, floatingIPCacheMaxAge)
+
+	c := &cloud{
+		client:          client,
+		robotClient:     robotClient,
+		serverCache:     serverCache,
+		projects:        projects,
+		lbTypeCache:     lbTypeCache,
+		floatingIPCache: floatingIPCache,
+		cfg:             cfg,
+		cfgStore:        config.NewStore(cfg),
+		credentials: credentialsState{
+			current: config.Credentials{
+				HCloudToken:   cfg.HCloudClient.Token,
+				RobotUser:     cfg.Robot.User,
+				RobotPassword: cfg.Robot.Password,
+				ProjectTokens: projectTokens(cfg.HCloudClient.Projects),
+			},
+			hcloudTransport: tokenTransport,
+			robotTransport:  robotTransport,
+		},
+		networkID: networkID,
+		cidr:      cidr,
+	}
+
+	// Informers must be requested before the informer factory is started,
+	// which happens after the cloud provider was initialized.
+	if informerFactory != nil {
+		c.nodeLister = informerFactory.Core().V1().Nodes().Lister()
+
+		if cfg.LoadBalancer.Enabled && cfg.LoadBalancer.NamespaceDefaultsEnabled {
+			namespaceInformer := informerFactory.Core().V1().Namespaces()
+			c.namespaceLister = namespaceInformer.Lister()
+			c.namespacesSynced = namespaceInformer.Informer().HasSynced
+		}
+
+		// The debug endpoint resolves the Services of the Load Balancers.
+		if cfg.LoadBalancer.Enabled && (cfg.LoadBalancer.ProfilesEnabled || cfg.Debug.EndpointEnabled) {
+			serviceInformer := informerFactory.Core().V1().Services()
+			c.serviceLister = serviceInformer.Lister()
+			c.servicesSynced = serviceInformer.Informer().HasSynced
+		}
+	}
+
+	// start metrics server if enabled (enabled by default)
+	if cfg.Metrics.Enabled {
+		// The checks run on all replicas, not only on the leader, so the
+		// liveness probe does not restart the standby replicas.
+		checker := c.newHealthChecker()
+		go checker.Run(wait.NeverStop)
+
+		handlers := checker.Handlers()
+		if cfg.Debug.EndpointEnabled {
+			handlers["/debug/hccm"] = c.debugHandler()
+		}
+		go metrics.Serve(cfg.Metrics.Address, handlers)
+	}
+
+	return c, nil
+}
+
+// newHCloudClient returns a client for the project of token. The transport
+// authenticates all requests, which allows to rotate the credentials without
+// recreating the client. All requests to the project share one rate limit.
+func newHCloudClient(cfg config.HCCMConfiguration, token string) (*hcloud.Client, *credentials.Transport) {
+	tokenTransport := credentials.NewBearerTokenTransport(
+		ratelimit.NewTransport(tracing.NewTransport(nil)),
+		token,
+	)
+
+	opts := []hcloud.ClientOption{
+		hcloud.WithToken(token),
+		hcloud.WithApplication("hcloud-cloud-controller", providerVersion),
+		hcloud.WithHTTPClient(
+			&http.Client{
+				Timeout:   apiClientTimeout,
+				Transport: tokenTransport,
+			},
+		),
+	}
+
+	if cfg.Metrics.Enabled {
+		opts = append(opts, hcloud.WithInstrumentation(metrics.GetRegistry()))
+	}
 
-	return &cloud{
-		client:      client,
-		robotClient: robotClient,
-		serverCache: serverCache,
-		lbTypeCache: lbTypeCache,
-		cfg:         cfg,
-		networkID:   networkID,
-		cidr:        cidr,
-		nodeLister:  nodeLister,
-	}, nil
+	if cfg.HCloudClient.Debug {
+		opts = append(opts, hcloud.WithDebugWriter(os.Stderr))
+	}
+	if cfg.HCloudClient.Endpoint != "" {
+		opts = append(opts, hcloud.WithEndpoint(cfg.HCloudClient.Endpoint))
+	}
+	return hcloud.NewClient(opts...), tokenTransport
 }
 
 func (c *cloud) Initialize(clientBuilder cloudprovider.ControllerClientBuilder, stop <-chan struct{}) {
@@ -176,10 +277,33 @@ func (c *cloud) Initialize(clientBuilder cloudprovider.ControllerClientBuilder,
 	go func() {
 		<-stop
 		eventBroadcaster.Shutdown()
+		if err := tracing.Shutdown(context.Background()); err != nil {
+			klog.ErrorS(err, "failed to export remaining spans")
+		}
 	}()
 
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "hcloud-cloud-controller-manager"})
 	c.recorder = recorder
+
+	if c.cfg.LoadBalancer.Enabled && c.cfg.LoadBalancer.ProfilesEnabled {
+		c.initProfiles(clientBuilder, stop)
+	}
+
+	if len(c.cfg.Instance.ServerLabels) > 0 && c.nodeLister != nil {
+		c.initServerLabelSync(clientBuilder, stop)
+	}
+
+	if len(c.cfg.Instance.StateTaints) > 0 && c.nodeLister != nil {
+		c.initStateTaintSync(clientBuilder, stop)
+	}
+
+	if c.cfg.File != "" {
+		go config.WatchFile(c.cfg.File, configFileCheckInterval, stop, c.reloadConfig)
+	}
+
+	for _, file := range config.CredentialFiles(c.cfg.HCloudClient.Projects) {
+		go config.WatchFile(file, credentialsFileCheckInterval, stop, c.rotateCredentials)
+	}
 }
 
 func (c *cloud) Instances() (cloudprovider.Instances, bool) {
@@ -188,7 +312,7 @@ func (c *cloud) Instances() (cloudprovider.Instances, bool) {
 }
 
 func (c *cloud) InstancesV2() (cloudprovider.InstancesV2, bool) {
-	return newInstances(c.client, c.robotClient, c.serverCache, c.recorder, c.networkID, c.cfg), true
+	return newInstances(c.client, c.robotClient, c.serverCache, c.floatingIPCache, c.projects, c.recorder, c.networkID, c.cfg), true
 }
 
 func (c *cloud) Zones() (cloudprovider.Zones, bool) {
@@ -201,19 +325,33 @@ func (c *cloud) LoadBalancer() (cloudprovider.LoadBalancer, bool) {
 		return nil, false
 	}
 
+	retry := hcops.DefaultRetryPolicy(c.cfg.HCloudClient.RetryBudget)
 	lbOps := &hcops.LoadBalancerOps{
-		LBClient:      &c.client.LoadBalancer,
-		RobotClient:   c.robotClient,
-		CertOps:       &hcops.CertificateOps{ActionClient: &c.client.Action, CertClient: &c.client.Certificate},
-		ActionClient:  &c.client.Action,
+		LBClient:    &c.client.LoadBalancer,
+		RobotClient: c.robotClient,
+		CertOps: &hcops.CertificateOps{
+			ActionClient: tracing.NewActionClient(&c.client.Action),
+			CertClient:   &c.client.Certificate,
+			Retry:        retry,
+		},
+		ActionClient:  tracing.NewActionClient(&c.client.Action),
 		NetworkClient: &c.client.Network,
 		LBTypeCache:   c.lbTypeCache,
+		Retry:         retry,
 		NetworkID:     c.networkID,
-		Cfg:           c.cfg,
+		Cfg:           c.cfgStore,
 		Recorder:      c.recorder,
+		Profiles:      c.profiles,
 	}
 
-	return newLoadBalancers(lbOps, &c.cfg.LoadBalancer), true
+	lbs := newLoadBalancers(lbOps, c.cfgStore)
+	lbs.recorder = c.recorder
+	lbs.namespaceLister = c.namespaceLister
+	lbs.namespacesSynced = c.namespacesSynced
+	lbs.profiles = c.profiles
+	lbs.profilesSynced = c.profilesSynced
+
+	return lbs, true
 }
 
 func (c *cloud) Clusters() (cloudprovider.Clusters, bool) {
@@ -238,6 +376,7 @@ func (c *cloud) Routes() (cloudprovider.Routes, bool) {
 		klog.ErrorS(err, "create routes provider", "networkID", c.networkID)
 		return nil, false
 	}
+	r.retry = hcops.DefaultRetryPolicy(c.cfg.HCloudClient.RetryBudget)
 	return r, true
 }
 
@@ -252,9 +391,11 @@ func (c *cloud) HasClusterID() bool {
 // serverIsAttachedToNetwork checks if the server where the master is running on is attached to the configured private network
 // We use this measurement to protect users against some parts of misconfiguration, like configuring a master in a not attached
 // network.
-func serverIsAttachedToNetwork(ctx context.Context, metadataClient *metadata.Client, networkID int64) (bool, error) {
+func serverIsAttachedToNetwork(ctx context.Context, metadataClient *metadata.Client, networkID int64) (_ bool, err error) {
 	const op = "serverIsAttachedToNetwork"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	serverPrivateNetworks, err := metadataClient.PrivateNetworksWithContext(ctx)
 	if err != nil {
@@ -38,13 +38,14 @@ import (
 )
 
 type testEnv struct {
-	Server      *httptest.Server
-	Mux         *http.ServeMux
-	Client      *hcloud.Client
-	RobotClient hrobot.RobotClient
-	ServerCache *cache.Cache[hcloud.Server]
-	Recorder    record.EventRecorder
-	Cfg         config.HCCMConfiguration
+	Server          *httptest.Server
+	Mux             *http.ServeMux
+	Client          *hcloud.Client
+	RobotClient     hrobot.RobotClient
+	ServerCache     *cache.Cache[hcloud.Server]
+	FloatingIPCache *cache.Cache[hcloud.FloatingIP]
+	Recorder        record.EventRecorder
+	Cfg             config.HCCMConfiguration
 }
 
 func (env *testEnv) Teardown() {
@@ -54,6 +55,7 @@ func (env *testEnv) Teardown() {
 	env.Client = nil
 	env.RobotClient = nil
 	env.ServerCache = nil
+	env.FloatingIPCache = nil
 	env.Recorder = nil
 }
 
@@ -70,6 +72,7 @@ func newTestEnv() testEnv {
 	robotClient := hrobot.NewBasicAuthClient("", "")
 	robotClient.SetBaseURL(server.URL + "/robot")
 	serverCache := cache.NewServerCache(client, cache.ModeOne, 10*time.Second)
+	floatingIPCache := cache.NewFloatingIPCache(client, cache.ModeAll, 10*time.Second)
 	recorder := record.NewBroadcaster().NewRecorder(scheme.Scheme, corev1.EventSource{Component: "hcloud-cloud-controller-manager"})
 
 	cfg := config.HCCMConfiguration{}
@@ -77,13 +80,14 @@ func newTestEnv() testEnv {
 	cfg.Instance.ZoneLabelEnabled = true
 
 	return testEnv{
-		Server:      server,
-		Mux:         mux,
-		Client:      client,
-		RobotClient: robotClient,
-		ServerCache: serverCache,
-		Recorder:    recorder,
-		Cfg:         cfg,
+		Server:          server,
+		Mux:             mux,
+		Client:          client,
+		RobotClient:     robotClient,
+		ServerCache:     serverCache,
+		FloatingIPCache: floatingIPCache,
+		Recorder:        recorder,
+		Cfg:             cfg,
 	}
 }
 
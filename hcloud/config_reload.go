@@ -0,0 +1,64 @@
+package hcloud
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/robot"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/utils"
+)
+
+const (
+	InvalidConfiguration = "InvalidConfiguration"
+	RestartRequired      = "RestartRequired"
+
+	configFileCheckInterval = 10 * time.Second
+)
+
+// reloadConfig reads the configuration again after the configuration file
+// changed. Changes which can be applied at runtime are applied immediately,
+// all other changes are reported with an event on the HCCM Pod.
+func (c *cloud) reloadConfig() {
+	cfg, err := config.Read()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		c.warnConfig(InvalidConfiguration, "Ignoring invalid configuration file %s: %s", c.cfg.File, err)
+		return
+	}
+
+	next, restartRequired := c.cfgStore.Get().Reload(cfg)
+	c.cfgStore.Set(next)
+	c.serverCache.SetDefaultMaxAge(next.ServerCache.MaxAge)
+	for _, p := range c.projects {
+		p.serverCache.SetDefaultMaxAge(next.ServerCache.MaxAge)
+	}
+	if c.robotClient != nil {
+		robot.SetRateLimitWaitTime(c.robotClient, next.Robot.RateLimitWaitTime)
+	}
+	klog.InfoS("reloaded configuration file", "path", c.cfg.File)
+
+	if len(restartRequired) > 0 {
+		c.warnConfig(RestartRequired, "Changes to %s require a restart to take effect", strings.Join(restartRequired, ", "))
+	}
+}
+
+// warnConfig emits a warning event on the HCCM Pod. The Pod is identified by
+// the POD_NAME and POD_NAMESPACE environment variables. If they are not set,
+// the warning is only logged.
+func (c *cloud) warnConfig(reason string, msg string, args ...any) {
+	name, namespace := os.Getenv("POD_NAME"), os.Getenv("POD_NAMESPACE")
+	if c.recorder == nil || name == "" || namespace == "" {
+		klog.Warningf(msg, args...)
+		return
+	}
+
+	pod := &corev1.ObjectReference{Kind: "Pod", APIVersion: "v1", Namespace: namespace, Name: name}
+	utils.WarnEventLogf(c.recorder, pod, reason, msg, args...)
+}
@@ -0,0 +1,50 @@
+package hcloud
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+)
+
+func TestCloud_reloadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	t.Setenv("HCLOUD_TOKEN", "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq")
+	t.Setenv("HCLOUD_CONFIG_FILE", path)
+	t.Setenv("POD_NAME", "hcloud-cloud-controller-manager-abc")
+	t.Setenv("POD_NAMESPACE", "kube-system")
+
+	writeConfig("loadBalancer:\n  location: fsn1\n")
+	cfg, err := config.Read()
+	require.NoError(t, err)
+
+	recorder := record.NewFakeRecorder(10)
+	c := &cloud{
+		cfg:         cfg,
+		cfgStore:    config.NewStore(cfg),
+		serverCache: cache.NewServerCache(nil, cache.ModeAll, time.Minute),
+		recorder:    recorder,
+	}
+
+	writeConfig("loadBalancer:\n  location: hel1\nnetwork:\n  nameOrID: my-network\n")
+	c.reloadConfig()
+	assert.Equal(t, "hel1", c.cfgStore.Get().LoadBalancer.Location)
+	assert.Empty(t, c.cfgStore.Get().Network.NameOrID)
+	assert.Equal(t, "Warning RestartRequired Changes to Network.NameOrID, Route.Enabled require a restart to take effect", <-recorder.Events)
+
+	writeConfig("loadBalancer:\n  location: nbg1\n  networkZone: eu-central\n")
+	c.reloadConfig()
+	assert.Equal(t, "hel1", c.cfgStore.Get().LoadBalancer.Location)
+	assert.Contains(t, <-recorder.Events, "Warning InvalidConfiguration Ignoring invalid configuration file "+path)
+}
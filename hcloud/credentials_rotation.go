@@ -0,0 +1,124 @@
+package hcloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	hrobot "github.com/syself/hrobot-go"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/credentials"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+const (
+	CredentialsRotationFailed = "CredentialsRotationFailed"
+
+	credentialsFileCheckInterval = 10 * time.Second
+)
+
+// credentialsState holds the credentials currently used by the API clients.
+type credentialsState struct {
+	mu      sync.Mutex
+	current config.Credentials
+
+	hcloudTransport *credentials.Transport
+	// robotTransport is nil, if Robot support is disabled.
+	robotTransport *credentials.Transport
+}
+
+// rotateCredentials reads the credentials again after one of the files they
+// are read from changed. New credentials are only used after they were
+// successfully validated against the API, otherwise the current ones are kept.
+func (c *cloud) rotateCredentials() {
+	c.credentials.mu.Lock()
+	defer c.credentials.mu.Unlock()
+
+	next, err := config.ReadCredentials(c.cfg.HCloudClient.Projects)
+	if err != nil {
+		c.warnConfig(CredentialsRotationFailed, "Keeping the current credentials, as the new ones could not be read: %s", err)
+		return
+	}
+	current := &c.credentials.current
+
+	if next.HCloudToken != current.HCloudToken {
+		if err := c.validateHCloudToken(next.HCloudToken); err != nil {
+			c.warnConfig(CredentialsRotationFailed, "Keeping the current Hetzner Cloud API token, as the new one is invalid: %s", err)
+		} else {
+			c.credentials.hcloudTransport.SetBearerToken(next.HCloudToken)
+			current.HCloudToken = next.HCloudToken
+			klog.Info("rotated Hetzner Cloud API token")
+		}
+	}
+
+	if c.credentials.robotTransport != nil &&
+		(next.RobotUser != current.RobotUser || next.RobotPassword != current.RobotPassword) {
+		if err := validateRobotCredentials(next.RobotUser, next.RobotPassword); err != nil {
+			c.warnConfig(CredentialsRotationFailed, "Keeping the current Robot credentials, as the new ones are invalid: %s", err)
+		} else {
+			c.credentials.robotTransport.SetBasicAuth(next.RobotUser, next.RobotPassword)
+			current.RobotUser, current.RobotPassword = next.RobotUser, next.RobotPassword
+			klog.Info("rotated Robot credentials")
+		}
+	}
+
+	for _, p := range c.projects {
+		token := next.ProjectTokens[p.name]
+		if token == current.ProjectTokens[p.name] {
+			continue
+		}
+		if err := c.validateHCloudToken(token); err != nil {
+			c.warnConfig(CredentialsRotationFailed, "Keeping the current API token of project %s, as the new one is invalid: %s", p.name, err)
+		} else {
+			p.transport.SetBearerToken(token)
+			current.ProjectTokens[p.name] = token
+			klog.InfoS("rotated Hetzner Cloud API token", "project", p.name)
+		}
+	}
+}
+
+// projectTokens returns the API tokens of projects by their name.
+func projectTokens(projects []config.ProjectConfiguration) map[string]string {
+	tokens := make(map[string]string, len(projects))
+	for _, p := range projects {
+		tokens[p.Name] = p.Token
+	}
+	return tokens
+}
+
+// validateHCloudToken checks the token with a cheap API call.
+func (c *cloud) validateHCloudToken(token string) error {
+	if token == "" {
+		return errors.New("token is empty")
+	}
+
+	opts := []hcloud.ClientOption{
+		hcloud.WithToken(token),
+		hcloud.WithApplication("hcloud-cloud-controller", providerVersion),
+		hcloud.WithHTTPClient(&http.Client{Timeout: apiClientTimeout}),
+	}
+	if c.cfg.HCloudClient.Endpoint != "" {
+		opts = append(opts, hcloud.WithEndpoint(c.cfg.HCloudClient.Endpoint))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), apiClientTimeout)
+	defer cancel()
+
+	_, _, err := hcloud.NewClient(opts...).Location.List(ctx, hcloud.LocationListOpts{
+		ListOpts: hcloud.ListOpts{PerPage: 1},
+	})
+	return err
+}
+
+func validateRobotCredentials(user, password string) error {
+	if user == "" || password == "" {
+		return errors.New("user and password must not be empty")
+	}
+
+	return hrobot.NewBasicAuthClientWithCustomHttpClient(user, password, &http.Client{Timeout: apiClientTimeout}).
+		ValidateCredentials()
+}
@@ -0,0 +1,101 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+func TestCloud_rotateCredentials(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	const (
+		oldToken = "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"
+		newToken = "Jk7LvbkEDJqXaUZb4kPCXvAutNhmxfaYAYQP6tRkbPcJ_NOT_VALID_b8TbexyhC"
+
+		oldProjectToken = "Xw9hCn3tGfRkPq7LzMvB2yDsJa6NeUcH4oTbW8iKxYdQ_NOT_VALID_mrfpgsuvl"
+		newProjectToken = "Pb4sLmYt8VqZcNw2HxRj7KgDe3FaUoTi6MnBk9SyWvEr_NOT_VALID_hqzxncwat"
+	)
+
+	var authorization string
+	env.Mux.HandleFunc("/locations", func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		switch authorization {
+		case "Bearer " + oldToken, "Bearer " + newToken, "Bearer " + oldProjectToken, "Bearer " + newProjectToken:
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(schema.ErrorResponse{Error: schema.Error{Code: "unauthorized", Message: "unable to authenticate"}})
+			return
+		}
+		json.NewEncoder(w).Encode(schema.LocationListResponse{Locations: []schema.Location{}})
+	})
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	writeToken := func(token string) {
+		require.NoError(t, os.WriteFile(tokenFile, []byte(token+"\n"), 0o600))
+	}
+	writeToken(oldToken)
+
+	projectTokenFile := filepath.Join(t.TempDir(), "project-token")
+	writeProjectToken := func(token string) {
+		require.NoError(t, os.WriteFile(projectTokenFile, []byte(token+"\n"), 0o600))
+	}
+	writeProjectToken(oldProjectToken)
+
+	t.Setenv("HCLOUD_ENDPOINT", env.Server.URL)
+	t.Setenv("HCLOUD_TOKEN_FILE", tokenFile)
+	t.Setenv("HCLOUD_PROJECTS", "team-a")
+	t.Setenv("HCLOUD_PROJECT_TEAM_A_TOKEN_FILE", projectTokenFile)
+	t.Setenv("HCLOUD_METRICS_ENABLED", "false")
+	t.Setenv("POD_NAME", "hcloud-cloud-controller-manager-abc")
+	t.Setenv("POD_NAMESPACE", "kube-system")
+
+	cloudProvider, err := NewCloud(DefaultClusterCIDR, nil)
+	require.NoError(t, err)
+	c := cloudProvider.(*cloud)
+	assert.Equal(t, []string{tokenFile, projectTokenFile}, config.CredentialFiles(c.cfg.HCloudClient.Projects))
+	recorder := record.NewFakeRecorder(10)
+	c.recorder = recorder
+
+	writeToken("invalid")
+	c.rotateCredentials()
+	assert.Equal(t, oldToken, c.credentials.current.HCloudToken)
+	assert.Contains(t, <-recorder.Events, "Warning CredentialsRotationFailed Keeping the current Hetzner Cloud API token, as the new one is invalid")
+
+	writeToken(newToken)
+	c.rotateCredentials()
+	assert.Equal(t, newToken, c.credentials.current.HCloudToken)
+	assert.Empty(t, recorder.Events)
+
+	// The existing client now uses the new token.
+	_, _, err = c.client.Location.List(t.Context(), hcloud.LocationListOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+newToken, authorization)
+
+	// The tokens of additional projects are rotated independently.
+	writeProjectToken("invalid")
+	c.rotateCredentials()
+	assert.Equal(t, oldProjectToken, c.credentials.current.ProjectTokens["team-a"])
+	assert.Contains(t, <-recorder.Events, "Warning CredentialsRotationFailed Keeping the current API token of project team-a, as the new one is invalid")
+
+	writeProjectToken(newProjectToken)
+	c.rotateCredentials()
+	assert.Equal(t, newProjectToken, c.credentials.current.ProjectTokens["team-a"])
+	assert.Equal(t, newToken, c.credentials.current.HCloudToken)
+	assert.Empty(t, recorder.Events)
+
+	_, _, err = c.projects[0].client.Location.List(t.Context(), hcloud.LocationListOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer "+newProjectToken, authorization)
+}
@@ -0,0 +1,245 @@
+package hcloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/hcops"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/ratelimit"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/robot"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+const (
+	// debugTimeout limits the API requests of a single debug request.
+	debugTimeout = 30 * time.Second
+	// debugMinInterval limits how often the state is collected, as the
+	// endpoint is served without authentication on the metrics port.
+	debugMinInterval = 10 * time.Second
+)
+
+// debugCache holds the last collected state, which is served again for
+// requests within debugMinInterval.
+type debugCache struct {
+	mu          sync.Mutex
+	state       debugState
+	collectedAt time.Time
+}
+
+// debugState is the response of the /debug/hccm endpoint.
+type debugState struct {
+	ServerCache   []cache.Entry[hcloud.Server] `json:"serverCache"`
+	Projects      []debugProject               `json:"projects,omitempty"`
+	Robot         *debugRobot                  `json:"robot,omitempty"`
+	LoadBalancers []debugLoadBalancer          `json:"loadBalancers"`
+	Routes        []debugRoute                 `json:"routes,omitempty"`
+	// Errors lists the parts of the state, which could not be collected.
+	Errors []string `json:"errors,omitempty"`
+}
+
+type debugProject struct {
+	Name        string                       `json:"name"`
+	ServerCache []cache.Entry[hcloud.Server] `json:"serverCache"`
+}
+
+type debugRobot struct {
+	RateLimited bool                 `json:"rateLimited"`
+	NextTry     *time.Time           `json:"nextTry,omitempty"`
+	LastRequest *time.Time           `json:"lastRequest,omitempty"`
+	LastError   string               `json:"lastError,omitempty"`
+	Cache       *robot.CacheSnapshot `json:"cache,omitempty"`
+}
+
+type debugLoadBalancer struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	ServiceUID string   `json:"serviceUID"`
+	Service    string   `json:"service,omitempty"`
+	Targets    []string `json:"targets"`
+	Services   []string `json:"services"`
+}
+
+type debugRoute struct {
+	Destination string `json:"destination"`
+	Gateway     string `json:"gateway"`
+	// Node is empty if no server has the gateway as private IP.
+	Node string `json:"node,omitempty"`
+}
+
+func (c *cloud) debugHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(c.cachedDebugState(r.Context())); err != nil {
+			klog.ErrorS(err, "failed to write debug response")
+		}
+	})
+}
+
+// cachedDebugState returns the state collected by the last request, if it is
+// younger than debugMinInterval. Concurrent requests wait for the same state.
+func (c *cloud) cachedDebugState(ctx context.Context) debugState {
+	c.debug.mu.Lock()
+	defer c.debug.mu.Unlock()
+
+	if time.Since(c.debug.collectedAt) >= debugMinInterval {
+		ctx, cancel := context.WithTimeout(ctx, debugTimeout)
+		defer cancel()
+
+		c.debug.state = c.debugState(ctx)
+		c.debug.collectedAt = time.Now()
+	}
+	return c.debug.state
+}
+
+// debugState collects the state of the caches and the managed resources. The
+// caches are not refreshed, only their current entries are shown. The Load
+// Balancers and routes are fetched from the API.
+func (c *cloud) debugState(ctx context.Context) debugState {
+	ctx = ratelimit.WithLowPriority(ctx)
+
+	state := debugState{
+		ServerCache:   c.serverCache.Entries(),
+		LoadBalancers: []debugLoadBalancer{},
+	}
+
+	for _, p := range c.projects {
+		state.Projects = append(state.Projects, debugProject{
+			Name:        p.name,
+			ServerCache: p.serverCache.Entries(),
+		})
+	}
+
+	if c.robotClient != nil {
+		state.Robot = c.debugRobot()
+	}
+
+	lbs, err := c.debugLoadBalancers(ctx)
+	if err != nil {
+		state.Errors = append(state.Errors, fmt.Sprintf("Load Balancers: %s", err))
+	}
+	state.LoadBalancers = append(state.LoadBalancers, lbs...)
+
+	if c.networkID != 0 && c.cfg.Route.Enabled {
+		state.Routes, err = c.debugRoutes(ctx, state.ServerCache)
+		if err != nil {
+			state.Errors = append(state.Errors, fmt.Sprintf("routes: %s", err))
+		}
+	}
+
+	return state
+}
+
+func (c *cloud) debugRobot() *debugRobot {
+	state := &debugRobot{}
+
+	if status, ok := robot.GetStatus(c.robotClient); ok {
+		state.RateLimited = status.RateLimited
+		if status.RateLimited {
+			state.NextTry = &status.NextTry
+		}
+		if !status.LastRequest.IsZero() {
+			state.LastRequest = &status.LastRequest
+		}
+		if status.LastError != nil {
+			state.LastError = status.LastError.Error()
+		}
+	}
+	if snapshot, ok := robot.GetCacheSnapshot(c.robotClient); ok {
+		state.Cache = &snapshot
+	}
+
+	return state
+}
+
+func (c *cloud) debugLoadBalancers(ctx context.Context) ([]debugLoadBalancer, error) {
+	lbs, err := c.client.LoadBalancer.AllWithOpts(ctx, hcloud.LoadBalancerListOpts{
+		ListOpts: hcloud.ListOpts{LabelSelector: hcops.LabelServiceUID},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The Service lister is only available on the leader, as the informers
+	// are started with the controllers.
+	servicesByUID := make(map[string]string)
+	if c.serviceLister != nil {
+		services, err := c.serviceLister.List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, svc := range services {
+			servicesByUID[string(svc.UID)] = svc.Namespace + "/" + svc.Name
+		}
+	}
+
+	result := make([]debugLoadBalancer, 0, len(lbs))
+	for _, lb := range lbs {
+		uid := lb.Labels[hcops.LabelServiceUID]
+		dlb := debugLoadBalancer{
+			ID:         lb.ID,
+			Name:       lb.Name,
+			ServiceUID: uid,
+			Service:    servicesByUID[uid],
+			Targets:    make([]string, 0, len(lb.Targets)),
+			Services:   make([]string, 0, len(lb.Services)),
+		}
+		for _, target := range lb.Targets {
+			switch target.Type {
+			case hcloud.LoadBalancerTargetTypeServer:
+				dlb.Targets = append(dlb.Targets, fmt.Sprintf("server:%d", target.Server.Server.ID))
+			case hcloud.LoadBalancerTargetTypeIP:
+				dlb.Targets = append(dlb.Targets, fmt.Sprintf("ip:%s", target.IP.IP))
+			case hcloud.LoadBalancerTargetTypeLabelSelector:
+				dlb.Targets = append(dlb.Targets, fmt.Sprintf("label_selector:%s", target.LabelSelector.Selector))
+			}
+		}
+		for _, svc := range lb.Services {
+			dlb.Services = append(dlb.Services, fmt.Sprintf("%s %d->%d", svc.Protocol, svc.ListenPort, svc.DestinationPort))
+		}
+		result = append(result, dlb)
+	}
+	return result, nil
+}
+
+// debugRoutes resolves the gateways of the routes with the cached servers.
+func (c *cloud) debugRoutes(ctx context.Context, servers []cache.Entry[hcloud.Server]) ([]debugRoute, error) {
+	network, _, err := c.client.Network.GetByID(ctx, c.networkID)
+	if err != nil {
+		return nil, err
+	}
+	if network == nil {
+		return nil, fmt.Errorf("Network %d not found", c.networkID)
+	}
+
+	nodesByPrivateIP := make(map[string]string, len(servers))
+	for _, server := range servers {
+		if privateNet := server.Value.PrivateNetFor(network); privateNet != nil {
+			nodesByPrivateIP[privateNet.IP.String()] = server.Name
+		}
+	}
+
+	routes := make([]debugRoute, 0, len(network.Routes))
+	for _, route := range network.Routes {
+		routes = append(routes, debugRoute{
+			Destination: route.Destination.String(),
+			Gateway:     route.Gateway.String(),
+			Node:        nodesByPrivateIP[route.Gateway.String()],
+		})
+	}
+	return routes, nil
+}
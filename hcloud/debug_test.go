@@ -0,0 +1,152 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/mocks"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/robot"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+func TestCloud_debugState(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/servers", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.ServerListResponse{Servers: []schema.Server{{
+			ID:         1,
+			Name:       "node1",
+			PrivateNet: []schema.ServerPrivateNet{{Network: 1, IP: "10.0.0.2"}},
+		}}})
+	})
+	env.Mux.HandleFunc("/networks/1", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.NetworkGetResponse{Network: schema.Network{
+			ID:      1,
+			IPRange: "10.0.0.0/8",
+			Routes: []schema.NetworkRoute{
+				{Destination: "10.244.0.0/24", Gateway: "10.0.0.2"},
+				{Destination: "10.244.1.0/24", Gateway: "10.0.0.3"},
+			},
+		}})
+	})
+	lbRequests := 0
+	env.Mux.HandleFunc("/load_balancers", func(w http.ResponseWriter, r *http.Request) {
+		lbRequests++
+		assert.Equal(t, "hcloud-ccm/service-uid", r.URL.Query().Get("label_selector"))
+		json.NewEncoder(w).Encode(schema.LoadBalancerListResponse{LoadBalancers: []schema.LoadBalancer{{
+			ID:     2,
+			Name:   "my-lb",
+			Labels: map[string]string{"hcloud-ccm/service-uid": "uid-1"},
+			Targets: []schema.LoadBalancerTarget{
+				{Type: "server", Server: &schema.LoadBalancerTargetServer{ID: 1}},
+				{Type: "ip", IP: &schema.LoadBalancerTargetIP{IP: "192.0.2.1"}},
+			},
+			Services: []schema.LoadBalancerService{{Protocol: "tcp", ListenPort: 80, DestinationPort: 30080}},
+		}}})
+	})
+
+	robotClient := &mocks.RobotClient{}
+	robotClient.On("ServerGetList").Return([]hrobotmodels.Server{{ServerNumber: 321}}, nil)
+
+	c := &cloud{
+		client:      env.Client,
+		robotClient: robot.NewRateLimitedClient(time.Minute, robot.NewCachedClient(time.Minute, time.Minute, robotClient)),
+		serverCache: env.ServerCache,
+		networkID:   1,
+	}
+	c.cfg.Route.Enabled = true
+
+	_, err := env.ServerCache.All(t.Context())
+	require.NoError(t, err)
+	_, err = c.robotClient.ServerGetList()
+	require.NoError(t, err)
+
+	state := c.debugState(t.Context())
+	assert.Empty(t, state.Errors)
+
+	if assert.Len(t, state.ServerCache, 1) {
+		assert.Equal(t, int64(1), state.ServerCache[0].ID)
+		assert.Equal(t, "node1", state.ServerCache[0].Name)
+	}
+
+	if assert.NotNil(t, state.Robot) {
+		assert.False(t, state.Robot.RateLimited)
+		assert.NotNil(t, state.Robot.LastRequest)
+		if assert.NotNil(t, state.Robot.Cache) {
+			assert.Equal(t, []hrobotmodels.Server{{ServerNumber: 321}}, state.Robot.Cache.Servers)
+		}
+	}
+
+	assert.Equal(t, []debugLoadBalancer{{
+		ID:         2,
+		Name:       "my-lb",
+		ServiceUID: "uid-1",
+		Targets:    []string{"server:1", "ip:192.0.2.1"},
+		Services:   []string{"tcp 80->30080"},
+	}}, state.LoadBalancers)
+
+	assert.Equal(t, []debugRoute{
+		{Destination: "10.244.0.0/24", Gateway: "10.0.0.2", Node: "node1"},
+		{Destination: "10.244.1.0/24", Gateway: "10.0.0.3"},
+	}, state.Routes)
+
+	// The cached servers of additional projects are listed separately.
+	teamEnv := newTestEnv()
+	defer teamEnv.Teardown()
+	handleServers(teamEnv, schema.Server{ID: 4, Name: "team"})
+	teamCache := cache.NewServerCache(teamEnv.Client, cache.ModeAll, time.Minute)
+	_, err = teamCache.All(t.Context())
+	require.NoError(t, err)
+	c.projects = []*project{{name: "team-a", client: teamEnv.Client, serverCache: teamCache}}
+
+	state = c.debugState(t.Context())
+	if assert.Len(t, state.Projects, 1) {
+		assert.Equal(t, "team-a", state.Projects[0].Name)
+		if assert.Len(t, state.Projects[0].ServerCache, 1) {
+			assert.Equal(t, "team", state.Projects[0].ServerCache[0].Name)
+		}
+	}
+
+	// The state including the cached servers can be encoded.
+	lbRequests = 0
+	for range 2 {
+		rec := httptest.NewRecorder()
+		c.debugHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/hccm", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"serverCache"`)
+	}
+	// The second request is served from the previously collected state.
+	assert.Equal(t, 1, lbRequests)
+}
+
+func TestCloud_debugStateDoesNotRefreshCache(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/servers", func(_ http.ResponseWriter, _ *http.Request) {
+		t.Error("the server cache must not be refreshed")
+	})
+	env.Mux.HandleFunc("/networks/1", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.NetworkGetResponse{Network: schema.Network{ID: 1, IPRange: "10.0.0.0/8"}})
+	})
+	env.Mux.HandleFunc("/load_balancers", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.LoadBalancerListResponse{})
+	})
+
+	c := &cloud{client: env.Client, serverCache: env.ServerCache, networkID: 1}
+	c.cfg.Route.Enabled = true
+
+	state := c.debugState(t.Context())
+	assert.Empty(t, state.Errors)
+	assert.Empty(t, state.ServerCache)
+	assert.Empty(t, state.Routes)
+}
@@ -0,0 +1,102 @@
+package hcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	hrobotmodels "github.com/syself/hrobot-go/models"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/health"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/ratelimit"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/robot"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// healthCheckInterval is the interval of the health checks. Each run costs one
+// request per check to the Hetzner Cloud API, but none to the Robot API.
+const healthCheckInterval = 30 * time.Second
+
+func (c *cloud) newHealthChecker() *health.Checker {
+	checker := health.NewChecker(healthCheckInterval)
+	checker.Add("hcloud", c.checkHCloudAPI)
+	for _, p := range c.projects {
+		checker.Add("hcloud-"+p.name, func(ctx context.Context) health.Result {
+			return checkHCloudClient(ctx, p.client)
+		})
+	}
+	if c.networkID != 0 {
+		checker.Add("network", c.checkNetwork)
+	}
+	if c.robotClient != nil {
+		checker.Add("robot", c.checkRobotAPI)
+	}
+	return checker
+}
+
+// checkHCloudAPI checks that the Hetzner Cloud API is reachable and the token
+// is valid.
+func (c *cloud) checkHCloudAPI(ctx context.Context) health.Result {
+	return checkHCloudClient(ctx, c.client)
+}
+
+// checkHCloudClient checks that the Hetzner Cloud API is reachable and the
+// token of client is valid. It is used for the default and additional projects.
+func checkHCloudClient(ctx context.Context, client *hcloud.Client) health.Result {
+	ctx = ratelimit.WithLowPriority(ctx)
+	_, _, err := client.Location.List(ctx, hcloud.LocationListOpts{ListOpts: hcloud.ListOpts{PerPage: 1}})
+	switch {
+	case errors.Is(err, ratelimit.ErrThrottled):
+		return health.Result{Status: health.StatusDegraded, Message: "the rate limit budget is low"}
+	case hcloud.IsError(err, hcloud.ErrorCodeUnauthorized):
+		return health.Result{Status: health.StatusFailed, Message: "the token is invalid"}
+	case hcloud.IsError(err, hcloud.ErrorCodeRateLimitExceeded):
+		return health.Result{Status: health.StatusDegraded, Message: "the rate limit is exceeded"}
+	case err != nil:
+		return health.Result{Status: health.StatusFailed, Message: fmt.Sprintf("the API is not reachable: %s", err)}
+	}
+	return health.Result{Status: health.StatusOK}
+}
+
+// checkNetwork checks that the configured Network still exists.
+func (c *cloud) checkNetwork(ctx context.Context) health.Result {
+	ctx = ratelimit.WithLowPriority(ctx)
+	network, _, err := c.client.Network.GetByID(ctx, c.networkID)
+	switch {
+	case errors.Is(err, ratelimit.ErrThrottled):
+		return health.Result{Status: health.StatusDegraded, Message: "the rate limit budget is low"}
+	case err != nil:
+		return health.Result{Status: health.StatusFailed, Message: err.Error()}
+	case network == nil:
+		return health.Result{Status: health.StatusFailed, Message: fmt.Sprintf("Network %d not found", c.networkID)}
+	}
+	return health.Result{Status: health.StatusOK, Message: fmt.Sprintf("Network %s (%d)", network.Name, network.ID)}
+}
+
+// checkRobotAPI reports the state of the Robot API as observed by the
+// requests of the controllers, as the hourly rate limit of the Robot API is
+// too low for periodic checks.
+func (c *cloud) checkRobotAPI(_ context.Context) health.Result {
+	status, ok := robot.GetStatus(c.robotClient)
+	if !ok {
+		return health.Result{Status: health.StatusOK, Message: "the status is unknown"}
+	}
+
+	var apiErr hrobotmodels.Error
+	switch {
+	case status.RateLimited:
+		return health.Result{
+			Status:  health.StatusDegraded,
+			Message: fmt.Sprintf("the rate limit is exceeded, next try at %s", status.NextTry.Format(time.RFC3339)),
+		}
+	case status.LastRequest.IsZero():
+		return health.Result{Status: health.StatusOK, Message: "no requests were sent yet"}
+	case hrobotmodels.IsError(status.LastError, hrobotmodels.ErrorCodeUnauthorized):
+		return health.Result{Status: health.StatusFailed, Message: "the credentials are invalid"}
+	case status.LastError != nil && !errors.As(status.LastError, &apiErr):
+		// Other API errors, e.g. a server not found, show that the API is reachable.
+		return health.Result{Status: health.StatusFailed, Message: fmt.Sprintf("the API is not reachable: %s", status.LastError)}
+	}
+	return health.Result{Status: health.StatusOK}
+}
@@ -0,0 +1,125 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/health"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/mocks"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/robot"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+func TestCloud_checkHCloudAPI(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	authorized := true
+	env.Mux.HandleFunc("/locations", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !authorized {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(schema.ErrorResponse{Error: schema.Error{Code: "unauthorized", Message: "unable to authenticate"}})
+			return
+		}
+		json.NewEncoder(w).Encode(schema.LocationListResponse{Locations: []schema.Location{}})
+	})
+
+	c := &cloud{client: env.Client}
+	assert.Equal(t, health.Result{Status: health.StatusOK}, c.checkHCloudAPI(t.Context()))
+
+	authorized = false
+	assert.Equal(t, health.Result{Status: health.StatusFailed, Message: "the token is invalid"}, c.checkHCloudAPI(t.Context()))
+}
+
+func TestCloud_checkNetwork(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+
+	env.Mux.HandleFunc("/networks/1", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.NetworkGetResponse{Network: schema.Network{ID: 1, Name: "my-network", IPRange: "10.0.0.0/8"}})
+	})
+	env.Mux.HandleFunc("/networks/2", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(schema.ErrorResponse{Error: schema.Error{Code: "not_found"}})
+	})
+
+	c := &cloud{client: env.Client, networkID: 1}
+	assert.Equal(t, health.Result{Status: health.StatusOK, Message: "Network my-network (1)"}, c.checkNetwork(t.Context()))
+
+	c.networkID = 2
+	assert.Equal(t, health.Result{Status: health.StatusFailed, Message: "Network 2 not found"}, c.checkNetwork(t.Context()))
+}
+
+func TestCloud_checkRobotAPI(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want health.Status
+	}{
+		{name: "success", err: nil, want: health.StatusOK},
+		{name: "not found", err: hrobotmodels.Error{Code: hrobotmodels.ErrorCodeServerNotFound}, want: health.StatusOK},
+		{name: "unauthorized", err: hrobotmodels.Error{Code: hrobotmodels.ErrorCodeUnauthorized}, want: health.StatusFailed},
+		{name: "rate limit", err: hrobotmodels.Error{Code: hrobotmodels.ErrorCodeRateLimitExceeded}, want: health.StatusDegraded},
+		{name: "unreachable", err: errors.New("connection refused"), want: health.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			robotClient := &mocks.RobotClient{}
+			robotClient.On("ServerGet").Return(nil, tt.err)
+
+			c := &cloud{robotClient: robot.NewRateLimitedClient(time.Minute, robotClient)}
+			assert.Equal(t, health.Result{Status: health.StatusOK, Message: "no requests were sent yet"}, c.checkRobotAPI(t.Context()))
+
+			_, _ = c.robotClient.ServerGet(1)
+			assert.Equal(t, tt.want, c.checkRobotAPI(t.Context()).Status)
+		})
+	}
+}
+
+func TestCloud_newHealthCheckerProjects(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+	teamEnv := newTestEnv()
+	defer teamEnv.Teardown()
+
+	env.Mux.HandleFunc("/locations", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.LocationListResponse{Locations: []schema.Location{}})
+	})
+	teamEnv.Mux.HandleFunc("/locations", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(schema.ErrorResponse{Error: schema.Error{Code: "unauthorized", Message: "unable to authenticate"}})
+	})
+
+	c := &cloud{
+		client:   env.Client,
+		projects: []*project{{name: "team-a", client: teamEnv.Client}},
+	}
+	checker := c.newHealthChecker()
+	stop := make(chan struct{})
+	defer close(stop)
+	go checker.Run(stop)
+
+	assert.EventuallyWithT(t, func(t *assert.CollectT) {
+		rec := httptest.NewRecorder()
+		checker.Handlers()["/readyz"].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var resp struct {
+			Checks map[string]health.Result `json:"checks"`
+		}
+		assert.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		assert.Equal(t, health.StatusOK, resp.Checks["hcloud"].Status)
+		assert.Equal(t, health.StatusFailed, resp.Checks["hcloud-team-a"].Status)
+		assert.Equal(t, "the token is invalid", resp.Checks["hcloud-team-a"].Message)
+	}, time.Second, 10*time.Millisecond)
+}
@@ -21,25 +21,32 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"strconv"
 
 	hrobot "github.com/syself/hrobot-go"
 	hrobotmodels "github.com/syself/hrobot-go/models"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
 
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/agent"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
-	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/legacydatacenter"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/health"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/providerid"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/topology"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/utils"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
 
 const (
 	ProvidedBy              = "instance.hetzner.cloud/provided-by"
+	InstanceLabelPrefix     = "instance.hetzner.cloud/"
+	ProjectLabel            = "instance.hetzner.cloud/project"
 	MisconfiguredInternalIP = "MisconfiguredInternalIP"
 	instancesV2Subsystem    = "instances_v2"
 )
@@ -48,9 +55,13 @@ type instances struct {
 	client      *hcloud.Client
 	robotClient hrobot.RobotClient
 	serverCache *cache.Cache[hcloud.Server]
-	recorder    record.EventRecorder
-	networkID   int64
-	cfg         config.HCCMConfiguration
+	// floatingIPCache is only used for the default project, the additional
+	// projects have their own cache.
+	floatingIPCache *cache.Cache[hcloud.FloatingIP]
+	projects        []*project
+	recorder        record.EventRecorder
+	networkID       int64
+	cfg             config.HCCMConfiguration
 }
 
 var (
@@ -62,6 +73,8 @@ func newInstances(
 	client *hcloud.Client,
 	robotClient hrobot.RobotClient,
 	serverCache *cache.Cache[hcloud.Server],
+	floatingIPCache *cache.Cache[hcloud.FloatingIP],
+	projects []*project,
 	recorder record.EventRecorder,
 	networkID int64,
 	cfg config.HCCMConfiguration,
@@ -70,6 +83,8 @@ func newInstances(
 		client,
 		robotClient,
 		serverCache,
+		floatingIPCache,
+		projects,
 		recorder,
 		networkID,
 		cfg,
@@ -93,7 +108,17 @@ func (i *instances) lookupServer(
 		}
 
 		if isCloudServer {
-			server, err := i.serverCache.ByID(ctx, serverID)
+			projectName, _ := providerid.ToProject(node.Spec.ProviderID)
+			serverCache := i.serverCache
+			if projectName != "" {
+				p := projectByName(i.projects, projectName)
+				if p == nil {
+					return nil, fmt.Errorf("unknown project %q of provider id %q", projectName, node.Spec.ProviderID)
+				}
+				serverCache = p.serverCache
+			}
+
+			server, err := serverCache.ByID(ctx, serverID)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get hcloud server \"%d\": %w", serverID, err)
 			}
@@ -102,7 +127,7 @@ func (i *instances) lookupServer(
 				return nil, nil
 			}
 
-			return hcloudServer{server}, nil
+			return hcloudServer{Server: server, project: projectName}, nil
 		}
 
 		if i.robotClient == nil {
@@ -120,11 +145,21 @@ func (i *instances) lookupServer(
 		return robotServer{server, i.robotClient, i.recorder}, nil
 	}
 
+	if i.cfg.Instance.NodeAgentEnabled {
+		serverID, ok, err := agent.ServerID(node.Annotations)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return i.cloudServerByID(ctx, node, serverID)
+		}
+	}
+
 	// If the node has no provider ID we try to find the server by name from
 	// both sources. In case we find two servers, we return an error.
-	cloudServer, err := i.serverCache.ByName(ctx, node.Name)
+	cloudServer, err := i.cloudServerByName(ctx, node)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get hcloud server %q: %w", node.Name, err)
+		return nil, err
 	}
 
 	var hrobotServer *hrobotmodels.Server
@@ -135,7 +170,7 @@ func (i *instances) lookupServer(
 		}
 	}
 
-	if cloudServer != nil && hrobotServer != nil {
+	if cloudServer.Server != nil && hrobotServer != nil {
 		utils.WarnEventLogf(
 			i.recorder,
 			node,
@@ -147,8 +182,8 @@ func (i *instances) lookupServer(
 	}
 
 	switch {
-	case cloudServer != nil:
-		return hcloudServer{cloudServer}, nil
+	case cloudServer.Server != nil:
+		return cloudServer, nil
 	case hrobotServer != nil:
 		return robotServer{hrobotServer, i.robotClient, i.recorder}, nil
 	default:
@@ -157,9 +192,123 @@ func (i *instances) lookupServer(
 	}
 }
 
-func (i *instances) InstanceExists(ctx context.Context, node *corev1.Node) (bool, error) {
+// cloudServerByID returns the cloud server with the ID published by the node
+// agent. All projects are searched, as server IDs are unique across projects.
+// The server is only returned, if it has one of the addresses of node.
+func (i *instances) cloudServerByID(ctx context.Context, node *corev1.Node, serverID int64) (genericServer, error) {
+	result := hcloudServer{}
+
+	server, err := i.serverCache.ByID(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hcloud server \"%d\": %w", serverID, err)
+	}
+	if server != nil {
+		result = hcloudServer{Server: server}
+	}
+
+	for _, p := range i.projects {
+		if result.Server != nil {
+			break
+		}
+		server, err := p.serverCache.ByID(ctx, serverID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get hcloud server \"%d\" in project %s: %w", serverID, p.name, err)
+		}
+		if server != nil {
+			result = hcloudServer{Server: server, project: p.name}
+		}
+	}
+
+	if result.Server == nil {
+		return nil, nil
+	}
+
+	// Anyone who can patch the node can set the annotation, while the addresses
+	// in the node status are reported by the kubelet.
+	if !nodeHasServerAddress(node, result.Server) {
+		utils.WarnEventLogf(
+			i.recorder,
+			node,
+			"InstanceLookupFailed",
+			"Node %s was not associated with server %d published by the node agent, as none of the addresses of the Node belongs to the server",
+			node.Name,
+			serverID,
+		)
+		return nil, fmt.Errorf("addresses of node %q do not match hcloud server \"%d\" published by the node agent", node.Name, serverID)
+	}
+	return result, nil
+}
+
+// nodeHasServerAddress returns true, if one of the internal or external
+// addresses of node is a public or private IP of server.
+func nodeHasServerAddress(node *corev1.Node, server *hcloud.Server) bool {
+	for _, address := range node.Status.Addresses {
+		if address.Type != corev1.NodeInternalIP && address.Type != corev1.NodeExternalIP {
+			continue
+		}
+		ip := net.ParseIP(address.Address)
+		if ip == nil {
+			continue
+		}
+
+		if !server.PublicNet.IPv4.IsUnspecified() && server.PublicNet.IPv4.IP.Equal(ip) {
+			return true
+		}
+		if !server.PublicNet.IPv6.IsUnspecified() && server.PublicNet.IPv6.Network != nil && server.PublicNet.IPv6.Network.Contains(ip) {
+			return true
+		}
+		for _, privateNet := range server.PrivateNet {
+			if privateNet.IP.Equal(ip) {
+				return true
+			}
+			for _, aliasIP := range privateNet.Aliases {
+				if aliasIP.Equal(ip) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// cloudServerByName looks up the server of node by name in the default project
+// and all additional projects. Server names are only unique within a project,
+// so it returns an error if more than one project has a matching server.
+func (i *instances) cloudServerByName(ctx context.Context, node *corev1.Node) (hcloudServer, error) {
+	server, err := i.serverCache.ByName(ctx, node.Name)
+	if err != nil {
+		return hcloudServer{}, fmt.Errorf("failed to get hcloud server %q: %w", node.Name, err)
+	}
+	result := hcloudServer{Server: server}
+
+	for _, p := range i.projects {
+		server, err := p.serverCache.ByName(ctx, node.Name)
+		if err != nil {
+			return hcloudServer{}, fmt.Errorf("failed to get hcloud server %q in project %s: %w", node.Name, p.name, err)
+		}
+		if server == nil {
+			continue
+		}
+		if result.Server != nil {
+			utils.WarnEventLogf(
+				i.recorder,
+				node,
+				"InstanceLookupFailed",
+				"Node %s could not be uniquely associated with a Cloud server, as a server with this name exists in multiple projects",
+				node.Name,
+			)
+			return hcloudServer{}, fmt.Errorf("found cloud servers in multiple projects for name %q", node.Name)
+		}
+		result = hcloudServer{Server: server, project: p.name}
+	}
+	return result, nil
+}
+
+func (i *instances) InstanceExists(ctx context.Context, node *corev1.Node) (_ bool, err error) {
 	const op = "hcloud/instancesv2.InstanceExists"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op, tracing.Node(node)...)
+	defer endSpan(&err)
 	klog.V(4).InfoS("InstanceExists called", "node", node.Name, "providerID", node.Spec.ProviderID)
 
 	server, err := i.lookupServer(ctx, node)
@@ -170,9 +319,11 @@ func (i *instances) InstanceExists(ctx context.Context, node *corev1.Node) (bool
 	return server != nil, nil
 }
 
-func (i *instances) InstanceShutdown(ctx context.Context, node *corev1.Node) (bool, error) {
+func (i *instances) InstanceShutdown(ctx context.Context, node *corev1.Node) (_ bool, err error) {
 	const op = "hcloud/instancesv2.InstanceShutdown"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op, tracing.Node(node)...)
+	defer endSpan(&err)
 	klog.V(4).InfoS("InstanceShutdown called", "node", node.Name, "providerID", node.Spec.ProviderID)
 
 	server, err := i.lookupServer(ctx, node)
@@ -195,9 +346,12 @@ func (i *instances) InstanceShutdown(ctx context.Context, node *corev1.Node) (bo
 	return isShutdown, nil
 }
 
-func (i *instances) InstanceMetadata(ctx context.Context, node *corev1.Node) (*cloudprovider.InstanceMetadata, error) {
+func (i *instances) InstanceMetadata(ctx context.Context, node *corev1.Node) (_ *cloudprovider.InstanceMetadata, err error) {
 	const op = "hcloud/instancesv2.InstanceMetadata"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op, tracing.Node(node)...)
+	defer endSpan(&err)
+	defer health.ObserveReconcile(health.Instances, &err)
 	klog.V(4).InfoS("InstanceMetadata called", "node", node.Name, "providerID", node.Spec.ProviderID)
 
 	server, err := i.lookupServer(ctx, node)
@@ -212,6 +366,14 @@ func (i *instances) InstanceMetadata(ctx context.Context, node *corev1.Node) (*c
 		)
 	}
 
+	if cloudServer, ok := server.(hcloudServer); ok && usesFloatingIPs(addressPolicyFor(config.AddressProviderCloud, node, i.cfg)) {
+		cloudServer.floatingIPs, err = i.floatingIPs(ctx, cloudServer)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		server = cloudServer
+	}
+
 	metadata, err := server.Metadata(i.networkID, node, i.cfg)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -220,123 +382,49 @@ func (i *instances) InstanceMetadata(ctx context.Context, node *corev1.Node) (*c
 	return metadata, nil
 }
 
-func hcloudNodeAddresses(
-	networkID int64,
-	server *hcloud.Server,
-	cfg config.HCCMConfiguration,
-) []corev1.NodeAddress {
-	var addresses []corev1.NodeAddress
-	addresses = append(
-		addresses,
-		corev1.NodeAddress{Type: corev1.NodeHostName, Address: server.Name},
-	)
-
-	dualStack := cfg.Instance.AddressFamily == config.AddressFamilyDualStack
-	ipv4 := cfg.Instance.AddressFamily == config.AddressFamilyIPv4 || dualStack
-	ipv6 := cfg.Instance.AddressFamily == config.AddressFamilyIPv6 || dualStack
-
-	if ipv4 && !server.PublicNet.IPv4.IsUnspecified() {
-		addresses = append(
-			addresses,
-			corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: server.PublicNet.IPv4.IP.String()},
-		)
+// floatingIPs returns the Floating IPs assigned to server. The server only
+// contains their IDs. The Floating IPs are listed once for all nodes and cached
+// for a short time, as they are resolved on every sync of the node addresses.
+func (i *instances) floatingIPs(ctx context.Context, server hcloudServer) ([]*hcloud.FloatingIP, error) {
+	floatingIPCache := i.floatingIPCache
+	if p := projectByName(i.projects, server.project); p != nil {
+		floatingIPCache = p.floatingIPCache
 	}
 
-	if ipv6 && !server.PublicNet.IPv6.IsUnspecified() {
-		// For a given IPv6 network of 2001:db8:1234::/64, the instance address is 2001:db8:1234::1
-		hostAddress := server.PublicNet.IPv6.IP
-		hostAddress[len(hostAddress)-1] |= 0x01
-
-		addresses = append(
-			addresses,
-			corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: hostAddress.String()},
-		)
-	}
-
-	// Add private IP from network if network is specified
-	if networkID > 0 {
-		for _, privateNet := range server.PrivateNet {
-			if privateNet.Network.ID == networkID {
-				addresses = append(
-					addresses,
-					corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: privateNet.IP.String()},
-				)
-			}
+	floatingIPs := make([]*hcloud.FloatingIP, 0, len(server.PublicNet.FloatingIPs))
+	for _, f := range server.PublicNet.FloatingIPs {
+		floatingIP, err := floatingIPCache.ByID(ctx, f.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get floating ip \"%d\": %w", f.ID, err)
 		}
+		// The Floating IP could have been unassigned in the meantime.
+		if floatingIP == nil || floatingIP.Server == nil || floatingIP.Server.ID != server.ID {
+			continue
+		}
+		floatingIPs = append(floatingIPs, floatingIP)
 	}
+	return floatingIPs, nil
+}
 
-	return addresses
+// hcloudNodeAddresses returns the addresses of a Hetzner Cloud Server according
+// to the default address policy.
+func hcloudNodeAddresses(
+	networkID int64,
+	server *hcloud.Server,
+	cfg config.HCCMConfiguration,
+) []corev1.NodeAddress {
+	return cloudNodeAddresses(defaultAddressPolicy(config.AddressProviderCloud, cfg), networkID, server, nil)
 }
 
+// robotNodeAddresses returns the addresses of a Robot server according to the
+// default address policy.
 func robotNodeAddresses(
 	server *hrobotmodels.Server,
 	node *corev1.Node,
 	cfg config.HCCMConfiguration,
 	recorder record.EventRecorder,
 ) []corev1.NodeAddress {
-	var addresses []corev1.NodeAddress
-	addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeHostName, Address: server.Name})
-
-	dualStack := cfg.Instance.AddressFamily == config.AddressFamilyDualStack
-	ipv4 := cfg.Instance.AddressFamily == config.AddressFamilyIPv4 || dualStack
-	ipv6 := cfg.Instance.AddressFamily == config.AddressFamilyIPv6 || dualStack
-
-	if ipv6 {
-		// For a given IPv6 network of 2a01:f48:111:4221::, the instance address is 2a01:f48:111:4221::1
-		hostAddress := server.ServerIPv6Net + "1"
-		addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: hostAddress})
-	}
-
-	if ipv4 {
-		addresses = append(addresses, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: server.ServerIP})
-	}
-
-	if cfg.Robot.ForwardInternalIPs {
-	OUTER:
-		for _, currentAddress := range node.Status.Addresses {
-			if currentAddress.Type != corev1.NodeInternalIP {
-				continue
-			}
-
-			ip := net.ParseIP(currentAddress.Address)
-			isIPv4 := ip.To4() != nil
-
-			var warnMsg string
-			if isIPv4 && ipv6 && !dualStack {
-				warnMsg = fmt.Sprintf(
-					"Configured InternalIP is IPv4 even though IPv6 only is configured. As a result, %s is not added as an InternalIP",
-					currentAddress.Address,
-				)
-			} else if !isIPv4 && ipv4 && !dualStack {
-				warnMsg = fmt.Sprintf(
-					"Configured InternalIP is IPv6 even though IPv4 only is configured. As a result, %s is not added as an InternalIP",
-					currentAddress.Address,
-				)
-			}
-
-			if warnMsg != "" {
-				utils.WarnEventLogf(recorder, node, MisconfiguredInternalIP, "%s", warnMsg)
-				continue
-			}
-
-			for _, address := range addresses {
-				if currentAddress.Address == address.Address {
-					utils.WarnEventLogf(
-						recorder,
-						node,
-						MisconfiguredInternalIP,
-						"Configured InternalIP already exists as an ExternalIP. As a result, %s is not added as an InternalIP",
-						currentAddress.Address,
-					)
-					continue OUTER
-				}
-			}
-
-			addresses = append(addresses, currentAddress)
-		}
-	}
-
-	return addresses
+	return robotNodeAddressesForPolicy(defaultAddressPolicy(config.AddressProviderRobot, cfg), server, node, recorder)
 }
 
 type genericServer interface {
@@ -350,32 +438,64 @@ type genericServer interface {
 
 type hcloudServer struct {
 	*hcloud.Server
+	// project is the name of the additional project of the server. It is empty
+	// for the default project.
+	project string
+	// floatingIPs are only resolved if the address policy of the node uses
+	// Floating IPs.
+	floatingIPs []*hcloud.FloatingIP
 }
 
 func (s hcloudServer) IsShutdown() (bool, error) {
 	return s.Status == hcloud.ServerStatusOff, nil
 }
 
-func (s hcloudServer) Metadata(networkID int64, _ *corev1.Node, cfg config.HCCMConfiguration) (*cloudprovider.InstanceMetadata, error) {
+func (s hcloudServer) Metadata(networkID int64, node *corev1.Node, cfg config.HCCMConfiguration) (*cloudprovider.InstanceMetadata, error) {
+	mapper := topologyMapper(cfg)
+	placement := topology.CloudPlacement(s.Location.Name, string(s.Location.NetworkZone))
+
 	metadata := &cloudprovider.InstanceMetadata{
-		ProviderID:    providerid.FromCloudServerID(s.ID),
+		ProviderID:    providerid.FromCloudServerIDInProject(s.project, s.ID),
 		InstanceType:  s.ServerType.Name,
-		NodeAddresses: hcloudNodeAddresses(networkID, s.Server, cfg),
-		Region:        s.Location.Name,
+		NodeAddresses: cloudNodeAddresses(addressPolicyFor(config.AddressProviderCloud, node, cfg), networkID, s.Server, s.floatingIPs),
+		Region:        mapper.Region(placement),
 		AdditionalLabels: map[string]string{
 			ProvidedBy: "cloud",
 		},
 	}
+	if s.project != "" {
+		metadata.AdditionalLabels[ProjectLabel] = s.project
+	}
+	addInstanceLabels(metadata.AdditionalLabels, cfg.Instance.Labels, s.labelValues())
 
 	// By default, we continue to configure a zone label. The user
 	// can configure this behavior. We can drop this entirely in a v2.
 	if cfg.Instance.ZoneLabelEnabled {
-		metadata.Zone = legacydatacenter.NameFromLocation(s.Location.Name)
+		metadata.Zone = mapper.Zone(placement)
 	}
 
 	return metadata, nil
 }
 
+func (s hcloudServer) labelValues() map[config.InstanceLabel]string {
+	values := map[config.InstanceLabel]string{
+		config.InstanceLabelCPUType:      string(s.ServerType.CPUType),
+		config.InstanceLabelArchitecture: string(s.ServerType.Architecture),
+		config.InstanceLabelCores:        strconv.Itoa(s.ServerType.Cores),
+		config.InstanceLabelMemory:       strconv.FormatFloat(float64(s.ServerType.Memory), 'f', -1, 32),
+		config.InstanceLabelDisk:         strconv.Itoa(s.ServerType.Disk),
+		config.InstanceLabelNetworkZone:  string(s.Location.NetworkZone),
+	}
+	if s.PlacementGroup != nil {
+		values[config.InstanceLabelPlacementGroup] = s.PlacementGroup.Name
+	}
+	// The image is not set if it was deleted after the server was created.
+	if s.Image != nil {
+		values[config.InstanceLabelOSFlavor] = s.Image.OSFlavor
+	}
+	return values
+}
+
 type robotServer struct {
 	*hrobotmodels.Server
 	robotClient hrobot.RobotClient
@@ -394,14 +514,53 @@ func (s robotServer) IsShutdown() (bool, error) {
 }
 
 func (s robotServer) Metadata(_ int64, node *corev1.Node, cfg config.HCCMConfiguration) (*cloudprovider.InstanceMetadata, error) {
-	return &cloudprovider.InstanceMetadata{
+	mapper := topologyMapper(cfg)
+	placement := topology.RobotPlacement(s.Dc)
+
+	metadata := &cloudprovider.InstanceMetadata{
 		ProviderID:    providerid.FromRobotServerNumber(s.ServerNumber),
 		InstanceType:  getInstanceTypeOfRobotServer(s.Server),
-		NodeAddresses: robotNodeAddresses(s.Server, node, cfg, s.recorder),
-		Zone:          getZoneOfRobotServer(s.Server),
-		Region:        getRegionOfRobotServer(s.Server),
+		NodeAddresses: robotNodeAddressesForPolicy(addressPolicyFor(config.AddressProviderRobot, node, cfg), s.Server, node, s.recorder),
+		Zone:          mapper.Zone(placement),
+		Region:        mapper.Region(placement),
 		AdditionalLabels: map[string]string{
 			ProvidedBy: "robot",
 		},
-	}, nil
+	}
+	addInstanceLabels(metadata.AdditionalLabels, cfg.Instance.Labels, s.labelValues())
+
+	return metadata, nil
+}
+
+func topologyMapper(cfg config.HCCMConfiguration) topology.Mapper {
+	return topology.Mapper{
+		RegionLevel: cfg.Instance.Topology.Region,
+		ZoneLevel:   cfg.Instance.Topology.Zone,
+	}
+}
+
+// labelValues returns the product and datacenter of the server. The Robot API
+// does not expose the rack of a server.
+func (s robotServer) labelValues() map[config.InstanceLabel]string {
+	return map[config.InstanceLabel]string{
+		config.InstanceLabelProduct:    getInstanceTypeOfRobotServer(s.Server),
+		config.InstanceLabelDatacenter: topology.RobotPlacement(s.Dc).Datacenter,
+	}
+}
+
+// addInstanceLabels adds the enabled labels to labels. Labels without a value
+// for the server, e.g. the placement group of a server which is in none, are
+// skipped, as are values which are not valid label values.
+func addInstanceLabels(labels map[string]string, enabled []config.InstanceLabel, values map[config.InstanceLabel]string) {
+	for _, label := range enabled {
+		value := values[label]
+		if value == "" {
+			continue
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			klog.InfoS("skipping invalid node label value", "label", InstanceLabelPrefix+string(label), "value", value, "errs", errs)
+			continue
+		}
+		labels[InstanceLabelPrefix+string(label)] = value
+	}
 }
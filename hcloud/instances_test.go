@@ -26,12 +26,15 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	hrobotmodels "github.com/syself/hrobot-go/models"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	cloudprovider "k8s.io/cloud-provider"
 
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/agent"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/topology"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
 )
@@ -91,7 +94,7 @@ func TestInstances_InstanceExists(t *testing.T) {
 		})
 	})
 
-	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.Recorder, 0, env.Cfg)
+	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.FloatingIPCache, nil, env.Recorder, 0, env.Cfg)
 
 	tests := []struct {
 		name     string
@@ -219,7 +222,7 @@ func TestInstances_InstanceShutdown(t *testing.T) {
 		})
 	})
 
-	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.Recorder, 0, env.Cfg)
+	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.FloatingIPCache, nil, env.Recorder, 0, env.Cfg)
 	env.Mux.HandleFunc("/robot/server/3", func(w http.ResponseWriter, _ *http.Request) {
 		json.NewEncoder(w).Encode(hrobotmodels.ServerResponse{
 			Server: hrobotmodels.Server{
@@ -357,7 +360,7 @@ func TestInstances_InstanceMetadata(t *testing.T) {
 		})
 	})
 
-	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.Recorder, 0, env.Cfg)
+	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.FloatingIPCache, nil, env.Recorder, 0, env.Cfg)
 
 	metadata, err := instances.InstanceMetadata(context.TODO(), &corev1.Node{
 		Spec: corev1.NodeSpec{ProviderID: "hcloud://1"},
@@ -401,7 +404,7 @@ func TestInstances_InstanceMetadataRobotServer(t *testing.T) {
 		})
 	})
 
-	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.Recorder, 0, env.Cfg)
+	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.FloatingIPCache, nil, env.Recorder, 0, env.Cfg)
 
 	metadata, err := instances.InstanceMetadata(context.TODO(), &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -432,6 +435,152 @@ func TestInstances_InstanceMetadataRobotServer(t *testing.T) {
 	}
 }
 
+func TestInstances_InstanceMetadataLabels(t *testing.T) {
+	cfg := config.HCCMConfiguration{
+		Instance: config.InstanceConfiguration{
+			AddressFamily: config.AddressFamilyIPv4,
+			Labels:        config.InstanceLabels,
+		},
+	}
+
+	t.Run("cloud", func(t *testing.T) {
+		server := hcloudServer{Server: &hcloud.Server{
+			Name: "foobar",
+			ServerType: &hcloud.ServerType{
+				Name:         "cax11",
+				Cores:        2,
+				Memory:       0.5,
+				Disk:         40,
+				CPUType:      hcloud.CPUTypeShared,
+				Architecture: hcloud.ArchitectureARM,
+			},
+			Location:       &hcloud.Location{Name: "fsn1", NetworkZone: hcloud.NetworkZoneEUCentral},
+			PlacementGroup: &hcloud.PlacementGroup{Name: "workers"},
+			Image:          &hcloud.Image{OSFlavor: "ubuntu"},
+		}}
+
+		metadata, err := server.Metadata(0, &corev1.Node{}, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"instance.hetzner.cloud/provided-by":     "cloud",
+			"instance.hetzner.cloud/cpu-type":        "shared",
+			"instance.hetzner.cloud/architecture":    "arm",
+			"instance.hetzner.cloud/cores":           "2",
+			"instance.hetzner.cloud/memory-gb":       "0.5",
+			"instance.hetzner.cloud/disk-gb":         "40",
+			"instance.hetzner.cloud/network-zone":    "eu-central",
+			"instance.hetzner.cloud/placement-group": "workers",
+			"instance.hetzner.cloud/os-flavor":       "ubuntu",
+		}, metadata.AdditionalLabels)
+
+		// Labels without a value are skipped.
+		server.PlacementGroup = nil
+		server.Image = nil
+		metadata, err = server.Metadata(0, &corev1.Node{}, config.HCCMConfiguration{
+			Instance: config.InstanceConfiguration{
+				AddressFamily: config.AddressFamilyIPv4,
+				Labels:        []config.InstanceLabel{config.InstanceLabelPlacementGroup, config.InstanceLabelOSFlavor, config.InstanceLabelCores},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"instance.hetzner.cloud/provided-by": "cloud",
+			"instance.hetzner.cloud/cores":       "2",
+		}, metadata.AdditionalLabels)
+	})
+
+	t.Run("robot", func(t *testing.T) {
+		server := robotServer{Server: &hrobotmodels.Server{
+			ServerIP:     "233.252.0.123",
+			ServerNumber: 321,
+			Name:         "robot-server1",
+			Product:      "Robot Server™ 1",
+			Dc:           "NBG1-DC1",
+		}}
+
+		metadata, err := server.Metadata(0, &corev1.Node{}, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"instance.hetzner.cloud/provided-by": "robot",
+			"instance.hetzner.cloud/product":     "Robot-Server-1",
+			"instance.hetzner.cloud/datacenter":  "nbg1-dc1",
+		}, metadata.AdditionalLabels)
+	})
+}
+
+func TestInstances_InstanceMetadataTopology(t *testing.T) {
+	cfg := config.HCCMConfiguration{
+		Instance: config.InstanceConfiguration{
+			AddressFamily:    config.AddressFamilyIPv4,
+			ZoneLabelEnabled: true,
+			Topology:         config.TopologyConfiguration{Region: topology.LevelNetworkZone, Zone: topology.LevelLocation},
+		},
+	}
+
+	cloudServer := hcloudServer{Server: &hcloud.Server{
+		ServerType: &hcloud.ServerType{Name: "cx22"},
+		Location:   &hcloud.Location{Name: "fsn1", NetworkZone: hcloud.NetworkZoneEUCentral},
+	}}
+	metadata, err := cloudServer.Metadata(0, &corev1.Node{}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-central", metadata.Region)
+	assert.Equal(t, "fsn1", metadata.Zone)
+
+	robotServer := robotServer{Server: &hrobotmodels.Server{ServerIP: "233.252.0.123", Dc: "FSN1-DC14"}}
+	metadata, err = robotServer.Metadata(0, &corev1.Node{}, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-central", metadata.Region)
+	assert.Equal(t, "fsn1", metadata.Zone)
+}
+
+func TestInstances_InstanceMetadataFloatingIPs(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+	env.Mux.HandleFunc("/servers/1", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.ServerGetResponse{
+			Server: schema.Server{
+				ID:         1,
+				Name:       "foobar",
+				ServerType: schema.ServerType{Name: "asdf11"},
+				Location:   schema.Location{Name: "fsn1"},
+				PublicNet: schema.ServerPublicNet{
+					IPv4:        schema.ServerPublicNetIPv4{IP: "203.0.113.7"},
+					FloatingIPs: []int64{5, 6},
+				},
+			},
+		})
+	})
+	var floatingIPRequests int
+	env.Mux.HandleFunc("/floating_ips", func(w http.ResponseWriter, _ *http.Request) {
+		floatingIPRequests++
+		json.NewEncoder(w).Encode(schema.FloatingIPListResponse{
+			FloatingIPs: []schema.FloatingIP{
+				{ID: 5, Type: "ipv4", IP: "198.51.100.5", Server: new(int64(1))},
+				// Unassigned after the server was cached.
+				{ID: 6, Type: "ipv4", IP: "198.51.100.6"},
+				{ID: 7, Type: "ipv4", IP: "198.51.100.7", Server: new(int64(2))},
+			},
+		})
+	})
+
+	env.Cfg.Instance.FloatingIPs = config.FloatingIPsFirst
+	instances := newInstances(env.Client, env.RobotClient, env.ServerCache, env.FloatingIPCache, nil, env.Recorder, 0, env.Cfg)
+
+	for range 2 {
+		metadata, err := instances.InstanceMetadata(t.Context(), &corev1.Node{
+			Spec: corev1.NodeSpec{ProviderID: "hcloud://1"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []corev1.NodeAddress{
+			{Type: corev1.NodeHostName, Address: "foobar"},
+			{Type: corev1.NodeExternalIP, Address: "198.51.100.5"},
+			{Type: corev1.NodeExternalIP, Address: "203.0.113.7"},
+		}, metadata.NodeAddresses)
+	}
+	// The Floating IPs are listed once and served from the cache afterwards.
+	assert.Equal(t, 1, floatingIPRequests)
+}
+
 func TestNodeAddresses(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -738,3 +887,129 @@ func TestNodeAddressesRobotServer(t *testing.T) {
 		})
 	}
 }
+
+func TestInstances_NodeAgent(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+	teamEnv := newTestEnv()
+	defer teamEnv.Teardown()
+
+	handleServers(env, schema.Server{
+		ID:         1,
+		Name:       "shared",
+		ServerType: schema.ServerType{Name: "cx22"},
+		Location:   schema.Location{Name: "fsn1"},
+		PublicNet:  schema.ServerPublicNet{IPv4: schema.ServerPublicNetIPv4{IP: "203.0.113.1"}},
+	})
+	handleServers(teamEnv, schema.Server{
+		ID:         2,
+		Name:       "team",
+		ServerType: schema.ServerType{Name: "cx32"},
+		Location:   schema.Location{Name: "nbg1"},
+		PrivateNet: []schema.ServerPrivateNet{{Network: 1, IP: "10.0.0.2"}},
+	})
+	notFound := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(schema.ErrorResponse{Error: schema.Error{Code: string(hcloud.ErrorCodeNotFound)}})
+	}
+	env.Mux.HandleFunc("/servers/2", notFound)
+	env.Mux.HandleFunc("/servers/3", notFound)
+	teamEnv.Mux.HandleFunc("/servers/3", notFound)
+
+	projects := []*project{{name: "team-a", client: teamEnv.Client, serverCache: teamEnv.ServerCache}}
+
+	agentNode := func(name, serverID string, internalIPs ...string) *corev1.Node {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{agent.AnnotationServerID: serverID},
+		}}
+		for _, ip := range internalIPs {
+			node.Status.Addresses = append(node.Status.Addresses, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: ip})
+		}
+		return node
+	}
+
+	tests := []struct {
+		name             string
+		nodeAgentEnabled bool
+		node             *corev1.Node
+		wantProviderID   string
+		wantExists       bool
+		wantErr          string
+	}{
+		{
+			name:             "default project",
+			nodeAgentEnabled: true,
+			node:             agentNode("k8s-node", "1", "203.0.113.1"),
+			wantProviderID:   "hcloud://1",
+			wantExists:       true,
+		},
+		{
+			name:             "additional project",
+			nodeAgentEnabled: true,
+			node:             agentNode("k8s-node", "2", "10.0.0.2"),
+			wantProviderID:   "hcloud://team-a/2",
+			wantExists:       true,
+		},
+		{
+			name:             "annotation takes precedence over name",
+			nodeAgentEnabled: true,
+			node:             agentNode("shared", "2", "10.0.0.2"),
+			wantProviderID:   "hcloud://team-a/2",
+			wantExists:       true,
+		},
+		{
+			name:             "addresses do not match",
+			nodeAgentEnabled: true,
+			node:             agentNode("k8s-node", "1", "10.0.0.2"),
+			wantErr:          `addresses of node "k8s-node" do not match hcloud server "1" published by the node agent`,
+		},
+		{
+			name:             "no addresses",
+			nodeAgentEnabled: true,
+			node:             agentNode("k8s-node", "2"),
+			wantErr:          `addresses of node "k8s-node" do not match hcloud server "2" published by the node agent`,
+		},
+		{
+			name:             "unknown server",
+			nodeAgentEnabled: true,
+			node:             agentNode("shared", "3"),
+			wantExists:       false,
+		},
+		{
+			name:             "invalid annotation",
+			nodeAgentEnabled: true,
+			node:             agentNode("shared", "abc"),
+			wantErr:          `invalid annotation node-agent.hetzner.cloud/server-id="abc"`,
+		},
+		{
+			name:           "annotation ignored if disabled",
+			node:           agentNode("shared", "2"),
+			wantProviderID: "hcloud://1",
+			wantExists:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := env.Cfg
+			cfg.Instance.NodeAgentEnabled = tt.nodeAgentEnabled
+			instances := newInstances(env.Client, nil, env.ServerCache, env.FloatingIPCache, projects, env.Recorder, 0, cfg)
+
+			exists, err := instances.InstanceExists(t.Context(), tt.node)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExists, exists)
+			if !tt.wantExists {
+				return
+			}
+
+			metadata, err := instances.InstanceMetadata(t.Context(), tt.node)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProviderID, metadata.ProviderID)
+		})
+	}
+}
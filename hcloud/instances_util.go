@@ -112,14 +112,3 @@ func getInstanceTypeOfRobotServer(server *hrobotmodels.Server) string {
 	// Removes all characters that are invalid for a Kubernetes label
 	return regexp.MustCompile(`[^a-zA-Z0-9_.-]+`).ReplaceAllString(productName, "")
 }
-
-func getZoneOfRobotServer(server *hrobotmodels.Server) string {
-	return strings.ToLower(server.Dc)
-}
-
-func getRegionOfRobotServer(server *hrobotmodels.Server) string {
-	zone := getZoneOfRobotServer(server)
-	// zone is a Hetzner DC, e.g. "hel1-dc2"
-	// the cloud location is equal to the first part of the zone, e.g. "hel1" and that is was has historically been used in the Region label.
-	return strings.Split(zone, "-")[0]
-}
@@ -0,0 +1,207 @@
+package hcloud
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/cloud-provider/app"
+	cloudcontrollerconfig "k8s.io/cloud-provider/app/config"
+	servicecontroller "k8s.io/cloud-provider/controllers/service"
+	servicehelper "k8s.io/cloud-provider/service/helpers"
+	genericcontrollermanager "k8s.io/controller-manager/app"
+	"k8s.io/controller-manager/controller"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+)
+
+// LoadBalancerClassControllerName is the name of the controller handling the
+// Services with one of the configured Load Balancer classes.
+const LoadBalancerClassControllerName = "hcloud-load-balancer-class"
+
+// ignoredLoadBalancerClass is set on all Services, which are not handled by
+// the Load Balancer class controller. The upstream service controller skips
+// all Services with a Load Balancer class.
+const ignoredLoadBalancerClass = "load-balancer.hetzner.cloud/ignored"
+
+// loadBalancerClassFinalizer is set instead of
+// [servicehelper.LoadBalancerCleanupFinalizer] on the Services handled by the
+// Load Balancer class controller. The upstream service controller cleans up all
+// Services with its finalizer, including Services with a Load Balancer class,
+// so with a shared finalizer both controllers would delete the Load Balancer.
+const loadBalancerClassFinalizer = "load-balancer.hetzner.cloud/class-cleanup"
+
+// StartLoadBalancerClassControllerWrapper returns the [app.InitFunc] of the
+// Load Balancer class controller.
+//
+// The upstream service controller only handles Services without a Load
+// Balancer class. The Load Balancer class controller is a second instance of
+// the upstream service controller, which uses its own Service informer. The
+// informer transforms the Services, so that the service controller only sees
+// the Services of the configured classes, without their class. The Load
+// Balancers of these Services are only cleaned up by the Load Balancer class
+// controller, see [loadBalancerClassFinalizer].
+func StartLoadBalancerClassControllerWrapper(
+	initContext app.ControllerInitContext, completedConfig *cloudcontrollerconfig.CompletedConfig, cloud cloudprovider.Interface,
+) app.InitFunc {
+	return func(ctx context.Context, controllerContext genericcontrollermanager.ControllerContext) (controller.Interface, bool, error) {
+		return startLoadBalancerClassController(ctx, initContext, controllerContext, completedConfig, cloud)
+	}
+}
+
+func startLoadBalancerClassController(
+	ctx context.Context,
+	initContext app.ControllerInitContext,
+	controllerContext genericcontrollermanager.ControllerContext,
+	completedConfig *cloudcontrollerconfig.CompletedConfig,
+	cloudProvider cloudprovider.Interface,
+) (controller.Interface, bool, error) {
+	c, ok := cloudProvider.(*cloud)
+	if !ok || !c.cfg.LoadBalancer.Enabled || len(c.cfg.LoadBalancer.Classes) == 0 {
+		return nil, false, nil
+	}
+
+	client := completedConfig.ClientBuilder.ClientOrDie(initContext.ClientName)
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(
+		client,
+		completedConfig.ComponentConfig.Generic.MinResyncPeriod.Duration,
+		informers.WithTransform(loadBalancerClassTransform(c.cfg.LoadBalancer.Classes)),
+	)
+
+	serviceController, err := servicecontroller.New(
+		cloudProvider,
+		loadBalancerClassClient{client},
+		informerFactory.Core().V1().Services(),
+		completedConfig.SharedInformers.Core().V1().Nodes(),
+		completedConfig.ComponentConfig.KubeCloudShared.ClusterName,
+		utilfeature.DefaultFeatureGate,
+	)
+	if err != nil {
+		// Same as for the upstream service controller, this should not fail
+		// the cloud controller manager.
+		klog.Errorf("Failed to start load balancer class controller: %v", err)
+		return nil, false, nil
+	}
+
+	informerFactory.Start(ctx.Done())
+	go serviceController.Run(ctx, int(completedConfig.ComponentConfig.ServiceController.ConcurrentServiceSyncs), controllerContext.ControllerManagerMetrics)
+
+	return nil, true, nil
+}
+
+// loadBalancerClassTransform returns a [toolscache.TransformFunc] for Services.
+//
+// Services with one of the given classes are returned without their class and
+// with the defaults of their class applied. All other Services are marked with
+// [ignoredLoadBalancerClass], so that the service controller does not reconcile
+// them.
+//
+// On all Services, [loadBalancerClassFinalizer] is returned as
+// [servicehelper.LoadBalancerCleanupFinalizer], which the service controller
+// expects, and the finalizer of the upstream service controller is removed.
+// This way the service controller only cleans up the Load Balancers it created,
+// also after the Service type was changed.
+func loadBalancerClassTransform(classes map[string]map[string]string) toolscache.TransformFunc {
+	return func(obj any) (any, error) {
+		svc, ok := obj.(*corev1.Service)
+		if !ok {
+			return obj, nil
+		}
+
+		svc.Finalizers = slices.DeleteFunc(svc.Finalizers, func(f string) bool {
+			return f == servicehelper.LoadBalancerCleanupFinalizer
+		})
+		if i := slices.Index(svc.Finalizers, loadBalancerClassFinalizer); i >= 0 {
+			svc.Finalizers[i] = servicehelper.LoadBalancerCleanupFinalizer
+		}
+
+		if svc.Spec.LoadBalancerClass != nil {
+			if defaults, ok := classes[*svc.Spec.LoadBalancerClass]; ok {
+				svc.Spec.LoadBalancerClass = nil
+				return annotation.ServiceWithClassDefaults(svc, defaults), nil
+			}
+		}
+
+		class := ignoredLoadBalancerClass
+		svc.Spec.LoadBalancerClass = &class
+		return svc, nil
+	}
+}
+
+// loadBalancerClassClient is the client of the Load Balancer class controller.
+// It replaces [servicehelper.LoadBalancerCleanupFinalizer] with
+// [loadBalancerClassFinalizer] in the patches of Services, which is the reverse
+// of [loadBalancerClassTransform].
+type loadBalancerClassClient struct {
+	kubernetes.Interface
+}
+
+func (c loadBalancerClassClient) CoreV1() corev1client.CoreV1Interface {
+	return loadBalancerClassCoreV1Client{c.Interface.CoreV1()}
+}
+
+type loadBalancerClassCoreV1Client struct {
+	corev1client.CoreV1Interface
+}
+
+func (c loadBalancerClassCoreV1Client) Services(namespace string) corev1client.ServiceInterface {
+	return loadBalancerClassServiceClient{c.CoreV1Interface.Services(namespace)}
+}
+
+type loadBalancerClassServiceClient struct {
+	corev1client.ServiceInterface
+}
+
+func (c loadBalancerClassServiceClient) Patch(
+	ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string,
+) (*corev1.Service, error) {
+	if pt == types.StrategicMergePatchType {
+		var err error
+		data, err = replaceFinalizerInPatch(data, servicehelper.LoadBalancerCleanupFinalizer, loadBalancerClassFinalizer)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.ServiceInterface.Patch(ctx, name, pt, data, opts, subresources...)
+}
+
+// replaceFinalizerInPatch replaces the finalizer from with to in a strategic
+// merge patch, as created by [servicehelper.PatchService].
+func replaceFinalizerInPatch(data []byte, from, to string) ([]byte, error) {
+	var patch map[string]any
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, err
+	}
+	metadata, ok := patch["metadata"].(map[string]any)
+	if !ok {
+		return data, nil
+	}
+
+	replaced := false
+	for _, key := range []string{"finalizers", "$setElementOrder/finalizers", "$deleteFromPrimitiveList/finalizers"} {
+		finalizers, ok := metadata[key].([]any)
+		if !ok {
+			continue
+		}
+		for i, f := range finalizers {
+			if f == from {
+				finalizers[i] = to
+				replaced = true
+			}
+		}
+	}
+	if !replaced {
+		return data, nil
+	}
+	return json.Marshal(patch)
+}
@@ -0,0 +1,184 @@
+package hcloud
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	cloudprovider "k8s.io/cloud-provider"
+	servicecontroller "k8s.io/cloud-provider/controllers/service"
+	cloudfake "k8s.io/cloud-provider/fake"
+	servicehelper "k8s.io/cloud-provider/service/helpers"
+	controllersmetrics "k8s.io/component-base/metrics/prometheus/controllers"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+func TestLoadBalancerClassTransform(t *testing.T) {
+	transform := loadBalancerClassTransform(map[string]map[string]string{
+		"example.com/internal": {
+			string(annotation.LBType): "lb21",
+		},
+	})
+
+	newService := func(class *string, finalizers ...string) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "svc",
+				Finalizers: finalizers,
+			},
+			Spec: corev1.ServiceSpec{
+				Type:              corev1.ServiceTypeLoadBalancer,
+				LoadBalancerClass: class,
+			},
+		}
+	}
+
+	t.Run("configured class", func(t *testing.T) {
+		obj, err := transform(newService(hcloud.Ptr("example.com/internal"), loadBalancerClassFinalizer, "other"))
+		assert.NoError(t, err)
+
+		svc := obj.(*corev1.Service)
+		assert.Nil(t, svc.Spec.LoadBalancerClass)
+		assert.Equal(t, map[string]string{string(annotation.LBType): "lb21"}, svc.Annotations)
+		assert.Equal(t, []string{servicehelper.LoadBalancerCleanupFinalizer, "other"}, svc.Finalizers)
+	})
+
+	for name, class := range map[string]*string{
+		"no class":      nil,
+		"unknown class": hcloud.Ptr("metallb.io/metallb"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			obj, err := transform(newService(class, servicehelper.LoadBalancerCleanupFinalizer, "other"))
+			assert.NoError(t, err)
+
+			svc := obj.(*corev1.Service)
+			assert.Equal(t, hcloud.Ptr(ignoredLoadBalancerClass), svc.Spec.LoadBalancerClass)
+			assert.Nil(t, svc.Annotations)
+			assert.Equal(t, []string{"other"}, svc.Finalizers)
+		})
+	}
+
+	t.Run("class removed with type change", func(t *testing.T) {
+		svc := newService(nil, loadBalancerClassFinalizer, "other")
+		svc.Spec.Type = corev1.ServiceTypeClusterIP
+
+		obj, err := transform(svc)
+		assert.NoError(t, err)
+
+		// The service controller still cleans up the Load Balancer.
+		svc = obj.(*corev1.Service)
+		assert.Equal(t, []string{servicehelper.LoadBalancerCleanupFinalizer, "other"}, svc.Finalizers)
+	})
+
+	t.Run("other object", func(t *testing.T) {
+		node := &corev1.Node{}
+		obj, err := transform(node)
+		assert.NoError(t, err)
+		assert.Same(t, node, obj)
+	})
+}
+
+func TestReplaceFinalizerInPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			name:  "add finalizer",
+			patch: `{"metadata":{"$setElementOrder/finalizers":["other","service.kubernetes.io/load-balancer-cleanup"],"finalizers":["service.kubernetes.io/load-balancer-cleanup"]}}`,
+			want:  `{"metadata":{"$setElementOrder/finalizers":["other","load-balancer.hetzner.cloud/class-cleanup"],"finalizers":["load-balancer.hetzner.cloud/class-cleanup"]}}`,
+		},
+		{
+			name:  "remove finalizer",
+			patch: `{"metadata":{"$deleteFromPrimitiveList/finalizers":["service.kubernetes.io/load-balancer-cleanup"],"$setElementOrder/finalizers":["other"]}}`,
+			want:  `{"metadata":{"$deleteFromPrimitiveList/finalizers":["load-balancer.hetzner.cloud/class-cleanup"],"$setElementOrder/finalizers":["other"]}}`,
+		},
+		{
+			name:  "status",
+			patch: `{"status":{"loadBalancer":{"ingress":[{"ip":"203.0.113.7"}]}}}`,
+			want:  `{"status":{"loadBalancer":{"ingress":[{"ip":"203.0.113.7"}]}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := replaceFinalizerInPatch([]byte(tt.patch), servicehelper.LoadBalancerCleanupFinalizer, loadBalancerClassFinalizer)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(patch))
+		})
+	}
+}
+
+// TestLoadBalancerClassController_Cleanup runs the upstream service controller
+// next to the Load Balancer class controller and checks, that the Load Balancer
+// of a Service with a class is only deleted by the class controller.
+func TestLoadBalancerClassController_Cleanup(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+
+		client := kubefake.NewClientset(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec: corev1.ServiceSpec{
+				Type:              corev1.ServiceTypeLoadBalancer,
+				LoadBalancerClass: hcloud.Ptr("example.com/internal"),
+			},
+		})
+		cloud := &cloudfake.Cloud{Exists: true}
+		countCalls := func(call string) int {
+			return len(slices.DeleteFunc(slices.Clone(cloud.Calls), func(c string) bool { return c != call }))
+		}
+
+		nodeInformerFactory := informers.NewSharedInformerFactory(client, 0)
+		startServiceController := func(client kubernetes.Interface, informerFactory informers.SharedInformerFactory) {
+			serviceController, err := servicecontroller.New(
+				cloudprovider.Interface(cloud),
+				client,
+				informerFactory.Core().V1().Services(),
+				nodeInformerFactory.Core().V1().Nodes(),
+				"kubernetes",
+				utilfeature.DefaultFeatureGate,
+			)
+			require.NoError(t, err)
+			informerFactory.Start(ctx.Done())
+			go serviceController.Run(ctx, 1, controllersmetrics.NewControllerManagerMetrics("test"))
+		}
+		startServiceController(client, informers.NewSharedInformerFactory(client, 0))
+		startServiceController(loadBalancerClassClient{client}, informers.NewSharedInformerFactoryWithOptions(
+			client, 0, informers.WithTransform(loadBalancerClassTransform(map[string]map[string]string{"example.com/internal": {}})),
+		))
+		nodeInformerFactory.Start(ctx.Done())
+		synctest.Wait()
+
+		svc, err := client.CoreV1().Services("default").Get(ctx, "svc", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{loadBalancerClassFinalizer}, svc.Finalizers)
+		assert.Equal(t, 1, countCalls("create"))
+
+		// The fake client does not handle finalizers, so the deletion is only
+		// marked on the Service.
+		svc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		_, err = client.CoreV1().Services("default").Update(ctx, svc, metav1.UpdateOptions{})
+		require.NoError(t, err)
+		synctest.Wait()
+
+		svc, err = client.CoreV1().Services("default").Get(ctx, "svc", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, svc.Finalizers)
+		assert.Equal(t, 1, countCalls("delete"))
+
+		cancel()
+		synctest.Wait()
+	})
+}
@@ -0,0 +1,44 @@
+package hcloud
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/lbprofile"
+)
+
+const profileStatusSyncPeriod = 30 * time.Second
+
+// initProfiles starts the informer for HCloudLoadBalancerProfiles and
+// periodically updates their status with the Services using them.
+func (c *cloud) initProfiles(clientBuilder cloudprovider.ControllerClientBuilder, stop <-chan struct{}) {
+	client := dynamic.NewForConfigOrDie(clientBuilder.ConfigOrDie("hccm-load-balancer-profiles"))
+
+	informerFactory := dynamicinformer.NewDynamicSharedInformerFactory(client, 0)
+	informer := informerFactory.ForResource(lbprofile.GroupVersionResource)
+	c.profiles = lbprofile.NewLister(informer.Lister())
+	c.profilesSynced = informer.Informer().HasSynced
+	informerFactory.Start(stop)
+
+	ctx := wait.ContextForChannel(stop)
+	go wait.Until(func() {
+		if !c.profilesSynced() || c.serviceLister == nil || !c.servicesSynced() {
+			return
+		}
+
+		services, err := c.serviceLister.List(labels.Everything())
+		if err != nil {
+			klog.ErrorS(err, "list services for load balancer profile status")
+			return
+		}
+		if err := c.profiles.SyncStatus(ctx, client, services); err != nil {
+			klog.ErrorS(err, "update load balancer profile status")
+		}
+	}, profileStatusSyncPeriod, stop)
+}
@@ -4,16 +4,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/klog/v2"
 
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/hcops"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/health"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/lbprofile"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
 
@@ -32,13 +40,81 @@ type LoadBalancerOps interface {
 
 type loadBalancers struct {
 	lbOps LoadBalancerOps
-	cfg   *config.LoadBalancerConfiguration
+	cfg   *config.Store
+
+	// recorder is used to report aborted reconciles. Events are not recorded if
+	// it is nil.
+	recorder record.EventRecorder
+
+	// namespaceLister is only set if reading defaults from the Namespace of
+	// a Service is enabled.
+	namespaceLister  corelisters.NamespaceLister
+	namespacesSynced toolscache.InformerSynced
+
+	// profiles is only set if HCloudLoadBalancerProfiles are enabled.
+	profiles       *lbprofile.Lister
+	profilesSynced toolscache.InformerSynced
 }
 
-func newLoadBalancers(lbOps LoadBalancerOps, lbCfg *config.LoadBalancerConfiguration) *loadBalancers {
+func newLoadBalancers(lbOps LoadBalancerOps, cfg *config.Store) *loadBalancers {
 	return &loadBalancers{
 		lbOps: lbOps,
-		cfg:   lbCfg,
+		cfg:   cfg,
+	}
+}
+
+// errReconcileTimeout is the cause of the context of a reconcile, which
+// exceeded the configured reconcile timeout.
+var errReconcileTimeout = errors.New("reconcile timeout exceeded")
+
+// reconcileProgress records the steps of a reconcile of a Service, so an
+// aborted reconcile can report how far it got.
+type reconcileProgress struct {
+	completed []string
+	current   string
+}
+
+// step marks the current step as completed and starts the next one.
+func (p *reconcileProgress) step(name string) {
+	if p.current != "" {
+		p.completed = append(p.completed, p.current)
+	}
+	p.current = name
+}
+
+func (p *reconcileProgress) String() string {
+	if len(p.completed) == 0 {
+		return fmt.Sprintf("aborted while %s", p.current)
+	}
+	return fmt.Sprintf("aborted while %s, after %s", p.current, strings.Join(p.completed, ", "))
+}
+
+// withReconcileTimeout limits the duration of a reconcile of svc. The returned
+// function must be deferred with the error of the reconcile. If the reconcile
+// was aborted by the timeout, it adds the progress to the error and records an
+// event on svc.
+func (l *loadBalancers) withReconcileTimeout(
+	ctx context.Context, svc *corev1.Service, progress *reconcileProgress,
+) (context.Context, func(*error)) {
+	timeout := l.cfg.Get().LoadBalancer.ReconcileTimeout
+	if timeout == 0 {
+		return ctx, func(*error) {}
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, errReconcileTimeout)
+	return ctx, func(err *error) {
+		defer cancel()
+
+		if *err == nil || !errors.Is(context.Cause(ctx), errReconcileTimeout) {
+			return
+		}
+		*err = fmt.Errorf("%w: %s exceeded (%s)", *err, timeout, progress)
+
+		klog.InfoS("reconcile timeout exceeded", "service", svc.Namespace+"/"+svc.Name, "timeout", timeout, "progress", progress.String())
+		if l.recorder != nil {
+			l.recorder.Eventf(svc, corev1.EventTypeWarning, "ReconcileTimeout",
+				"Reconcile timeout of %s exceeded, %s", timeout, progress)
+		}
 	}
 }
 
@@ -65,11 +141,69 @@ func matchNodeSelector(svc *corev1.Service, nodes []*corev1.Node) ([]*corev1.Nod
 	return selectedNodes, nil
 }
 
+// withDefaults returns svc with the Load Balancer defaults of its
+// HCloudLoadBalancerProfile and Namespace applied. The profile takes precedence
+// over the Namespace.
+func (l *loadBalancers) withDefaults(svc *corev1.Service) (*corev1.Service, error) {
+	svc, err := l.withProfileDefaults(svc)
+	if err != nil {
+		return nil, err
+	}
+	return l.withNamespaceDefaults(svc)
+}
+
+// withProfileDefaults returns svc with the settings of the
+// HCloudLoadBalancerProfile referenced by the Service applied.
+func (l *loadBalancers) withProfileDefaults(svc *corev1.Service) (*corev1.Service, error) {
+	if _, ok := annotation.LBProfile.StringFromService(svc); !ok {
+		return svc, nil
+	}
+
+	if l.profiles == nil {
+		return nil, fmt.Errorf("annotation %s is set, but %ss are disabled", annotation.LBProfile, lbprofile.Kind)
+	}
+
+	// Only Services using a profile wait for the cache, so other Services are
+	// still reconciled if the informer can not sync, e.g. because the CRD is
+	// missing.
+	if l.profilesSynced != nil && !l.profilesSynced() {
+		return nil, fmt.Errorf("%s cache not synced yet", lbprofile.Kind)
+	}
+
+	return l.profiles.ServiceWithDefaults(svc)
+}
+
+// withNamespaceDefaults returns svc with the Load Balancer defaults configured
+// on its Namespace applied. See [annotation.ServiceWithNamespaceDefaults].
+func (l *loadBalancers) withNamespaceDefaults(svc *corev1.Service) (*corev1.Service, error) {
+	if l.namespaceLister == nil {
+		return svc, nil
+	}
+
+	// Reconciling without the Namespace defaults could create a Load Balancer
+	// with the wrong settings, e.g. in the wrong location.
+	if l.namespacesSynced != nil && !l.namespacesSynced() {
+		return nil, fmt.Errorf("namespace cache not synced yet")
+	}
+
+	ns, err := l.namespaceLister.Get(svc.Namespace)
+	if apierrors.IsNotFound(err) {
+		return svc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", svc.Namespace, err)
+	}
+
+	return annotation.ServiceWithNamespaceDefaults(svc, ns), nil
+}
+
 func (l *loadBalancers) GetLoadBalancer(
 	ctx context.Context, _ string, service *corev1.Service,
 ) (status *corev1.LoadBalancerStatus, exists bool, err error) {
 	const op = "hcloud/loadBalancers.GetLoadBalancer"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op, tracing.Service(service)...)
+	defer endSpan(&err)
 
 	lb, err := l.lbOps.GetByK8SServiceUID(ctx, service)
 	if err != nil {
@@ -102,17 +236,27 @@ func (l *loadBalancers) GetLoadBalancerName(_ context.Context, _ string, service
 
 func (l *loadBalancers) EnsureLoadBalancer(
 	ctx context.Context, clusterName string, svc *corev1.Service, nodes []*corev1.Node,
-) (*corev1.LoadBalancerStatus, error) {
+) (_ *corev1.LoadBalancerStatus, err error) {
 	const op = "hcloud/loadBalancers.EnsureLoadBalancer"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op, tracing.Service(svc)...)
+	defer endSpan(&err)
+	defer health.ObserveReconcile(health.LoadBalancers, &err)
+	progress := &reconcileProgress{}
+	ctx, endReconcile := l.withReconcileTimeout(ctx, svc, progress)
+	defer endReconcile(&err)
 
 	var (
 		reload        bool
 		lb            *hcloud.LoadBalancer
-		err           error
 		selectedNodes []*corev1.Node
 	)
 
+	svc, err = l.withDefaults(svc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	selectedNodes, err = matchNodeSelector(svc, nodes)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -124,6 +268,7 @@ func (l *loadBalancers) EnsureLoadBalancer(
 	}
 	klog.InfoS("ensure Load Balancer", "op", op, "service", svc.Name, "nodes", nodeNames)
 
+	progress.step("looking up the Load Balancer")
 	lb, err = l.lbOps.GetByK8SServiceUID(ctx, svc)
 	if err != nil && !errors.Is(err, hcops.ErrNotFound) {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -147,12 +292,14 @@ func (l *loadBalancers) EnsureLoadBalancer(
 
 	// If we were still not able to find the load balancer we create it.
 	if errors.Is(err, hcops.ErrNotFound) {
+		progress.step("creating the Load Balancer")
 		lb, err = l.lbOps.Create(ctx, lbName, svc)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
+	progress.step("reconciling the Load Balancer")
 	lbChanged, err := l.lbOps.ReconcileHCLB(ctx, lb, svc)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -167,6 +314,7 @@ func (l *loadBalancers) EnsureLoadBalancer(
 	// lb state so that we can re-attach the targets if needed
 	if reload {
 		klog.InfoS("reload HC Load Balancer", "op", op, "loadBalancerID", lb.ID)
+		progress.step("reloading the Load Balancer")
 		lb, err = l.lbOps.GetByID(ctx, lb.ID)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
@@ -174,12 +322,14 @@ func (l *loadBalancers) EnsureLoadBalancer(
 		reload = false
 	}
 
+	progress.step("reconciling the services")
 	servicesChanged, err := l.lbOps.ReconcileHCLBServices(ctx, lb, svc)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	reload = reload || servicesChanged
 
+	progress.step("reconciling the targets")
 	targetsChanged, err := l.lbOps.ReconcileHCLBTargets(ctx, lb, svc, selectedNodes)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -188,6 +338,7 @@ func (l *loadBalancers) EnsureLoadBalancer(
 
 	if reload {
 		klog.InfoS("reload HC Load Balancer", "op", op, "loadBalancerID", lb.ID)
+		progress.step("reloading the Load Balancer")
 		lb, err = l.lbOps.GetByID(ctx, lb.ID)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
@@ -210,9 +361,9 @@ func (l *loadBalancers) EnsureLoadBalancer(
 	return &corev1.LoadBalancerStatus{Ingress: ingress}, nil
 }
 
-func (l *loadBalancers) buildLoadBalancerStatusIngress(lb *hcloud.LoadBalancer, svc *corev1.Service) ([]corev1.LoadBalancerIngress, error) {
+func (l *loadBalancers) buildLoadBalancerStatusIngress(lb *hcloud.LoadBalancer, svc *corev1.Service) (_ []corev1.LoadBalancerIngress, err error) {
 	const op = "hcloud/loadBalancers.getLoadBalancerStatusIngress"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
 
 	var ingress []corev1.LoadBalancerIngress
 	ipMode := corev1.LoadBalancerIPModeVIP
@@ -267,7 +418,7 @@ func (l *loadBalancers) getPrivateIngressEnabled(svc *corev1.Service) (bool, err
 		return !disable, nil
 	}
 	if errors.Is(err, annotation.ErrNotSet) {
-		return l.cfg.PrivateIngressEnabled, nil
+		return l.cfg.Get().LoadBalancer.PrivateIngressEnabled, nil
 	}
 	return true, err
 }
@@ -278,10 +429,11 @@ func (l *loadBalancers) getProxyProtocolEnabled(svc *corev1.Service) (bool, erro
 		return enable, nil
 	}
 	if errors.Is(err, annotation.ErrNotSet) {
-		if l.cfg.ProxyProtocolEnabled == nil {
+		proxyProtocolEnabled := l.cfg.Get().LoadBalancer.ProxyProtocolEnabled
+		if proxyProtocolEnabled == nil {
 			return false, nil
 		}
-		return *l.cfg.ProxyProtocolEnabled, nil
+		return *proxyProtocolEnabled, nil
 	}
 	return false, err
 }
@@ -292,23 +444,33 @@ func (l *loadBalancers) getIPv6Enabled(svc *corev1.Service) (bool, error) {
 		return !disable, nil
 	}
 	if errors.Is(err, annotation.ErrNotSet) {
-		return l.cfg.IPv6Enabled, nil
+		return l.cfg.Get().LoadBalancer.IPv6Enabled, nil
 	}
 	return true, err
 }
 
 func (l *loadBalancers) UpdateLoadBalancer(
 	ctx context.Context, clusterName string, svc *corev1.Service, nodes []*corev1.Node,
-) error {
+) (err error) {
 	const op = "hcloud/loadBalancers.UpdateLoadBalancer"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op, tracing.Service(svc)...)
+	defer endSpan(&err)
+	defer health.ObserveReconcile(health.LoadBalancers, &err)
+	progress := &reconcileProgress{}
+	ctx, endReconcile := l.withReconcileTimeout(ctx, svc, progress)
+	defer endReconcile(&err)
 
 	var (
 		lb            *hcloud.LoadBalancer
-		err           error
 		selectedNodes []*corev1.Node
 	)
 
+	svc, err = l.withDefaults(svc)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	selectedNodes, err = matchNodeSelector(svc, nodes)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -320,6 +482,7 @@ func (l *loadBalancers) UpdateLoadBalancer(
 	}
 	klog.InfoS("update Load Balancer", "op", op, "service", svc.Name, "nodes", nodeNames)
 
+	progress.step("looking up the Load Balancer")
 	lb, err = l.lbOps.GetByK8SServiceUID(ctx, svc)
 	if errors.Is(err, hcops.ErrNotFound) {
 		lbName := l.GetLoadBalancerName(ctx, clusterName, svc)
@@ -334,22 +497,32 @@ func (l *loadBalancers) UpdateLoadBalancer(
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	progress.step("reconciling the Load Balancer")
 	if _, err = l.lbOps.ReconcileHCLB(ctx, lb, svc); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	progress.step("reconciling the targets")
 	if _, err = l.lbOps.ReconcileHCLBTargets(ctx, lb, svc, selectedNodes); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	progress.step("reconciling the services")
 	if _, err = l.lbOps.ReconcileHCLBServices(ctx, lb, svc); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
 
-func (l *loadBalancers) EnsureLoadBalancerDeleted(ctx context.Context, _ string, service *corev1.Service) error {
+func (l *loadBalancers) EnsureLoadBalancerDeleted(ctx context.Context, _ string, service *corev1.Service) (err error) {
 	const op = "hcloud/loadBalancers.EnsureLoadBalancerDeleted"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
-
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op, tracing.Service(service)...)
+	defer endSpan(&err)
+	defer health.ObserveReconcile(health.LoadBalancers, &err)
+	progress := &reconcileProgress{}
+	ctx, endReconcile := l.withReconcileTimeout(ctx, service, progress)
+	defer endReconcile(&err)
+
+	progress.step("looking up the Load Balancer")
 	loadBalancer, err := l.lbOps.GetByK8SServiceUID(ctx, service)
 	if errors.Is(err, hcops.ErrNotFound) {
 		return nil
@@ -364,6 +537,7 @@ func (l *loadBalancers) EnsureLoadBalancerDeleted(ctx context.Context, _ string,
 	}
 
 	klog.InfoS("delete Load Balancer", "op", op, "loadBalancerID", loadBalancer.ID)
+	progress.step("deleting the Load Balancer")
 	err = l.lbOps.Delete(ctx, loadBalancer)
 	if errors.Is(err, hcops.ErrNotFound) {
 		return nil
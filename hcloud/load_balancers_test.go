@@ -1,17 +1,27 @@
 package hcloud
 
 import (
+	"context"
 	"errors"
 	"net"
 	"reflect"
 	"testing"
+	"testing/synctest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/hcops"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/lbprofile"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
 
@@ -963,3 +973,130 @@ func TestLoadBalancer_matchNodeSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadBalancer_withNamespaceDefaults(t *testing.T) {
+	indexer := toolscache.NewIndexer(toolscache.MetaNamespaceKeyFunc, toolscache.Indexers{})
+	err := indexer.Add(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "with-defaults",
+			Annotations: map[string]string{
+				string(annotation.LBLocation): "fsn1",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newService := func(namespace string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: namespace}}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		l := &loadBalancers{}
+		svc := newService("with-defaults")
+
+		actual, err := l.withNamespaceDefaults(svc)
+		assert.NoError(t, err)
+		assert.Same(t, svc, actual)
+	})
+
+	t.Run("namespace defaults applied", func(t *testing.T) {
+		l := &loadBalancers{namespaceLister: corelisters.NewNamespaceLister(indexer)}
+
+		actual, err := l.withNamespaceDefaults(newService("with-defaults"))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{string(annotation.LBLocation): "fsn1"}, actual.Annotations)
+	})
+
+	t.Run("namespace not found", func(t *testing.T) {
+		l := &loadBalancers{namespaceLister: corelisters.NewNamespaceLister(indexer)}
+		svc := newService("unknown")
+
+		actual, err := l.withNamespaceDefaults(svc)
+		assert.NoError(t, err)
+		assert.Same(t, svc, actual)
+	})
+
+	t.Run("cache not synced", func(t *testing.T) {
+		l := &loadBalancers{
+			namespaceLister:  corelisters.NewNamespaceLister(indexer),
+			namespacesSynced: func() bool { return false },
+		}
+
+		_, err := l.withNamespaceDefaults(newService("with-defaults"))
+		assert.EqualError(t, err, "namespace cache not synced yet")
+	})
+}
+
+func TestLoadBalancer_withProfileDefaults(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				string(annotation.LBProfile): "internal",
+			},
+		},
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		l := &loadBalancers{}
+
+		_, err := l.withProfileDefaults(svc)
+		assert.EqualError(t, err, "annotation load-balancer.hetzner.cloud/profile is set, but HCloudLoadBalancerProfiles are disabled")
+	})
+
+	t.Run("cache not synced", func(t *testing.T) {
+		l := &loadBalancers{
+			profiles:       lbprofile.NewLister(nil),
+			profilesSynced: func() bool { return false },
+		}
+
+		_, err := l.withProfileDefaults(svc)
+		assert.EqualError(t, err, "HCloudLoadBalancerProfile cache not synced yet")
+	})
+
+	t.Run("cache not synced without annotation", func(t *testing.T) {
+		l := &loadBalancers{
+			profiles:       lbprofile.NewLister(nil),
+			profilesSynced: func() bool { return false },
+		}
+
+		svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "no-profile"}}
+		got, err := l.withProfileDefaults(svc)
+		require.NoError(t, err)
+		assert.Same(t, svc, got)
+	})
+}
+
+func TestLoadBalancer_UpdateLoadBalancer_ReconcileTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		lbOps := &hcops.MockLoadBalancerOps{}
+		lbOps.Test(t)
+		recorder := record.NewFakeRecorder(1)
+
+		lbs := newLoadBalancers(lbOps, config.NewStore(config.HCCMConfiguration{
+			LoadBalancer: config.LoadBalancerConfiguration{ReconcileTimeout: time.Minute},
+		}))
+		lbs.recorder = recorder
+
+		svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", UID: "uid"}}
+		lb := &hcloud.LoadBalancer{ID: 1}
+
+		lbOps.On("GetByK8SServiceUID", mock.Anything, svc).Return(lb, nil)
+		lbOps.On("ReconcileHCLB", mock.Anything, lb, svc).Return(false, nil)
+		lbOps.On("ReconcileHCLBTargets", mock.Anything, lb, svc, mock.Anything).
+			Run(func(args mock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(false, context.DeadlineExceeded)
+
+		err := lbs.UpdateLoadBalancer(context.Background(), "cluster", svc, []*corev1.Node{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "1m0s exceeded (aborted while reconciling the targets, after looking up the Load Balancer, reconciling the Load Balancer)")
+		assert.Equal(t,
+			"Warning ReconcileTimeout Reconcile timeout of 1m0s exceeded, aborted while reconciling the targets, after looking up the Load Balancer, reconciling the Load Balancer",
+			<-recorder.Events,
+		)
+		lbOps.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,91 @@
+package hcloud
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/credentials"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/providerid"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// project is an additional Hetzner Cloud project, whose servers can be nodes of
+// the cluster. Load Balancers and Networks are only managed in the default
+// project, so its servers can not be targets of Load Balancers or routes.
+type project struct {
+	name            string
+	client          *hcloud.Client
+	transport       *credentials.Transport
+	serverCache     *cache.Cache[hcloud.Server]
+	floatingIPCache *cache.Cache[hcloud.FloatingIP]
+}
+
+// newProjects returns the additional projects of cfg. Like for the default
+// project, the tokens are validated against the API.
+func newProjects(ctx context.Context, cfg config.HCCMConfiguration) ([]*project, error) {
+	projects := make([]*project, 0, len(cfg.HCloudClient.Projects))
+	for _, p := range cfg.HCloudClient.Projects {
+		client, transport := newHCloudClient(cfg, p.Token)
+		if _, _, err := client.Location.List(ctx, hcloud.LocationListOpts{ListOpts: hcloud.ListOpts{PerPage: 1}}); err != nil {
+			return nil, fmt.Errorf("project %s: %w", p.Name, err)
+		}
+
+		projects = append(projects, &project{
+			name:            p.Name,
+			client:          client,
+			transport:       transport,
+			serverCache:     cache.NewServerCache(client, cfg.ServerCache.Mode, cfg.ServerCache.MaxAge),
+			floatingIPCache: cache.NewFloatingIPCache(client, floatingIPCacheDefaultMode, floatingIPCacheMaxAge),
+		})
+	}
+	return projects, nil
+}
+
+// projectByName returns the additional project with name, or nil if it is not
+// configured.
+func projectByName(projects []*project, name string) *project {
+	for _, p := range projects {
+		if p.name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// cloudServersByProviderID returns the servers of the default project and of
+// all additional projects by their canonical ProviderID.
+func (c *cloud) cloudServersByProviderID(ctx context.Context) (map[string]*hcloud.Server, error) {
+	servers, err := c.serverCache.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	serversByProviderID := make(map[string]*hcloud.Server, len(servers))
+	for _, server := range servers {
+		serversByProviderID[providerid.FromCloudServerID(server.ID)] = server
+	}
+
+	for _, p := range c.projects {
+		servers, err := p.serverCache.All(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("project %s: %w", p.name, err)
+		}
+		for _, server := range servers {
+			serversByProviderID[providerid.FromCloudServerIDInProject(p.name, server.ID)] = server
+		}
+	}
+	return serversByProviderID, nil
+}
+
+// canonicalCloudProviderID returns the canonical ProviderID of a Cloud Server
+// node, which is used as key by [cloud.cloudServersByProviderID]. It returns
+// false for Robot servers and invalid ProviderIDs.
+func canonicalCloudProviderID(providerID string) (string, bool) {
+	id, isCloudServer, err := providerid.ToServerID(providerID)
+	if err != nil || !isCloudServer {
+		return "", false
+	}
+	project, _ := providerid.ToProject(providerID)
+	return providerid.FromCloudServerIDInProject(project, id), true
+}
@@ -0,0 +1,141 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+// handleServers serves the servers on env, like the API of one project.
+func handleServers(env testEnv, servers ...schema.Server) {
+	env.Mux.HandleFunc("/servers", func(w http.ResponseWriter, r *http.Request) {
+		result := []schema.Server{}
+		for _, server := range servers {
+			if name := r.URL.Query().Get("name"); name == "" || name == server.Name {
+				result = append(result, server)
+			}
+		}
+		json.NewEncoder(w).Encode(schema.ServerListResponse{Servers: result})
+	})
+	for _, server := range servers {
+		env.Mux.HandleFunc("/servers/"+strconv.FormatInt(server.ID, 10), func(w http.ResponseWriter, _ *http.Request) {
+			json.NewEncoder(w).Encode(schema.ServerGetResponse{Server: server})
+		})
+	}
+}
+
+func TestInstances_InstanceMetadataProjects(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+	teamEnv := newTestEnv()
+	defer teamEnv.Teardown()
+
+	handleServers(env,
+		schema.Server{ID: 1, Name: "shared", ServerType: schema.ServerType{Name: "cx22"}, Location: schema.Location{Name: "fsn1"}},
+		schema.Server{ID: 3, Name: "duplicate", ServerType: schema.ServerType{Name: "cx22"}, Location: schema.Location{Name: "fsn1"}},
+	)
+	handleServers(teamEnv,
+		schema.Server{ID: 2, Name: "team", ServerType: schema.ServerType{Name: "cx32"}, Location: schema.Location{Name: "nbg1"}},
+		schema.Server{ID: 4, Name: "duplicate", ServerType: schema.ServerType{Name: "cx32"}, Location: schema.Location{Name: "nbg1"}},
+	)
+
+	projects := []*project{{name: "team-a", client: teamEnv.Client, serverCache: teamEnv.ServerCache}}
+	instances := newInstances(env.Client, nil, env.ServerCache, env.FloatingIPCache, projects, env.Recorder, 0, env.Cfg)
+
+	tests := []struct {
+		name           string
+		node           *corev1.Node
+		wantProviderID string
+		wantProject    string
+		wantErr        string
+	}{
+		{
+			name:           "default project by provider id",
+			node:           &corev1.Node{Spec: corev1.NodeSpec{ProviderID: "hcloud://1"}},
+			wantProviderID: "hcloud://1",
+		},
+		{
+			name:           "additional project by provider id",
+			node:           &corev1.Node{Spec: corev1.NodeSpec{ProviderID: "hcloud://team-a/2"}},
+			wantProviderID: "hcloud://team-a/2",
+			wantProject:    "team-a",
+		},
+		{
+			name:           "default project by name",
+			node:           &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "shared"}},
+			wantProviderID: "hcloud://1",
+		},
+		{
+			name:           "additional project by name",
+			node:           &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "team"}},
+			wantProviderID: "hcloud://team-a/2",
+			wantProject:    "team-a",
+		},
+		{
+			name:    "name in multiple projects",
+			node:    &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "duplicate"}},
+			wantErr: `found cloud servers in multiple projects for name "duplicate"`,
+		},
+		{
+			name:    "unknown project",
+			node:    &corev1.Node{Spec: corev1.NodeSpec{ProviderID: "hcloud://team-b/2"}},
+			wantErr: `unknown project "team-b" of provider id "hcloud://team-b/2"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := instances.InstanceMetadata(t.Context(), tt.node)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProviderID, metadata.ProviderID)
+			if tt.wantProject != "" {
+				assert.Equal(t, tt.wantProject, metadata.AdditionalLabels[ProjectLabel])
+			} else {
+				assert.NotContains(t, metadata.AdditionalLabels, ProjectLabel)
+			}
+		})
+	}
+}
+
+func TestCloud_CloudServersByProviderID(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+	teamEnv := newTestEnv()
+	defer teamEnv.Teardown()
+
+	handleServers(env, schema.Server{ID: 1, Name: "shared"})
+	handleServers(teamEnv, schema.Server{ID: 2, Name: "team"})
+
+	c := &cloud{
+		serverCache: cache.NewServerCache(env.Client, cache.ModeAll, 0),
+		projects: []*project{
+			{name: "team-a", client: teamEnv.Client, serverCache: cache.NewServerCache(teamEnv.Client, cache.ModeAll, 0)},
+		},
+	}
+	servers, err := c.cloudServersByProviderID(t.Context())
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+	assert.Equal(t, "shared", servers["hcloud://1"].Name)
+	assert.Equal(t, "team", servers["hcloud://team-a/2"].Name)
+}
+
+func TestCanonicalCloudProviderID(t *testing.T) {
+	providerID, ok := canonicalCloudProviderID("hcloud://team-a/2")
+	assert.True(t, ok)
+	assert.Equal(t, "hcloud://team-a/2", providerID)
+
+	_, ok = canonicalCloudProviderID("hrobot://2")
+	assert.False(t, ok)
+}
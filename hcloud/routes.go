@@ -7,6 +7,7 @@ import (
 	"slices"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -16,8 +17,11 @@ import (
 	"k8s.io/klog/v2"
 
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/hcops"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/health"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/providerid"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/utils"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
@@ -30,17 +34,19 @@ import (
 const routeTargetCacheMaxAge = 1 * time.Minute
 
 type routes struct {
-	client      *hcloud.Client
-	network     *hcloud.Network
-	serverCache *cache.Cache[hcloud.Server]
-	clusterCIDR *net.IPNet
-	recorder    record.EventRecorder
-	nodeLister  corelisters.NodeLister
+	client       *hcloud.Client
+	actionClient hcloud.IActionClient
+	network      *hcloud.Network
+	serverCache  *cache.Cache[hcloud.Server]
+	clusterCIDR  *net.IPNet
+	recorder     record.EventRecorder
+	nodeLister   corelisters.NodeLister
+	retry        hcops.RetryPolicy
 }
 
-func newRoutes(client *hcloud.Client, networkID int64, clusterCIDR string, recorder record.EventRecorder, nodeLister corelisters.NodeLister, serverCache *cache.Cache[hcloud.Server]) (*routes, error) {
+func newRoutes(client *hcloud.Client, networkID int64, clusterCIDR string, recorder record.EventRecorder, nodeLister corelisters.NodeLister, serverCache *cache.Cache[hcloud.Server]) (_ *routes, err error) {
 	const op = "hcloud/newRoutes"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
 
 	networkObj, _, err := client.Network.GetByID(context.Background(), networkID)
 	if err != nil {
@@ -56,18 +62,21 @@ func newRoutes(client *hcloud.Client, networkID int64, clusterCIDR string, recor
 	}
 
 	return &routes{
-		client:      client,
-		network:     networkObj,
-		serverCache: serverCache,
-		clusterCIDR: cidr,
-		recorder:    recorder,
-		nodeLister:  nodeLister,
+		client:       client,
+		actionClient: tracing.NewActionClient(&client.Action),
+		network:      networkObj,
+		serverCache:  serverCache,
+		clusterCIDR:  cidr,
+		recorder:     recorder,
+		nodeLister:   nodeLister,
 	}, nil
 }
 
-func (r *routes) reloadNetwork(ctx context.Context) error {
+func (r *routes) reloadNetwork(ctx context.Context) (err error) {
 	const op = "hcloud/reloadNetwork"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	networkObj, _, err := r.client.Network.GetByID(ctx, r.network.ID)
 	if err != nil {
@@ -81,9 +90,12 @@ func (r *routes) reloadNetwork(ctx context.Context) error {
 }
 
 // ListRoutes lists all managed routes that belong to the specified clusterName.
-func (r *routes) ListRoutes(ctx context.Context, _ string) ([]*cloudprovider.Route, error) {
+func (r *routes) ListRoutes(ctx context.Context, _ string) (_ []*cloudprovider.Route, err error) {
 	const op = "hcloud/ListRoutes"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
+	defer health.ObserveReconcile(health.Routes, &err)
 	ctx = cache.SetSubsystem(ctx, "routes")
 
 	if err := r.reloadNetwork(ctx); err != nil {
@@ -118,6 +130,7 @@ func (r *routes) ListRoutes(ctx context.Context, _ string) ([]*cloudprovider.Rou
 		}
 		routes = append(routes, cpRoute)
 	}
+	metrics.Routes.Set(float64(len(routes)))
 
 	return routes, nil
 }
@@ -125,9 +138,15 @@ func (r *routes) ListRoutes(ctx context.Context, _ string) ([]*cloudprovider.Rou
 // CreateRoute creates the described managed route
 // route.Name will be ignored, although the cloud-provider may use nameHint
 // to create a more user-meaningful name.
-func (r *routes) CreateRoute(ctx context.Context, _ string, _ string, route *cloudprovider.Route) error {
+func (r *routes) CreateRoute(ctx context.Context, _ string, _ string, route *cloudprovider.Route) (err error) {
 	const op = "hcloud/CreateRoute"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op,
+		attribute.String("k8s.route.destination_cidr", route.DestinationCIDR),
+		attribute.String("k8s.route.target_node", string(route.TargetNode)),
+	)
+	defer endSpan(&err)
+	defer health.ObserveReconcile(health.Routes, &err)
 	ctx = cache.SetSubsystem(ctx, "routes")
 
 	// Parse and return early if we detect IPv6 routes.
@@ -188,6 +207,9 @@ func (r *routes) resolveRouteTarget(ctx context.Context, nodeName string) (*core
 		if !isCloudServer {
 			return nil, nil, fmt.Errorf("node %s is not a cloud server, routes are only supported for cloud servers", node.Name)
 		}
+		if project, _ := providerid.ToProject(node.Spec.ProviderID); project != "" {
+			return nil, nil, fmt.Errorf("node %s is in the project %s, routes are only supported for servers in the project of the network", node.Name, project)
+		}
 		server, err = r.serverCache.ByID(ctx, id, cache.WithMaxAge(routeTargetCacheMaxAge))
 		if err != nil {
 			return nil, nil, fmt.Errorf("error looking up hcloud server by id %d for node %s: %w", id, nodeName, err)
@@ -238,15 +260,18 @@ func (r *routes) upsertRoute(ctx context.Context, gateway net.IP, cidr *net.IPNe
 			return nil
 		}
 
-		action, _, err := r.client.Network.DeleteRoute(ctx, r.network, hcloud.NetworkDeleteRouteOpts{
-			Route: existing,
+		err := hcops.Retry(ctx, "hcloud/CreateRoute", r.retry, func() error {
+			action, _, err := r.client.Network.DeleteRoute(ctx, r.network, hcloud.NetworkDeleteRouteOpts{
+				Route: existing,
+			})
+			if err != nil {
+				return err
+			}
+			return r.actionClient.WaitFor(ctx, action)
 		})
 		if err != nil {
 			return fmt.Errorf("error deleting route for %q via %q: %w", cidr.String(), gateway.String(), err)
 		}
-		if err := r.client.Action.WaitFor(ctx, action); err != nil {
-			return fmt.Errorf("error deleting route for %q via %q: %w", cidr.String(), gateway.String(), err)
-		}
 		klog.InfoS(
 			"deleted stale route with wrong gateway; recreating",
 			"node", nodeName,
@@ -261,9 +286,16 @@ func (r *routes) upsertRoute(ctx context.Context, gateway net.IP, cidr *net.IPNe
 			Gateway:     gateway,
 		},
 	}
-	action, _, err := r.client.Network.AddRoute(ctx, r.network, opts)
+	err := hcops.Retry(ctx, "hcloud/CreateRoute", r.retry, func() error {
+		action, _, err := r.client.Network.AddRoute(ctx, r.network, opts)
+		if err != nil {
+			return err
+		}
+		return r.actionClient.WaitFor(ctx, action)
+	})
 	if err != nil {
-		if hcloud.IsError(err, hcloud.ErrorCodeLocked, hcloud.ErrorCodeConflict) {
+		// The route controller retries conflicts of the node sooner.
+		if hcops.IsRetryable(err) {
 			return apierrors.NewConflict(
 				corev1.Resource("nodes"),
 				nodeName,
@@ -273,18 +305,20 @@ func (r *routes) upsertRoute(ctx context.Context, gateway net.IP, cidr *net.IPNe
 		return fmt.Errorf("error adding route for %q via %q: %w", cidr.String(), gateway.String(), err)
 	}
 
-	if err := r.client.Action.WaitFor(ctx, action); err != nil {
-		return fmt.Errorf("error adding route for %q via %q: %w", cidr.String(), gateway.String(), err)
-	}
-
 	return nil
 }
 
 // DeleteRoute deletes the specified managed route
 // Route should be as returned by ListRoutes.
-func (r *routes) DeleteRoute(ctx context.Context, _ string, route *cloudprovider.Route) error {
+func (r *routes) DeleteRoute(ctx context.Context, _ string, route *cloudprovider.Route) (err error) {
 	const op = "hcloud/DeleteRoute"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op,
+		attribute.String("k8s.route.destination_cidr", route.DestinationCIDR),
+		attribute.String("k8s.route.target_node", string(route.TargetNode)),
+	)
+	defer endSpan(&err)
+	defer health.ObserveReconcile(health.Routes, &err)
 
 	// Get target IP from current list of routes, routes can be uniquely identified by their destination cidr.
 	var ip net.IP
@@ -310,13 +344,16 @@ func (r *routes) DeleteRoute(ctx context.Context, _ string, route *cloudprovider
 		},
 	}
 
-	action, _, err := r.client.Network.DeleteRoute(ctx, r.network, opts)
+	err = hcops.Retry(ctx, op, r.retry, func() error {
+		action, _, err := r.client.Network.DeleteRoute(ctx, r.network, opts)
+		if err != nil {
+			return err
+		}
+		return r.actionClient.WaitFor(ctx, action)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	if err := r.client.Action.WaitFor(ctx, action); err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
 	return nil
 }
 
@@ -0,0 +1,151 @@
+package hcloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	cloudprovider "k8s.io/cloud-provider"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/ratelimit"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
+)
+
+const (
+	// ServerLabelPrefix is the prefix of the node labels synced from the labels
+	// of the server. All node labels with this prefix are owned by the sync.
+	ServerLabelPrefix = "server-label.hetzner.cloud/"
+
+	serverLabelSyncPeriod    = 30 * time.Second
+	serverLabelSyncSubsystem = "server_label_sync"
+)
+
+// initServerLabelSync periodically copies the configured server labels of
+// all Hetzner Cloud Servers to their nodes.
+func (c *cloud) initServerLabelSync(clientBuilder cloudprovider.ControllerClientBuilder, stop <-chan struct{}) {
+	client := clientBuilder.ClientOrDie("hccm-server-label-sync")
+
+	// The sync is not urgent, so it must not use the requests reserved for
+	// the controllers while the rate limit budget is low.
+	ctx := ratelimit.WithLowPriority(cache.SetSubsystem(wait.ContextForChannel(stop), serverLabelSyncSubsystem))
+	go wait.Until(func() {
+		if err := c.syncServerLabels(ctx, client.CoreV1().Nodes()); err != nil {
+			klog.ErrorS(err, "sync server labels to nodes")
+		}
+	}, serverLabelSyncPeriod, stop)
+}
+
+func (c *cloud) syncServerLabels(ctx context.Context, nodes corev1client.NodeInterface) (err error) {
+	const op = "hcloud/syncServerLabels"
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
+
+	nodeList, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	serversByProviderID, err := c.cloudServersByProviderID(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var errs []error
+	for _, node := range nodeList {
+		if node.Spec.ProviderID == "" {
+			continue
+		}
+		providerID, isCloudServer := canonicalCloudProviderID(node.Spec.ProviderID)
+		if !isCloudServer {
+			// Robot servers have no labels.
+			continue
+		}
+		server, ok := serversByProviderID[providerID]
+		if !ok {
+			// The node lifecycle controller removes nodes of deleted servers.
+			continue
+		}
+
+		patch, err := serverLabelPatch(node.Labels, server.Labels, c.cfg.Instance.ServerLabels)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: node %s: %w", op, node.Name, err))
+			continue
+		}
+		if patch == nil {
+			continue
+		}
+
+		klog.InfoS("sync server labels", "node", node.Name, "patch", string(patch))
+		if _, err := nodes.Patch(ctx, node.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("%s: node %s: %w", op, node.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// serverLabelPatch returns a merge patch, which sets the server labels matching
+// keys on the node, and removes the synced labels which no longer exist on the
+// server. It returns nil if the node labels are up to date.
+func serverLabelPatch(nodeLabels, serverLabels map[string]string, keys []string) ([]byte, error) {
+	want := make(map[string]string)
+	for key, value := range serverLabels {
+		if !matchesServerLabelKey(key, keys) {
+			continue
+		}
+		nodeKey := ServerLabelPrefix + key
+		if errs := validation.IsQualifiedName(nodeKey); len(errs) > 0 {
+			// Server label keys with their own prefix can not be nested below
+			// our prefix.
+			klog.V(4).InfoS("skipping server label", "key", key, "errs", errs)
+			continue
+		}
+		want[nodeKey] = value
+	}
+
+	changes := make(map[string]any)
+	for key, value := range want {
+		if current, ok := nodeLabels[key]; !ok || current != value {
+			changes[key] = value
+		}
+	}
+	for key := range nodeLabels {
+		if _, ok := want[key]; !ok && strings.HasPrefix(key, ServerLabelPrefix) {
+			changes[key] = nil
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	return json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": changes,
+		},
+	})
+}
+
+func matchesServerLabelKey(key string, keys []string) bool {
+	for _, k := range keys {
+		if prefix, ok := strings.CutSuffix(k, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+func TestServerLabelPatch(t *testing.T) {
+	tests := []struct {
+		name         string
+		nodeLabels   map[string]string
+		serverLabels map[string]string
+		want         string
+	}{
+		{
+			name:         "add matching labels",
+			nodeLabels:   map[string]string{"kubernetes.io/hostname": "node"},
+			serverLabels: map[string]string{"role": "worker", "team-a": "x", "env": "prod"},
+			want:         `{"metadata":{"labels":{"server-label.hetzner.cloud/role":"worker","server-label.hetzner.cloud/team-a":"x"}}}`,
+		},
+		{
+			name: "up to date",
+			nodeLabels: map[string]string{
+				"server-label.hetzner.cloud/role": "worker",
+			},
+			serverLabels: map[string]string{"role": "worker"},
+		},
+		{
+			name: "update and remove",
+			nodeLabels: map[string]string{
+				"server-label.hetzner.cloud/role":   "worker",
+				"server-label.hetzner.cloud/team-a": "x",
+				"role":                              "not-owned",
+			},
+			serverLabels: map[string]string{"role": "control-plane"},
+			want:         `{"metadata":{"labels":{"server-label.hetzner.cloud/role":"control-plane","server-label.hetzner.cloud/team-a":null}}}`,
+		},
+		{
+			name:         "skip keys with prefix",
+			serverLabels: map[string]string{"team-example.com/owner": "a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := serverLabelPatch(tt.nodeLabels, tt.serverLabels, []string{"role", "team-*"})
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, patch)
+			} else {
+				assert.JSONEq(t, tt.want, string(patch))
+			}
+		})
+	}
+}
+
+func TestCloud_SyncServerLabels(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+	env.Mux.HandleFunc("/servers", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.ServerListResponse{
+			Servers: []schema.Server{
+				{ID: 1, Name: "node1", Labels: map[string]string{"role": "worker"}},
+				{ID: 2, Name: "node2"},
+			},
+		})
+	})
+
+	nodes := []*corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Spec:       corev1.NodeSpec{ProviderID: "hcloud://1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node2", Labels: map[string]string{ServerLabelPrefix + "role": "worker"}},
+			Spec:       corev1.NodeSpec{ProviderID: "hcloud://2"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "robot", Labels: map[string]string{ServerLabelPrefix + "role": "worker"}},
+			Spec:       corev1.NodeSpec{ProviderID: "hrobot://3"},
+		},
+	}
+	client := fake.NewClientset(nodes[0], nodes[1], nodes[2])
+
+	env.Cfg.Instance.ServerLabels = []string{"role"}
+	c := &cloud{
+		serverCache: cache.NewServerCache(env.Client, cache.ModeAll, 0),
+		nodeLister:  nodeLister(t, nodes...),
+		cfg:         env.Cfg,
+	}
+	require.NoError(t, c.syncServerLabels(t.Context(), client.CoreV1().Nodes()))
+
+	node, err := client.CoreV1().Nodes().Get(t.Context(), "node1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{ServerLabelPrefix + "role": "worker"}, node.Labels)
+
+	node, err = client.CoreV1().Nodes().Get(t.Context(), "node2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, node.Labels)
+
+	// Labels of Robot servers are not touched.
+	node, err = client.CoreV1().Nodes().Get(t.Context(), "robot", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{ServerLabelPrefix + "role": "worker"}, node.Labels)
+}
@@ -0,0 +1,196 @@
+package hcloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	hrobotmodels "github.com/syself/hrobot-go/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	cloudprovider "k8s.io/cloud-provider"
+	nodehelpers "k8s.io/cloud-provider/node/helpers"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/providerid"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/ratelimit"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+const (
+	// StateTaintPrefix is the prefix of the node taints reflecting the state of
+	// the server. All taints with this prefix and one of [config.ServerStates]
+	// are owned by the sync.
+	StateTaintPrefix = "instance.hetzner.cloud/"
+
+	stateTaintSyncPeriod    = 30 * time.Second
+	stateTaintSyncSubsystem = "state_taint_sync"
+
+	robotServerStatusReady = "ready"
+)
+
+// initStateTaintSync periodically updates the taints of all nodes according to
+// the state of their servers.
+func (c *cloud) initStateTaintSync(clientBuilder cloudprovider.ControllerClientBuilder, stop <-chan struct{}) {
+	client := clientBuilder.ClientOrDie("hccm-state-taint-sync")
+
+	// The sync is not urgent, so it must not use the requests reserved for
+	// the controllers while the rate limit budget is low.
+	ctx := ratelimit.WithLowPriority(cache.SetSubsystem(wait.ContextForChannel(stop), stateTaintSyncSubsystem))
+	go wait.Until(func() {
+		if err := c.syncStateTaints(ctx, client); err != nil {
+			klog.ErrorS(err, "sync server state taints to nodes")
+		}
+	}, stateTaintSyncPeriod, stop)
+}
+
+func (c *cloud) syncStateTaints(ctx context.Context, client kubernetes.Interface) (err error) {
+	const op = "hcloud/syncStateTaints"
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
+
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	serversByProviderID, err := c.cloudServersByProviderID(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var errs []error
+
+	// If the Robot servers can not be listed, e.g. while the rate limit is
+	// exceeded, only the Robot nodes are skipped.
+	var robotServersByNumber map[int64]*hrobotmodels.Server
+	if c.robotClient != nil {
+		robotServers, err := c.robotClient.ServerGetList()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: skipping Robot nodes: %w", op, err))
+		}
+		robotServersByNumber = make(map[int64]*hrobotmodels.Server, len(robotServers))
+		for i := range robotServers {
+			robotServersByNumber[int64(robotServers[i].ServerNumber)] = &robotServers[i]
+		}
+	}
+
+	for _, node := range nodes {
+		if node.Spec.ProviderID == "" {
+			continue
+		}
+		id, isCloudServer, err := providerid.ToServerID(node.Spec.ProviderID)
+		if err != nil {
+			continue
+		}
+
+		var states []config.ServerState
+		if isCloudServer {
+			providerID, _ := canonicalCloudProviderID(node.Spec.ProviderID)
+			server, ok := serversByProviderID[providerID]
+			if !ok {
+				continue
+			}
+			states = cloudServerStates(server)
+		} else {
+			server, ok := robotServersByNumber[id]
+			if !ok {
+				continue
+			}
+			states = robotServerStates(server)
+		}
+
+		taints, changed := stateTaints(node.Spec.Taints, states, c.cfg.Instance.StateTaints)
+		if !changed {
+			continue
+		}
+
+		klog.InfoS("sync server state taints", "node", node.Name, "states", states)
+		newNode := node.DeepCopy()
+		newNode.Spec.Taints = taints
+		if err := nodehelpers.PatchNodeTaints(client, node.Name, node, newNode); err != nil {
+			errs = append(errs, fmt.Errorf("%s: node %s: %w", op, node.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func cloudServerStates(server *hcloud.Server) []config.ServerState {
+	var states []config.ServerState
+	if server.Locked {
+		states = append(states, config.ServerStateLocked)
+	}
+	if server.RescueEnabled {
+		states = append(states, config.ServerStateRescue)
+	}
+	if server.Status == hcloud.ServerStatusMigrating {
+		states = append(states, config.ServerStateMigrating)
+	}
+	return states
+}
+
+func robotServerStates(server *hrobotmodels.Server) []config.ServerState {
+	// The Robot API does not expose a reset in progress, but the server is
+	// "in process" while it is not ready.
+	if server.Status != "" && server.Status != robotServerStatusReady {
+		return []config.ServerState{config.ServerStateInProcess}
+	}
+	return nil
+}
+
+// stateTaints returns the taints of a node with the taints for states, for
+// which effects configures a taint. Owned taints of states the server is no
+// longer in, or which are no longer configured, are removed. All other taints
+// are kept.
+func stateTaints(current []corev1.Taint, states []config.ServerState, effects map[config.ServerState]corev1.TaintEffect) ([]corev1.Taint, bool) {
+	owned := func(key string) bool {
+		return slices.ContainsFunc(config.ServerStates, func(state config.ServerState) bool {
+			return key == StateTaintPrefix+string(state)
+		})
+	}
+
+	want := make(map[string]corev1.TaintEffect)
+	for _, state := range states {
+		if effect, ok := effects[state]; ok {
+			want[StateTaintPrefix+string(state)] = effect
+		}
+	}
+
+	taints := make([]corev1.Taint, 0, len(current)+len(want))
+	changed := false
+	for _, taint := range current {
+		if !owned(taint.Key) {
+			taints = append(taints, taint)
+			continue
+		}
+		if effect, ok := want[taint.Key]; ok && taint.Effect == effect {
+			taints = append(taints, taint)
+			delete(want, taint.Key)
+			continue
+		}
+		changed = true
+	}
+
+	// Sorted to keep the order of taints stable.
+	for _, key := range slices.Sorted(maps.Keys(want)) {
+		taint := corev1.Taint{Key: key, Value: "true", Effect: want[key]}
+		if taint.Effect == corev1.TaintEffectNoExecute {
+			taint.TimeAdded = &metav1.Time{Time: time.Now()}
+		}
+		taints = append(taints, taint)
+		changed = true
+	}
+
+	return taints, changed
+}
@@ -0,0 +1,189 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+func TestStateTaints(t *testing.T) {
+	effects := map[config.ServerState]corev1.TaintEffect{
+		config.ServerStateLocked: corev1.TaintEffectNoSchedule,
+		config.ServerStateRescue: corev1.TaintEffectNoExecute,
+	}
+	other := corev1.Taint{Key: "example.com/other", Effect: corev1.TaintEffectNoSchedule}
+	locked := corev1.Taint{Key: "instance.hetzner.cloud/locked", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+	migrating := corev1.Taint{Key: "instance.hetzner.cloud/migrating", Value: "true", Effect: corev1.TaintEffectNoSchedule}
+
+	tests := []struct {
+		name        string
+		current     []corev1.Taint
+		states      []config.ServerState
+		want        []corev1.Taint
+		wantChanged bool
+	}{
+		{
+			name:    "no states",
+			current: []corev1.Taint{other},
+			want:    []corev1.Taint{other},
+		},
+		{
+			name:        "add taint",
+			current:     []corev1.Taint{other},
+			states:      []config.ServerState{config.ServerStateLocked, config.ServerStateMigrating},
+			want:        []corev1.Taint{other, locked},
+			wantChanged: true,
+		},
+		{
+			name:    "up to date",
+			current: []corev1.Taint{locked, other},
+			states:  []config.ServerState{config.ServerStateLocked},
+			want:    []corev1.Taint{locked, other},
+		},
+		{
+			name:        "remove taints",
+			current:     []corev1.Taint{locked, other, migrating},
+			want:        []corev1.Taint{other},
+			wantChanged: true,
+		},
+		{
+			name:        "update effect",
+			current:     []corev1.Taint{{Key: "instance.hetzner.cloud/locked", Value: "true", Effect: corev1.TaintEffectNoExecute}},
+			states:      []config.ServerState{config.ServerStateLocked},
+			want:        []corev1.Taint{locked},
+			wantChanged: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taints, changed := stateTaints(tt.current, tt.states, effects)
+			assert.Equal(t, tt.want, taints)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+
+	t.Run("NoExecute sets time added", func(t *testing.T) {
+		taints, changed := stateTaints(nil, []config.ServerState{config.ServerStateRescue}, effects)
+		assert.True(t, changed)
+		require.Len(t, taints, 1)
+		assert.Equal(t, "instance.hetzner.cloud/rescue", taints[0].Key)
+		assert.NotNil(t, taints[0].TimeAdded)
+	})
+}
+
+func TestServerStates(t *testing.T) {
+	assert.Empty(t, cloudServerStates(&hcloud.Server{Status: hcloud.ServerStatusRunning}))
+	assert.Equal(t,
+		[]config.ServerState{config.ServerStateLocked, config.ServerStateRescue, config.ServerStateMigrating},
+		cloudServerStates(&hcloud.Server{Status: hcloud.ServerStatusMigrating, Locked: true, RescueEnabled: true}),
+	)
+
+	assert.Empty(t, robotServerStates(&hrobotmodels.Server{Status: "ready"}))
+	assert.Equal(t, []config.ServerState{config.ServerStateInProcess}, robotServerStates(&hrobotmodels.Server{Status: "in process"}))
+}
+
+func TestCloud_SyncStateTaints(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+	env.Mux.HandleFunc("/servers", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.ServerListResponse{
+			Servers: []schema.Server{
+				{ID: 1, Name: "node1", Locked: true},
+				{ID: 2, Name: "node2"},
+			},
+		})
+	})
+
+	nodes := []*corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Spec:       corev1.NodeSpec{ProviderID: "hcloud://1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+			Spec: corev1.NodeSpec{ProviderID: "hcloud://2", Taints: []corev1.Taint{
+				{Key: "instance.hetzner.cloud/locked", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+			}},
+		},
+	}
+	client := fake.NewClientset(nodes[0], nodes[1])
+
+	env.Cfg.Instance.StateTaints = map[config.ServerState]corev1.TaintEffect{
+		config.ServerStateLocked: corev1.TaintEffectNoSchedule,
+	}
+	c := &cloud{
+		serverCache: cache.NewServerCache(env.Client, cache.ModeAll, 0),
+		nodeLister:  nodeLister(t, nodes...),
+		cfg:         env.Cfg,
+	}
+	require.NoError(t, c.syncStateTaints(t.Context(), client))
+
+	node, err := client.CoreV1().Nodes().Get(t.Context(), "node1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.Taint{
+		{Key: "instance.hetzner.cloud/locked", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+	}, node.Spec.Taints)
+
+	node, err = client.CoreV1().Nodes().Get(t.Context(), "node2", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, node.Spec.Taints)
+}
+
+func TestCloud_SyncStateTaintsRobotError(t *testing.T) {
+	env := newTestEnv()
+	defer env.Teardown()
+	env.Mux.HandleFunc("/servers", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.ServerListResponse{
+			Servers: []schema.Server{{ID: 1, Name: "node1", Locked: true}},
+		})
+	})
+	env.Mux.HandleFunc("/robot/server", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(hrobotmodels.ErrorResponse{Error: hrobotmodels.Error{Code: hrobotmodels.ErrorCodeRateLimitExceeded}})
+	})
+
+	nodes := []*corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+			Spec:       corev1.NodeSpec{ProviderID: "hcloud://1"},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "robot"},
+			Spec:       corev1.NodeSpec{ProviderID: "hrobot://321"},
+		},
+	}
+	client := fake.NewClientset(nodes[0], nodes[1])
+
+	env.Cfg.Instance.StateTaints = map[config.ServerState]corev1.TaintEffect{
+		config.ServerStateLocked:    corev1.TaintEffectNoSchedule,
+		config.ServerStateInProcess: corev1.TaintEffectNoSchedule,
+	}
+	c := &cloud{
+		serverCache: cache.NewServerCache(env.Client, cache.ModeAll, 0),
+		robotClient: env.RobotClient,
+		nodeLister:  nodeLister(t, nodes...),
+		cfg:         env.Cfg,
+	}
+	err := c.syncStateTaints(t.Context(), client)
+	assert.ErrorContains(t, err, "skipping Robot nodes")
+
+	// The taints of Cloud nodes are still synced.
+	node, err := client.CoreV1().Nodes().Get(t.Context(), "node1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []corev1.Taint{
+		{Key: "instance.hetzner.cloud/locked", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+	}, node.Spec.Taints)
+}
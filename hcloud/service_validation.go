@@ -0,0 +1,90 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+)
+
+const (
+	// ServiceValidationWebhookName is the name of the validating webhook for
+	// Load Balancer Services.
+	ServiceValidationWebhookName = "hcloud-service-validation"
+
+	// ServiceValidationWebhookPath is the path the validating webhook for Load
+	// Balancer Services is served on.
+	ServiceValidationWebhookPath = "/validate-service"
+)
+
+// ValidateServiceAdmission validates the annotations of Services of type
+// LoadBalancer, so that invalid values are rejected when the Service is
+// applied instead of failing during reconciliation.
+//
+// On updates, only errors which the previous version of the Service did not
+// have are rejected. Otherwise, a Service with an annotation that became
+// invalid could not be updated anymore, not even to remove the finalizer of
+// the hcloud-cloud-controller-manager.
+func ValidateServiceAdmission(req *admissionv1.AdmissionRequest) (_ *admissionv1.AdmissionResponse, err error) {
+	const op = "hcloud/ValidateServiceAdmission"
+	defer metrics.ObserveOperation(op)(&err)
+
+	if req.Kind.Group != "" || req.Kind.Kind != "Service" {
+		return nil, fmt.Errorf("%s: unexpected kind %s", op, req.Kind.String())
+	}
+
+	// The object is empty for DELETE requests.
+	if len(req.Object.Raw) == 0 {
+		return &admissionv1.AdmissionResponse{Allowed: true}, nil
+	}
+
+	svc := &corev1.Service{}
+	if err := json.Unmarshal(req.Object.Raw, svc); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if svc.Spec.Type != corev1.ServiceTypeLoadBalancer || svc.DeletionTimestamp != nil {
+		return &admissionv1.AdmissionResponse{Allowed: true}, nil
+	}
+
+	errs := annotation.ValidateService(svc)
+
+	if req.Operation == admissionv1.Update && len(errs) > 0 && len(req.OldObject.Raw) > 0 {
+		oldSvc := &corev1.Service{}
+		if err := json.Unmarshal(req.OldObject.Raw, oldSvc); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if oldSvc.Spec.Type == corev1.ServiceTypeLoadBalancer {
+			oldErrs := annotation.ValidateService(oldSvc)
+			errs = slices.DeleteFunc(errs, func(e *field.Error) bool {
+				return slices.ContainsFunc(oldErrs, func(oldErr *field.Error) bool {
+					return oldErr.Error() == e.Error()
+				})
+			})
+		}
+	}
+
+	if len(errs) == 0 {
+		return &admissionv1.AdmissionResponse{Allowed: true}, nil
+	}
+
+	klog.InfoS("rejected invalid Load Balancer Service", "op", op, "service", klog.KObj(svc), "errors", errs.ToAggregate())
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: errs.ToAggregate().Error(),
+		},
+	}, nil
+}
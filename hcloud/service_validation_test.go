@@ -0,0 +1,129 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+)
+
+func TestValidateServiceAdmission(t *testing.T) {
+	newRequest := func(t *testing.T, svcType corev1.ServiceType, annotations map[string]string) *admissionv1.AdmissionRequest {
+		raw, err := json.Marshal(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc", Annotations: annotations},
+			Spec:       corev1.ServiceSpec{Type: svcType},
+		})
+		require.NoError(t, err)
+		return &admissionv1.AdmissionRequest{
+			Kind:   metav1.GroupVersionKind{Version: "v1", Kind: "Service"},
+			Object: runtime.RawExtension{Raw: raw},
+		}
+	}
+
+	invalid := map[string]string{
+		string(annotation.LBSvcHealthCheckInterval): "5",
+	}
+
+	t.Run("valid Service", func(t *testing.T) {
+		resp, err := ValidateServiceAdmission(newRequest(t, corev1.ServiceTypeLoadBalancer, map[string]string{
+			string(annotation.LBSvcHealthCheckInterval): "5s",
+		}))
+		require.NoError(t, err)
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("invalid Service", func(t *testing.T) {
+		resp, err := ValidateServiceAdmission(newRequest(t, corev1.ServiceTypeLoadBalancer, invalid))
+		require.NoError(t, err)
+		assert.False(t, resp.Allowed)
+		assert.Equal(t, metav1.StatusReasonInvalid, resp.Result.Reason)
+		assert.Contains(t, resp.Result.Message, "metadata.annotations[load-balancer.hetzner.cloud/health-check-interval]")
+	})
+
+	t.Run("ignore Services not of type LoadBalancer", func(t *testing.T) {
+		resp, err := ValidateServiceAdmission(newRequest(t, corev1.ServiceTypeClusterIP, invalid))
+		require.NoError(t, err)
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("ignore Services being deleted", func(t *testing.T) {
+		raw, err := json.Marshal(&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "default",
+				Name:              "svc",
+				Annotations:       invalid,
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			},
+			Spec: corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+		})
+		require.NoError(t, err)
+		req := newRequest(t, corev1.ServiceTypeLoadBalancer, invalid)
+		req.Operation = admissionv1.Update
+		req.Object.Raw = raw
+
+		resp, err := ValidateServiceAdmission(req)
+		require.NoError(t, err)
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req := newRequest(t, corev1.ServiceTypeLoadBalancer, invalid)
+		req.Operation = admissionv1.Delete
+		req.OldObject = req.Object
+		req.Object = runtime.RawExtension{}
+
+		resp, err := ValidateServiceAdmission(req)
+		require.NoError(t, err)
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("update keeps invalid annotation", func(t *testing.T) {
+		req := newRequest(t, corev1.ServiceTypeLoadBalancer, map[string]string{
+			string(annotation.LBSvcHealthCheckInterval): "5",
+			string(annotation.LBAlgorithmType):          "least_connections",
+		})
+		req.Operation = admissionv1.Update
+		req.OldObject = newRequest(t, corev1.ServiceTypeLoadBalancer, invalid).Object
+
+		resp, err := ValidateServiceAdmission(req)
+		require.NoError(t, err)
+		assert.True(t, resp.Allowed)
+	})
+
+	t.Run("update changes invalid annotation", func(t *testing.T) {
+		req := newRequest(t, corev1.ServiceTypeLoadBalancer, map[string]string{
+			string(annotation.LBSvcHealthCheckInterval): "6",
+		})
+		req.Operation = admissionv1.Update
+		req.OldObject = newRequest(t, corev1.ServiceTypeLoadBalancer, invalid).Object
+
+		resp, err := ValidateServiceAdmission(req)
+		require.NoError(t, err)
+		assert.False(t, resp.Allowed)
+	})
+
+	t.Run("update adds invalid annotation", func(t *testing.T) {
+		req := newRequest(t, corev1.ServiceTypeLoadBalancer, invalid)
+		req.Operation = admissionv1.Update
+		req.OldObject = newRequest(t, corev1.ServiceTypeLoadBalancer, nil).Object
+
+		resp, err := ValidateServiceAdmission(req)
+		require.NoError(t, err)
+		assert.False(t, resp.Allowed)
+	})
+
+	t.Run("unexpected kind", func(t *testing.T) {
+		req := newRequest(t, corev1.ServiceTypeLoadBalancer, nil)
+		req.Kind.Kind = "Pod"
+		_, err := ValidateServiceAdmission(req)
+		assert.EqualError(t, err, "hcloud/ValidateServiceAdmission: unexpected kind /v1, Kind=Pod")
+	})
+}
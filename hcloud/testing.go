@@ -77,12 +77,14 @@ func (tt *LoadBalancerTestCase) run(t *testing.T) {
 		tt.Mock(t, tt)
 	}
 
-	lbConfig := &config.LoadBalancerConfiguration{
-		PrivateIngressEnabled: *tt.UsePrivateIngressDefault,
-		IPv6Enabled:           *tt.UseIPv6Default,
-	}
+	cfg := config.NewStore(config.HCCMConfiguration{
+		LoadBalancer: config.LoadBalancerConfiguration{
+			PrivateIngressEnabled: *tt.UsePrivateIngressDefault,
+			IPv6Enabled:           *tt.UseIPv6Default,
+		},
+	})
 
-	tt.LoadBalancers = newLoadBalancers(tt.LBOps, lbConfig)
+	tt.LoadBalancers = newLoadBalancers(tt.LBOps, cfg)
 	tt.Perform(t, tt)
 
 	tt.LBOps.AssertExpectations(t)
@@ -0,0 +1,103 @@
+// Package agent implements the node agent. It runs on every node and publishes
+// the ID of its server as annotation of the node, so the
+// hcloud-cloud-controller-manager can resolve the server of a node without
+// matching the node name against the server names.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/metadata"
+)
+
+// AnnotationServerID is the ID of the Hetzner Cloud Server of the node. Other
+// metadata, e.g. the addresses, is not published, as the
+// hcloud-cloud-controller-manager can not trust it and reads it from the API.
+const AnnotationServerID = "node-agent.hetzner.cloud/server-id"
+
+// Info is the metadata of a server published by the agent.
+type Info struct {
+	ServerID int64
+}
+
+// ReadInfo reads the metadata of the server the agent runs on.
+func ReadInfo(ctx context.Context, client *metadata.Client) (Info, error) {
+	serverID, err := client.InstanceIDWithContext(ctx)
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to read instance id: %w", err)
+	}
+	return Info{ServerID: serverID}, nil
+}
+
+// Annotations returns the node annotations for info.
+func (i Info) Annotations() map[string]string {
+	return map[string]string{
+		AnnotationServerID: strconv.FormatInt(i.ServerID, 10),
+	}
+}
+
+// ServerID returns the server ID published by the agent in the annotations of a
+// node. It returns false, if the agent did not publish it yet.
+func ServerID(annotations map[string]string) (int64, bool, error) {
+	value, ok := annotations[AnnotationServerID]
+	if !ok {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid annotation %s=%q: %w", AnnotationServerID, value, err)
+	}
+	return id, true, nil
+}
+
+// Agent publishes the ID of the server it runs on as annotation of its node.
+type Agent struct {
+	Metadata   *metadata.Client
+	KubeClient kubernetes.Interface
+	NodeName   string
+}
+
+// Sync reads the metadata and updates the annotations of the node, if they
+// changed.
+func (a *Agent) Sync(ctx context.Context) error {
+	info, err := ReadInfo(ctx, a.Metadata)
+	if err != nil {
+		return err
+	}
+	annotations := info.Annotations()
+
+	node, err := a.KubeClient.CoreV1().Nodes().Get(ctx, a.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node %s: %w", a.NodeName, err)
+	}
+
+	changed := make(map[string]string)
+	for key, value := range annotations {
+		if current, ok := node.Annotations[key]; !ok || current != value {
+			changed[key] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{"annotations": changed},
+	})
+	if err != nil {
+		return err
+	}
+	klog.InfoS("update node metadata annotations", "node", a.NodeName, "annotations", changed)
+	if _, err := a.KubeClient.CoreV1().Nodes().Patch(ctx, a.NodeName, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to patch node %s: %w", a.NodeName, err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/metadata"
+)
+
+func newMetadataServer(t *testing.T, values map[string]string) *metadata.Client {
+	mux := http.NewServeMux()
+	for path, value := range values {
+		mux.HandleFunc(path, func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte(value))
+		})
+	}
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return metadata.NewClient(metadata.WithEndpoint(server.URL))
+}
+
+func TestAgent_Sync(t *testing.T) {
+	metadataClient := newMetadataServer(t, map[string]string{
+		"/instance-id": "42",
+	})
+	kubeClient := fake.NewClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node", Annotations: map[string]string{"example.com/other": "kept"}},
+	})
+
+	a := &Agent{Metadata: metadataClient, KubeClient: kubeClient, NodeName: "node"}
+	require.NoError(t, a.Sync(t.Context()))
+
+	node, err := kubeClient.CoreV1().Nodes().Get(t.Context(), "node", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"example.com/other":                  "kept",
+		"node-agent.hetzner.cloud/server-id": "42",
+	}, node.Annotations)
+
+	// The node is only patched, if the annotations changed.
+	kubeClient.ClearActions()
+	require.NoError(t, a.Sync(t.Context()))
+	for _, action := range kubeClient.Actions() {
+		assert.NotEqual(t, "patch", action.GetVerb())
+	}
+}
+
+func TestServerID(t *testing.T) {
+	id, ok, err := ServerID(map[string]string{AnnotationServerID: "42"})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), id)
+
+	_, ok, err = ServerID(nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = ServerID(map[string]string{AnnotationServerID: "abc"})
+	assert.EqualError(t, err, `invalid annotation node-agent.hetzner.cloud/server-id="abc": strconv.ParseInt: parsing "abc": invalid syntax`)
+}
+
+func TestAgent_RunRetriesProbe(t *testing.T) {
+	var hostnameRequests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hostname", func(w http.ResponseWriter, _ *http.Request) {
+		// The metadata service is not reachable on the first probe.
+		if hostnameRequests.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte("my-server"))
+	})
+	mux.HandleFunc("/instance-id", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("42"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	kubeClient := fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node"}})
+	a := &Agent{
+		Metadata:   metadata.NewClient(metadata.WithEndpoint(server.URL)),
+		KubeClient: kubeClient,
+		NodeName:   "node",
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go a.Run(ctx, time.Minute)
+
+	assert.EventuallyWithT(t, func(t *assert.CollectT) {
+		node, err := kubeClient.CoreV1().Nodes().Get(ctx, "node", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "42", node.Annotations[AnnotationServerID])
+	}, 5*time.Second, 50*time.Millisecond)
+}
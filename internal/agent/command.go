@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/metadata"
+)
+
+const defaultSyncInterval = 5 * time.Minute
+
+// NewCommand returns the agent subcommand.
+func NewCommand(version string) *cobra.Command {
+	var (
+		kubeconfig   string
+		nodeName     string
+		syncInterval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Publish the ID of the server as annotation of its Node",
+		Long: `Publish the ID of the server as annotation of its Node.
+
+The agent runs on every Node, usually as a DaemonSet. It reads the server ID
+from the metadata service, and publishes it as annotation of the Node. The
+hcloud-cloud-controller-manager uses it to resolve the server of Nodes without
+a provider ID, if HCLOUD_INSTANCES_NODE_AGENT_ENABLED is set.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if nodeName == "" {
+				return errors.New("--node-name or the NODE_NAME environment variable is required")
+			}
+
+			// Without a kubeconfig, the in-cluster configuration is used.
+			restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+			if err != nil {
+				return err
+			}
+			kubeClient, err := kubernetes.NewForConfig(restConfig)
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			a := &Agent{
+				Metadata:   metadata.NewClient(metadata.WithApplication("hcloud-cloud-controller", version)),
+				KubeClient: kubeClient,
+				NodeName:   nodeName,
+			}
+			a.Run(ctx, syncInterval)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig file. Required, if not running in a cluster.")
+	cmd.Flags().StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "Name of the Node the agent runs on.")
+	cmd.Flags().DurationVar(&syncInterval, "sync-interval", defaultSyncInterval, "Interval in which the annotations are updated.")
+
+	// The usage function of the root command prints its own flags.
+	cmd.SetUsageFunc((&cobra.Command{}).UsageFunc())
+	cmd.SetHelpFunc((&cobra.Command{}).HelpFunc())
+
+	return cmd
+}
+
+// Run syncs the annotations every interval until ctx is done. The metadata
+// service is probed with a backoff up to interval, until it is reachable. On
+// servers without a metadata service, e.g. Robot servers, the agent keeps
+// probing, so it can run as a DaemonSet on all Nodes.
+func (a *Agent) Run(ctx context.Context, interval time.Duration) {
+	backoff := wait.Backoff{
+		Duration: time.Second,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    math.MaxInt32,
+		Cap:      interval,
+	}
+	for !a.Metadata.IsHcloudServerWithContext(ctx) {
+		delay := backoff.Step()
+		klog.InfoS("the metadata service is not reachable, retrying", "node", a.NodeName, "delay", delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := a.Sync(ctx); err != nil {
+			klog.ErrorS(err, "sync node metadata annotations", "node", a.NodeName)
+		}
+	}, interval)
+}
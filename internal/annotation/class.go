@@ -0,0 +1,30 @@
+package annotation
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ClassDefaults contains all annotations which can be configured for a Load
+// Balancer class. In addition to the [NamespaceDefaults], a class can also
+// configure the network and protocol related settings of a Load Balancer.
+var ClassDefaults = slices.Concat(NamespaceDefaults, []Name{
+	LBAlgorithmType,
+	LBDisablePrivateIngress,
+	LBDisablePublicNetwork,
+	LBIPv6Disabled,
+	LBSvcProxyProtocol,
+	PrivateSubnetIPRange,
+})
+
+// ServiceWithClassDefaults returns svc with the defaults of a Load Balancer
+// class applied. defaults maps annotation names to their values.
+//
+// Annotations set on svc always take precedence over defaults. Only the
+// annotations listed in [ClassDefaults] are applied. If nothing is applied, svc
+// is returned unchanged. Otherwise, a copy of svc is returned and svc itself
+// is not modified.
+func ServiceWithClassDefaults(svc *corev1.Service, defaults map[string]string) *corev1.Service {
+	return serviceWithDefaults(svc, defaults, ClassDefaults)
+}
@@ -0,0 +1,36 @@
+package annotation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+)
+
+func TestServiceWithClassDefaults(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				string(annotation.LBType): "lb31",
+			},
+		},
+	}
+	original := svc.DeepCopy()
+
+	actual := annotation.ServiceWithClassDefaults(svc, map[string]string{
+		string(annotation.LBType):                 "lb11",
+		string(annotation.LBSvcProxyProtocol):     "true",
+		string(annotation.LBDisablePublicNetwork): "true",
+		string(annotation.LBName):                 "not-allowed",
+	})
+
+	assert.Equal(t, map[string]string{
+		string(annotation.LBType):                 "lb31",
+		string(annotation.LBSvcProxyProtocol):     "true",
+		string(annotation.LBDisablePublicNetwork): "true",
+	}, actual.Annotations)
+	assert.Equal(t, original, svc, "input Service must not be modified")
+}
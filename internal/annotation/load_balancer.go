@@ -12,7 +12,6 @@ const (
 	// the Load Balancer.
 	//
 	// Type: string
-	// Read-only: true
 	LBPublicIPv4RDNS Name = "load-balancer.hetzner.cloud/ipv4-rdns"
 
 	// LBPublicIPv6 is the public IPv6 address assigned to the Load Balancer by
@@ -26,7 +25,6 @@ const (
 	// the Load Balancer.
 	//
 	// Type: string
-	// Read-only: true
 	LBPublicIPv6RDNS Name = "load-balancer.hetzner.cloud/ipv6-rdns"
 
 	// LBIPv6Disabled disables the use of IPv6 for the Load Balancer.
@@ -42,6 +40,20 @@ const (
 	// Type: string
 	LBName Name = "load-balancer.hetzner.cloud/name"
 
+	// LBLabels is a comma separated list of key=value pairs, which are added
+	// as labels to the Load Balancer. Labels which are removed from the
+	// annotation are not removed from the Load Balancer.
+	//
+	// Type: string
+	LBLabels Name = "load-balancer.hetzner.cloud/labels"
+
+	// LBProfile is the name of the HCloudLoadBalancerProfile used by the
+	// Service. The settings of the profile are used as defaults for the
+	// annotations of the Service.
+	//
+	// Type: string
+	LBProfile Name = "load-balancer.hetzner.cloud/profile"
+
 	// LBDisablePublicNetwork disables the public network of the Hetzner Cloud
 	// Load Balancer. It will still have a public network assigned, but all
 	// traffic is routed over the private network.
@@ -0,0 +1,47 @@
+// Code generated by tools/doc_generation.go. DO NOT EDIT.
+
+package annotation
+
+var registry = map[Name]Info{
+	LBPublicIPv4:                    {Type: "string", ReadOnly: true},
+	LBPublicIPv4RDNS:                {Type: "string", ReadOnly: false},
+	LBPublicIPv6:                    {Type: "string", ReadOnly: true},
+	LBPublicIPv6RDNS:                {Type: "string", ReadOnly: false},
+	LBIPv6Disabled:                  {Type: "bool", ReadOnly: false},
+	LBName:                          {Type: "string", ReadOnly: false},
+	LBLabels:                        {Type: "string", ReadOnly: false},
+	LBProfile:                       {Type: "string", ReadOnly: false},
+	LBDisablePublicNetwork:          {Type: "bool", ReadOnly: false},
+	LBDisablePrivateIngress:         {Type: "bool", ReadOnly: false},
+	LBUsePrivateIP:                  {Type: "bool", ReadOnly: false},
+	LBPrivateIPv4:                   {Type: "string", ReadOnly: false},
+	PrivateSubnetIPRange:            {Type: "string", ReadOnly: false},
+	LBHostname:                      {Type: "string", ReadOnly: false},
+	LBSvcProtocol:                   {Type: "tcp | http | https", ReadOnly: false},
+	LBAlgorithmType:                 {Type: "round_robin | least_connections", ReadOnly: false},
+	LBType:                          {Type: "string", ReadOnly: false},
+	LBLocation:                      {Type: "string", ReadOnly: false},
+	LBNetworkZone:                   {Type: "string", ReadOnly: false},
+	LBNodeSelector:                  {Type: "string", ReadOnly: false},
+	LBSvcProxyProtocol:              {Type: "bool", ReadOnly: false},
+	LBSvcHTTPCookieName:             {Type: "string", ReadOnly: false},
+	LBSvcHTTPCookieLifetime:         {Type: "int", ReadOnly: false},
+	LBSvcHTTPTimeoutIdle:            {Type: "duration", ReadOnly: false},
+	LBSvcHTTPCertificateType:        {Type: "uploaded | managed", ReadOnly: false},
+	LBSvcHTTPCertificates:           {Type: "string", ReadOnly: false},
+	LBSvcHTTPManagedCertificateName: {Type: "string", ReadOnly: false},
+	LBSvcHTTPManagedCertificateUseACMEStaging: {Type: "bool", ReadOnly: false},
+	LBSvcHTTPManagedCertificateDomains:        {Type: "string", ReadOnly: false},
+	LBSvcRedirectHTTP:                         {Type: "bool", ReadOnly: false},
+	LBSvcHTTPStickySessions:                   {Type: "bool", ReadOnly: false},
+	LBSvcHealthCheckProtocol:                  {Type: "tcp | http | https", ReadOnly: false},
+	LBSvcHealthCheckPort:                      {Type: "int", ReadOnly: false},
+	LBSvcHealthCheckInterval:                  {Type: "int", ReadOnly: false},
+	LBSvcHealthCheckTimeout:                   {Type: "int", ReadOnly: false},
+	LBSvcHealthCheckRetries:                   {Type: "int", ReadOnly: false},
+	LBSvcHealthCheckHTTPDomain:                {Type: "string", ReadOnly: false},
+	LBSvcHealthCheckHTTPPath:                  {Type: "string", ReadOnly: false},
+	LBSvcHealthCheckHTTPValidateCertificate:   {Type: "bool", ReadOnly: false},
+	LBSvcHealthCheckHTTPStatusCodes:           {Type: "string", ReadOnly: false},
+	LBID:                                      {Type: "string", ReadOnly: true},
+}
@@ -17,6 +17,19 @@ import (
 // ErrNotSet signals that an annotation was not set.
 var ErrNotSet = errors.New("not set")
 
+// observeOperation wraps [metrics.ObserveOperation], but does not count
+// ErrNotSet as error, as most annotations are optional.
+func observeOperation(op string) func(err *error) {
+	done := metrics.ObserveOperation(op)
+	return func(err *error) {
+		if err != nil && errors.Is(*err, ErrNotSet) {
+			done(nil)
+			return
+		}
+		done(err)
+	}
+}
+
 // Name defines the name of a K8S annotation.
 type Name string
 
@@ -35,13 +48,13 @@ func (s Name) StringFromService(svc *corev1.Service) (string, bool) {
 // from svc.
 //
 // StringsFromService returns ErrNotSet annotation was not set.
-func (s Name) StringsFromService(svc *corev1.Service) ([]string, error) {
+func (s Name) StringsFromService(svc *corev1.Service) (_ []string, err error) {
 	const op = "annotation/Name.StringsFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var ss []string
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		ss = strings.Split(v, ",")
 		return nil
 	})
@@ -55,9 +68,9 @@ func (s Name) StringsFromService(svc *corev1.Service) ([]string, error) {
 // BoolFromService returns an error if the value could not be converted to a
 // boolean, or the annotation was not set. In the case of a missing value, the
 // error wraps ErrNotSet.
-func (s Name) BoolFromService(svc *corev1.Service) (bool, error) {
+func (s Name) BoolFromService(svc *corev1.Service) (_ bool, err error) {
 	const op = "annotation/Name.BoolFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	v, ok := s.StringFromService(svc)
 	if !ok {
@@ -75,9 +88,9 @@ func (s Name) BoolFromService(svc *corev1.Service) (bool, error) {
 // IntFromService returns an error if the value could not be converted to an
 // int, or the annotation was not set. In the case of a missing value, the
 // error wraps ErrNotSet.
-func (s Name) IntFromService(svc *corev1.Service) (int, error) {
+func (s Name) IntFromService(svc *corev1.Service) (_ int, err error) {
 	const op = "annotation/Name.IntFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	v, ok := s.StringFromService(svc)
 	if !ok {
@@ -96,13 +109,13 @@ func (s Name) IntFromService(svc *corev1.Service) (int, error) {
 // IntsFromService returns an error if the value could not be converted to a
 // []int, or the annotation was not set. In the case of a missing value, the
 // error wraps ErrNotSet.
-func (s Name) IntsFromService(svc *corev1.Service) ([]int, error) {
+func (s Name) IntsFromService(svc *corev1.Service) (_ []int, err error) {
 	const op = "annotation/Name.IntsFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var is []int
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		ss := strings.Split(v, ",")
 		is = make([]int, len(ss))
 
@@ -125,13 +138,13 @@ func (s Name) IntsFromService(svc *corev1.Service) ([]int, error) {
 // IPFromService returns an error if the value could not be converted to a
 // net.IP, or the annotation was not set. In the case of a missing value, the
 // error wraps ErrNotSet.
-func (s Name) IPFromService(svc *corev1.Service) (net.IP, error) {
+func (s Name) IPFromService(svc *corev1.Service) (_ net.IP, err error) {
 	const op = "annotation/Name.IPFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var ip net.IP
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		ip = net.ParseIP(v)
 		if ip == nil {
 			return fmt.Errorf("invalid ip address: %s", v)
@@ -148,13 +161,13 @@ func (s Name) IPFromService(svc *corev1.Service) (net.IP, error) {
 // DurationFromService returns an error if the value could not be converted to
 // a time.Duration, or the annotation was not set. In the case of a missing
 // value, the error wraps ErrNotSet.
-func (s Name) DurationFromService(svc *corev1.Service) (time.Duration, error) {
+func (s Name) DurationFromService(svc *corev1.Service) (_ time.Duration, err error) {
 	const op = "annotation/Name.DurationFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var d time.Duration
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		var err error
 
 		d, err = time.ParseDuration(v)
@@ -170,13 +183,13 @@ func (s Name) DurationFromService(svc *corev1.Service) (time.Duration, error) {
 // LBSvcProtocolFromService returns an error if the value could not be
 // converted to a hcloud.LoadBalancerServiceProtocol, or the annotation was not
 // set. In the case of a missing value, the error wraps ErrNotSet.
-func (s Name) LBSvcProtocolFromService(svc *corev1.Service) (hcloud.LoadBalancerServiceProtocol, error) {
+func (s Name) LBSvcProtocolFromService(svc *corev1.Service) (_ hcloud.LoadBalancerServiceProtocol, err error) {
 	const op = "annotation/Name.LBSvcProtocolFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var p hcloud.LoadBalancerServiceProtocol
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		var err error
 
 		p, err = validateServiceProtocol(v)
@@ -192,13 +205,13 @@ func (s Name) LBSvcProtocolFromService(svc *corev1.Service) (hcloud.LoadBalancer
 // LBAlgorithmTypeFromService returns an error if the value could not be
 // converted to a hcloud.LoadBalancerAlgorithmType, or the annotation was not
 // set. In the case of a missing value, the error wraps ErrNotSet.
-func (s Name) LBAlgorithmTypeFromService(svc *corev1.Service) (hcloud.LoadBalancerAlgorithmType, error) {
+func (s Name) LBAlgorithmTypeFromService(svc *corev1.Service) (_ hcloud.LoadBalancerAlgorithmType, err error) {
 	const op = "annotation/Name.LBAlgorithmTypeFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var alg hcloud.LoadBalancerAlgorithmType
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		var err error
 
 		alg, err = validateAlgorithmType(v)
@@ -212,13 +225,13 @@ func (s Name) LBAlgorithmTypeFromService(svc *corev1.Service) (hcloud.LoadBalanc
 // the annotation from svc.
 //
 // NetworkZoneFromService returns ErrNotSet if the annotation was not set.
-func (s Name) NetworkZoneFromService(svc *corev1.Service) (hcloud.NetworkZone, error) {
+func (s Name) NetworkZoneFromService(svc *corev1.Service) (_ hcloud.NetworkZone, err error) {
 	const op = "annotation/Name.NetworkZoneFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var nz hcloud.NetworkZone
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		nz = hcloud.NetworkZone(v)
 		return nil
 	})
@@ -232,13 +245,13 @@ func (s Name) NetworkZoneFromService(svc *corev1.Service) (hcloud.NetworkZone, e
 // CertificatesFromService returns an error if the value could not be converted
 // to a []*hcloud.Certificate, or the annotation was not set. In the case of a
 // missing value, the error wraps ErrNotSet.
-func (s Name) CertificatesFromService(svc *corev1.Service) ([]*hcloud.Certificate, error) {
+func (s Name) CertificatesFromService(svc *corev1.Service) (_ []*hcloud.Certificate, err error) {
 	const op = "annotation/Name.CertificatesFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var cs []*hcloud.Certificate
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		ss := strings.Split(v, ",")
 		cs = make([]*hcloud.Certificate, len(ss))
 
@@ -265,13 +278,13 @@ func (s Name) CertificatesFromService(svc *corev1.Service) ([]*hcloud.Certificat
 // CertificateTypeFromService returns an error if the value could not be
 // converted to a hcloud.CertificateType. In the case of a missing value, the
 // error wraps ErrNotSet.
-func (s Name) CertificateTypeFromService(svc *corev1.Service) (hcloud.CertificateType, error) {
+func (s Name) CertificateTypeFromService(svc *corev1.Service) (_ hcloud.CertificateType, err error) {
 	const op = "annotation/Name.CertificateTypeFromService"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	var ct hcloud.CertificateType
 
-	err := s.applyToValue(op, svc, func(v string) error {
+	err = s.applyToValue(op, svc, func(v string) error {
 		switch strings.ToLower(v) {
 		case string(hcloud.CertificateTypeUploaded):
 			ct = hcloud.CertificateTypeUploaded
@@ -286,6 +299,36 @@ func (s Name) CertificateTypeFromService(svc *corev1.Service) (hcloud.Certificat
 	return ct, err
 }
 
+// LabelsFromService retrieves the map[string]string value belonging to the
+// annotation from svc. The value is expected to be a comma separated list of
+// key=value pairs.
+//
+// LabelsFromService returns an error if the value could not be converted to a
+// map[string]string, or the annotation was not set. In the case of a missing
+// value, the error wraps ErrNotSet.
+func (s Name) LabelsFromService(svc *corev1.Service) (_ map[string]string, err error) {
+	const op = "annotation/Name.LabelsFromService"
+	defer observeOperation(op)(&err)
+
+	var labels map[string]string
+
+	err = s.applyToValue(op, svc, func(v string) error {
+		ss := strings.Split(v, ",")
+		labels = make(map[string]string, len(ss))
+
+		for _, s := range ss {
+			key, value, ok := strings.Cut(strings.TrimSpace(s), "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid label: %q", s)
+			}
+			labels[key] = value
+		}
+		return nil
+	})
+
+	return labels, err
+}
+
 func (s Name) applyToValue(op string, svc *corev1.Service, f func(string) error) error {
 	v, ok := s.StringFromService(svc)
 	if !ok {
@@ -297,9 +340,9 @@ func (s Name) applyToValue(op string, svc *corev1.Service, f func(string) error)
 	return nil
 }
 
-func validateAlgorithmType(algorithmType string) (hcloud.LoadBalancerAlgorithmType, error) {
+func validateAlgorithmType(algorithmType string) (_ hcloud.LoadBalancerAlgorithmType, err error) {
 	const op = "annotation/validateAlgorithmType"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	algorithmType = strings.ToLower(algorithmType) // Lowercase because all our protocols are lowercase
 	hcloudAlgorithmType := hcloud.LoadBalancerAlgorithmType(algorithmType)
@@ -314,9 +357,9 @@ func validateAlgorithmType(algorithmType string) (hcloud.LoadBalancerAlgorithmTy
 	return hcloudAlgorithmType, nil
 }
 
-func validateServiceProtocol(protocol string) (hcloud.LoadBalancerServiceProtocol, error) {
+func validateServiceProtocol(protocol string) (_ hcloud.LoadBalancerServiceProtocol, err error) {
 	const op = "annotation/validateServiceProtocol"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer observeOperation(op)(&err)
 
 	protocol = strings.ToLower(protocol) // Lowercase because all our protocols are lowercase
 	hcloudProtocol := hcloud.LoadBalancerServiceProtocol(protocol)
@@ -345,6 +345,33 @@ func TestName_CertificateTypeFromService(t *testing.T) {
 	})
 }
 
+func TestName_LabelsFromService(t *testing.T) {
+	tests := []typedAccessorTest{
+		{
+			name: "labels set",
+			svcAnnotations: map[annotation.Name]string{
+				ann: "team=a, env=prod,empty=",
+			},
+			expected: map[string]string{"team": "a", "env": "prod", "empty": ""},
+		},
+		{
+			name: "invalid label",
+			svcAnnotations: map[annotation.Name]string{
+				ann: "team=a,env",
+			},
+			err: fmt.Errorf(`annotation/Name.LabelsFromService: invalid label: "env"`),
+		},
+		{
+			name: "value not set",
+			err:  annotation.ErrNotSet,
+		},
+	}
+
+	runAllTypedAccessorTests(t, tests, func(svc *corev1.Service) (any, error) {
+		return ann.LabelsFromService(svc)
+	})
+}
+
 type typedAccessorTest struct {
 	name           string
 	svcAnnotations map[annotation.Name]string
@@ -0,0 +1,97 @@
+package annotation
+
+import (
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NamespaceDefaults contains all annotations which can be set on a Namespace
+// to provide defaults for the Load Balancer Services in it.
+var NamespaceDefaults = []Name{
+	LBLocation,
+	LBNetworkZone,
+	LBType,
+	LBUsePrivateIP,
+	LBLabels,
+	LBSvcHealthCheckProtocol,
+	LBSvcHealthCheckPort,
+	LBSvcHealthCheckInterval,
+	LBSvcHealthCheckTimeout,
+	LBSvcHealthCheckRetries,
+	LBSvcHealthCheckHTTPDomain,
+	LBSvcHealthCheckHTTPPath,
+	LBSvcHealthCheckHTTPValidateCertificate,
+	LBSvcHealthCheckHTTPStatusCodes,
+}
+
+// exclusiveDefaults contains groups of mutually exclusive
+// annotations. If a Service sets any annotation of a group, none of the
+// annotations of this group are inherited from the defaults.
+var exclusiveDefaults = [][]Name{
+	{LBLocation, LBNetworkZone},
+}
+
+// ServiceWithNamespaceDefaults returns svc with the defaults of ns applied.
+//
+// Annotations set on svc always take precedence over the ones set on ns. Only
+// the annotations listed in [NamespaceDefaults] are inherited. If ns does not
+// set any of them, svc is returned unchanged. Otherwise, a copy of svc is
+// returned and svc itself is not modified.
+func ServiceWithNamespaceDefaults(svc *corev1.Service, ns *corev1.Namespace) *corev1.Service {
+	if ns == nil {
+		return svc
+	}
+	return serviceWithDefaults(svc, ns.Annotations, NamespaceDefaults)
+}
+
+// serviceWithDefaults returns svc with all annotations from defaults applied,
+// which are contained in allowed and not already set on svc. If allowed is
+// nil, all annotations from defaults are applied.
+func serviceWithDefaults(svc *corev1.Service, defaults map[string]string, allowed []Name) *corev1.Service {
+	if len(defaults) == 0 {
+		return svc
+	}
+	if allowed == nil {
+		allowed = make([]Name, 0, len(defaults))
+		for name := range defaults {
+			allowed = append(allowed, Name(name))
+		}
+	}
+
+	skip := make(map[Name]bool)
+	for _, group := range exclusiveDefaults {
+		for _, name := range group {
+			if _, ok := name.StringFromService(svc); !ok {
+				continue
+			}
+			for _, n := range group {
+				skip[n] = true
+			}
+			break
+		}
+	}
+
+	apply := make(map[string]string)
+	for _, name := range allowed {
+		if skip[name] {
+			continue
+		}
+		if _, ok := name.StringFromService(svc); ok {
+			continue
+		}
+		if v, ok := defaults[string(name)]; ok {
+			apply[string(name)] = v
+		}
+	}
+	if len(apply) == 0 {
+		return svc
+	}
+
+	svc = svc.DeepCopy()
+	if svc.Annotations == nil {
+		svc.Annotations = make(map[string]string, len(apply))
+	}
+	maps.Copy(svc.Annotations, apply)
+	return svc
+}
@@ -0,0 +1,90 @@
+package annotation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+)
+
+func TestServiceWithNamespaceDefaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		svcAnnotations map[string]string
+		nsAnnotations  map[string]string
+		expected       map[string]string
+	}{
+		{
+			name: "namespace without annotations",
+			svcAnnotations: map[string]string{
+				string(annotation.LBType): "lb21",
+			},
+			expected: map[string]string{
+				string(annotation.LBType): "lb21",
+			},
+		},
+		{
+			name: "inherit defaults",
+			nsAnnotations: map[string]string{
+				string(annotation.LBLocation):               "fsn1",
+				string(annotation.LBType):                   "lb21",
+				string(annotation.LBSvcHealthCheckInterval): "5s",
+			},
+			expected: map[string]string{
+				string(annotation.LBLocation):               "fsn1",
+				string(annotation.LBType):                   "lb21",
+				string(annotation.LBSvcHealthCheckInterval): "5s",
+			},
+		},
+		{
+			name: "service annotations take precedence",
+			svcAnnotations: map[string]string{
+				string(annotation.LBType): "lb31",
+			},
+			nsAnnotations: map[string]string{
+				string(annotation.LBType):         "lb21",
+				string(annotation.LBUsePrivateIP): "true",
+			},
+			expected: map[string]string{
+				string(annotation.LBType):         "lb31",
+				string(annotation.LBUsePrivateIP): "true",
+			},
+		},
+		{
+			name: "ignore annotations which can not be defaulted",
+			nsAnnotations: map[string]string{
+				string(annotation.LBName):     "my-lb",
+				string(annotation.LBHostname): "example.com",
+			},
+			expected: nil,
+		},
+		{
+			name: "service network zone excludes namespace location",
+			svcAnnotations: map[string]string{
+				string(annotation.LBNetworkZone): "eu-central",
+			},
+			nsAnnotations: map[string]string{
+				string(annotation.LBLocation): "fsn1",
+			},
+			expected: map[string]string{
+				string(annotation.LBNetworkZone): "eu-central",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tt.svcAnnotations}}
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Annotations: tt.nsAnnotations}}
+			original := svc.DeepCopy()
+
+			actual := annotation.ServiceWithNamespaceDefaults(svc, ns)
+
+			assert.Equal(t, tt.expected, actual.Annotations)
+			assert.Equal(t, original, svc, "input Service must not be modified")
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package annotation
+
+import (
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ServiceWithProfileDefaults returns svc with the defaults of a Load Balancer
+// profile applied. defaults maps annotation names to their values.
+//
+// Annotations set on svc always take precedence over defaults. If nothing is
+// applied, svc is returned unchanged. Otherwise, a copy of svc is returned and
+// svc itself is not modified.
+func ServiceWithProfileDefaults(svc *corev1.Service, defaults map[string]string) *corev1.Service {
+	return serviceWithDefaults(svc, defaults, nil)
+}
+
+// ServiceWithOverrides returns a copy of svc with all annotations from
+// overrides set, replacing the values already set on svc. If overrides is
+// empty, svc is returned unchanged.
+func ServiceWithOverrides(svc *corev1.Service, overrides map[string]string) *corev1.Service {
+	if len(overrides) == 0 {
+		return svc
+	}
+
+	svc = svc.DeepCopy()
+	if svc.Annotations == nil {
+		svc.Annotations = make(map[string]string, len(overrides))
+	}
+	maps.Copy(svc.Annotations, overrides)
+	return svc
+}
@@ -0,0 +1,18 @@
+package annotation
+
+// Info describes a known annotation.
+type Info struct {
+	// Type is the documented type of the annotation value, e.g. "bool" or
+	// "tcp | http | https" for enums.
+	Type string
+	// ReadOnly is true for annotations which are reserved for the
+	// hcloud-cloud-controller-manager and must not be set by users.
+	ReadOnly bool
+}
+
+// Lookup returns the Info of a known annotation. The second return value is
+// false if name is not a known annotation.
+func Lookup(name Name) (Info, bool) {
+	info, ok := registry[name]
+	return info, ok
+}
@@ -0,0 +1,84 @@
+package annotation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Prefix is the prefix shared by all Load Balancer annotations.
+const Prefix = "load-balancer.hetzner.cloud/"
+
+// maxSuggestionDistance is the maximum edit distance between an unknown and
+// a known annotation, for the known annotation to be suggested.
+const maxSuggestionDistance = 3
+
+// UnsupportedFromService returns a sorted list of problems with the
+// annotations of svc, which start with [Prefix], but are either unknown or
+// read-only. Both are ignored during reconciliation. For unknown annotations,
+// the closest known annotation is suggested.
+func UnsupportedFromService(svc *corev1.Service) []string {
+	var problems []string
+
+	for key := range svc.Annotations {
+		if !strings.HasPrefix(key, Prefix) {
+			continue
+		}
+
+		info, ok := Lookup(Name(key))
+		switch {
+		case !ok:
+			msg := fmt.Sprintf("unknown annotation %q", key)
+			if suggestion, ok := closest(key); ok {
+				msg += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			problems = append(problems, msg)
+		case info.ReadOnly:
+			problems = append(problems, fmt.Sprintf("annotation %q is read-only", key))
+		}
+	}
+
+	slices.Sort(problems)
+	return problems
+}
+
+// closest returns the known, writable annotation with the smallest edit
+// distance to key, if it is close enough to be a likely typo.
+func closest(key string) (Name, bool) {
+	var (
+		best     Name
+		bestDist = maxSuggestionDistance + 1
+	)
+	for name, info := range registry {
+		if info.ReadOnly {
+			continue
+		}
+		d := levenshtein(key, string(name))
+		if d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best, bestDist <= maxSuggestionDistance
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
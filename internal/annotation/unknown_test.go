@@ -0,0 +1,41 @@
+package annotation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+)
+
+func TestUnsupportedFromService(t *testing.T) {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		"load-balancer.hetzner.cloud/healthcheck-port": "8080",
+		"load-balancer.hetzner.cloud/something-else":   "value",
+		string(annotation.LBPublicIPv4):                "1.2.3.4",
+		string(annotation.LBPublicIPv4RDNS):            "lb.example.com",
+		string(annotation.LBType):                      "lb11",
+		"example.com/unrelated":                        "value",
+	}}}
+
+	assert.Equal(t, []string{
+		`annotation "load-balancer.hetzner.cloud/ipv4" is read-only`,
+		`unknown annotation "load-balancer.hetzner.cloud/healthcheck-port", did you mean "load-balancer.hetzner.cloud/health-check-port"?`,
+		`unknown annotation "load-balancer.hetzner.cloud/something-else"`,
+	}, annotation.UnsupportedFromService(svc))
+}
+
+func TestLookup(t *testing.T) {
+	info, ok := annotation.Lookup(annotation.LBSvcProtocol)
+	assert.True(t, ok)
+	assert.Equal(t, annotation.Info{Type: "tcp | http | https"}, info)
+
+	info, ok = annotation.Lookup(annotation.LBID)
+	assert.True(t, ok)
+	assert.True(t, info.ReadOnly)
+
+	_, ok = annotation.Lookup("load-balancer.hetzner.cloud/unknown")
+	assert.False(t, ok)
+}
@@ -0,0 +1,172 @@
+package annotation
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+type parseFunc func(Name, *corev1.Service) error
+
+// parsers maps the annotations with a typed value to the function used to
+// parse them during reconciliation. Annotations with arbitrary string values
+// are not listed.
+var parsers = map[Name]parseFunc{
+	LBIPv6Disabled:                            parseBool,
+	LBDisablePublicNetwork:                    parseBool,
+	LBDisablePrivateIngress:                   parseBool,
+	LBUsePrivateIP:                            parseBool,
+	LBSvcProxyProtocol:                        parseBool,
+	LBSvcRedirectHTTP:                         parseBool,
+	LBSvcHTTPStickySessions:                   parseBool,
+	LBSvcHTTPManagedCertificateUseACMEStaging: parseBool,
+	LBSvcHealthCheckHTTPValidateCertificate:   parseBool,
+
+	LBSvcHTTPCookieLifetime:  parseDuration,
+	LBSvcHTTPTimeoutIdle:     parseDuration,
+	LBSvcHealthCheckInterval: parseDuration,
+	LBSvcHealthCheckTimeout:  parseDuration,
+
+	LBSvcHealthCheckPort:    parsePort,
+	LBSvcHealthCheckRetries: parseInt,
+
+	LBSvcProtocol:            parseProtocol,
+	LBSvcHealthCheckProtocol: parseProtocol,
+	LBAlgorithmType:          parseAlgorithmType,
+
+	LBSvcHTTPCertificateType: parseCertificateType,
+	LBSvcHTTPCertificates:    parseCertificates,
+
+	LBPrivateIPv4:        parseIP,
+	PrivateSubnetIPRange: parseCIDR,
+	LBLabels:             parseLabels,
+	LBNodeSelector:       parseSelector,
+}
+
+// ValidateService checks the Load Balancer annotations of svc. Every set
+// annotation is parsed with the same function used during reconciliation.
+// Additionally, combinations of annotations which can never be reconciled are
+// rejected.
+//
+// The returned errors point to the offending annotations. ValidateService
+// returns nil if svc is valid.
+func ValidateService(svc *corev1.Service) field.ErrorList {
+	const op = "annotation/ValidateService"
+	defer metrics.ObserveOperation(op)(nil)
+
+	var errs field.ErrorList
+
+	for name, parse := range parsers {
+		v, ok := name.StringFromService(svc)
+		if !ok {
+			continue
+		}
+		if err := parse(name, svc); err != nil {
+			errs = append(errs, field.Invalid(name.path(), v, err.Error()))
+		}
+	}
+
+	// An empty location resets the default location, which is required to use
+	// a network zone if HCLOUD_LOAD_BALANCERS_LOCATION is set.
+	if location, ok := LBLocation.StringFromService(svc); ok && location != "" {
+		if _, ok := LBNetworkZone.StringFromService(svc); ok {
+			errs = append(errs, field.Forbidden(LBNetworkZone.path(),
+				fmt.Sprintf("must not be set together with %s", LBLocation)))
+		}
+	}
+
+	if typ, err := LBSvcHTTPCertificateType.CertificateTypeFromService(svc); err == nil && typ == hcloud.CertificateTypeManaged {
+		if _, ok := LBSvcHTTPManagedCertificateDomains.StringFromService(svc); !ok {
+			errs = append(errs, field.Required(LBSvcHTTPManagedCertificateDomains.path(),
+				fmt.Sprintf("must be set if %s is %s", LBSvcHTTPCertificateType, hcloud.CertificateTypeManaged)))
+		}
+	}
+
+	// Map iteration order is random, sort to get stable results.
+	slices.SortStableFunc(errs, func(a, b *field.Error) int {
+		return strings.Compare(a.Field, b.Field)
+	})
+
+	return errs
+}
+
+func (s Name) path() *field.Path {
+	return field.NewPath("metadata", "annotations").Key(string(s))
+}
+
+func parseBool(s Name, svc *corev1.Service) error {
+	_, err := s.BoolFromService(svc)
+	return err
+}
+
+func parseDuration(s Name, svc *corev1.Service) error {
+	_, err := s.DurationFromService(svc)
+	return err
+}
+
+func parseInt(s Name, svc *corev1.Service) error {
+	_, err := s.IntFromService(svc)
+	return err
+}
+
+func parsePort(s Name, svc *corev1.Service) error {
+	p, err := s.IntFromService(svc)
+	if err != nil {
+		return err
+	}
+	if p < 1 || p > 65535 {
+		return errors.New("must be between 1 and 65535, inclusive")
+	}
+	return nil
+}
+
+func parseProtocol(s Name, svc *corev1.Service) error {
+	_, err := s.LBSvcProtocolFromService(svc)
+	return err
+}
+
+func parseAlgorithmType(s Name, svc *corev1.Service) error {
+	_, err := s.LBAlgorithmTypeFromService(svc)
+	return err
+}
+
+func parseCertificateType(s Name, svc *corev1.Service) error {
+	_, err := s.CertificateTypeFromService(svc)
+	return err
+}
+
+func parseCertificates(s Name, svc *corev1.Service) error {
+	_, err := s.CertificatesFromService(svc)
+	return err
+}
+
+func parseIP(s Name, svc *corev1.Service) error {
+	_, err := s.IPFromService(svc)
+	return err
+}
+
+func parseCIDR(s Name, svc *corev1.Service) error {
+	v, _ := s.StringFromService(svc)
+	_, _, err := net.ParseCIDR(v)
+	return err
+}
+
+func parseLabels(s Name, svc *corev1.Service) error {
+	_, err := s.LabelsFromService(svc)
+	return err
+}
+
+func parseSelector(s Name, svc *corev1.Service) error {
+	v, _ := s.StringFromService(svc)
+	_, err := labels.Parse(v)
+	return err
+}
@@ -0,0 +1,92 @@
+package annotation_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+)
+
+func TestValidateService(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expected    []string
+	}{
+		{
+			name: "no annotations",
+		},
+		{
+			name: "valid annotations",
+			annotations: map[string]string{
+				string(annotation.LBLocation):                         "fsn1",
+				string(annotation.LBSvcProtocol):                      "https",
+				string(annotation.LBSvcHealthCheckInterval):           "5s",
+				string(annotation.LBSvcHealthCheckPort):               "8080",
+				string(annotation.LBSvcHTTPCertificateType):           "managed",
+				string(annotation.LBSvcHTTPManagedCertificateDomains): "example.com",
+				string(annotation.LBNodeSelector):                     "role=lb,!excluded",
+				string(annotation.PrivateSubnetIPRange):               "10.0.1.0/24",
+				string(annotation.LBLabels):                           "team=a",
+			},
+		},
+		{
+			name: "invalid values",
+			annotations: map[string]string{
+				string(annotation.LBSvcProtocol):            "udp",
+				string(annotation.LBSvcHealthCheckInterval): "5",
+				string(annotation.LBSvcHealthCheckPort):     "70000",
+				string(annotation.LBUsePrivateIP):           "yes",
+				string(annotation.LBNodeSelector):           "role in (lb",
+			},
+			expected: []string{
+				`metadata.annotations[load-balancer.hetzner.cloud/health-check-interval]: Invalid value: "5": annotation/Name.DurationFromService: time: missing unit in duration "5"`,
+				`metadata.annotations[load-balancer.hetzner.cloud/health-check-port]: Invalid value: "70000": must be between 1 and 65535, inclusive`,
+				`metadata.annotations[load-balancer.hetzner.cloud/node-selector]: Invalid value: "role in (lb": unable to parse requirement: found '', expected: ',' or ')'`,
+				`metadata.annotations[load-balancer.hetzner.cloud/protocol]: Invalid value: "udp": annotation/Name.LBSvcProtocolFromService: annotation/validateServiceProtocol: invalid: udp`,
+				`metadata.annotations[load-balancer.hetzner.cloud/use-private-ip]: Invalid value: "yes": annotation/Name.BoolFromService: load-balancer.hetzner.cloud/use-private-ip: strconv.ParseBool: parsing "yes": invalid syntax`,
+			},
+		},
+		{
+			name: "location and network zone",
+			annotations: map[string]string{
+				string(annotation.LBLocation):    "fsn1",
+				string(annotation.LBNetworkZone): "eu-central",
+			},
+			expected: []string{
+				`metadata.annotations[load-balancer.hetzner.cloud/network-zone]: Forbidden: must not be set together with load-balancer.hetzner.cloud/location`,
+			},
+		},
+		{
+			name: "empty location and network zone",
+			annotations: map[string]string{
+				string(annotation.LBLocation):    "",
+				string(annotation.LBNetworkZone): "eu-central",
+			},
+		},
+		{
+			name: "managed certificate without domains",
+			annotations: map[string]string{
+				string(annotation.LBSvcHTTPCertificateType): "managed",
+			},
+			expected: []string{
+				`metadata.annotations[load-balancer.hetzner.cloud/http-managed-certificate-domains]: Required value: must be set if load-balancer.hetzner.cloud/certificate-type is managed`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+
+			var actual []string
+			for _, err := range annotation.ValidateService(svc) {
+				actual = append(actual, err.Error())
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
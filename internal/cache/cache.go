@@ -1,13 +1,17 @@
 package cache
 
 import (
+	"cmp"
 	"context"
 	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/ratelimit"
 )
 
 type Mode string
@@ -27,10 +31,14 @@ type RefreshOpts struct {
 }
 
 func newCacheRefreshOpts[T any](cache *Cache[T], opts ...RefreshOption) *RefreshOpts {
+	// The default max age can be changed at runtime.
+	cache.mu.Lock()
 	refreshOpts := &RefreshOpts{
 		maxAge: cache.defaultMaxAge,
 		mode:   cache.defaultMode,
 	}
+	cache.mu.Unlock()
+
 	for _, opt := range opts {
 		opt(refreshOpts)
 	}
@@ -68,6 +76,9 @@ type Cache[T any] struct {
 
 	byID   map[int64]*entry[T]
 	byName map[string]*entry[T]
+	// refreshAllCount is incremented with every successful refresh of all
+	// entries.
+	refreshAllCount uint64
 
 	mu sync.Mutex
 }
@@ -98,6 +109,15 @@ func newCache[T any](
 	}
 }
 
+// SetDefaultMaxAge changes the max age of cached entries, which is used if no
+// max age is passed with [WithMaxAge].
+func (c *Cache[T]) SetDefaultMaxAge(maxAge time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.defaultMaxAge = maxAge
+}
+
 func (c *Cache[T]) ByID(ctx context.Context, id int64, opts ...RefreshOption) (*T, error) {
 	return c.getFromCache(
 		ctx,
@@ -227,7 +247,11 @@ func (c *Cache[T]) refreshOne(
 ) error {
 	subsystem := GetSubsystem(ctx)
 	klog.V(4).InfoS("refreshing entry from api", "subsystem", subsystem)
-	value, err := fetch()
+	var value *T
+	var err error
+	c.unlockIfLowPriority(ctx, func() {
+		value, err = fetch()
+	})
 	if err != nil {
 		return err
 	}
@@ -283,10 +307,20 @@ func (c *Cache[T]) refreshAll(ctx context.Context) error {
 	subsystem := GetSubsystem(ctx)
 	klog.V(4).InfoS("refreshing all entries from api", "subsystem", subsystem)
 
-	values, err := c.fetchAll(ctx)
+	refreshAllCount := c.refreshAllCount
+	var values []*T
+	var err error
+	c.unlockIfLowPriority(ctx, func() {
+		values, err = c.fetchAll(ctx)
+	})
 	if err != nil {
 		return err
 	}
+	if c.refreshAllCount != refreshAllCount {
+		klog.V(4).InfoS("all entries were refreshed by another caller meanwhile", "subsystem", subsystem)
+		return nil
+	}
+	c.refreshAllCount++
 
 	c.byID = make(map[int64]*entry[T], len(values))
 	c.byName = make(map[string]*entry[T], len(values))
@@ -311,3 +345,46 @@ func (c *Cache[T]) refreshAll(ctx context.Context) error {
 
 	return nil
 }
+
+// unlockIfLowPriority calls fetch. Make sure to lock c.mu before calling
+// unlockIfLowPriority. Requests marked with [ratelimit.WithLowPriority] can be
+// delayed for a long time while the rate limit budget is low, so c.mu is
+// released meanwhile. Other callers can use the cached entries or fetch them
+// with a normal priority in the meantime.
+func (c *Cache[T]) unlockIfLowPriority(ctx context.Context, fetch func()) {
+	if ratelimit.IsLowPriority(ctx) {
+		c.mu.Unlock()
+		defer c.mu.Lock()
+	}
+	fetch()
+}
+
+// Entry is a cached value, see [Cache.Entries].
+type Entry[T any] struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	RefreshedAt time.Time `json:"refreshedAt"`
+	Age         string    `json:"age"`
+	Value       *T        `json:"value"`
+}
+
+// Entries returns all cached values including expired ones, which were not
+// evicted yet, ordered by ID. It does not refresh the cache.
+func (c *Cache[T]) Entries() []Entry[T] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	entries := make([]Entry[T], 0, len(c.byID))
+	for id, e := range c.byID {
+		entries = append(entries, Entry[T]{
+			ID:          id,
+			Name:        c.getName(e.value),
+			RefreshedAt: e.refreshedAt,
+			Age:         now.Sub(e.refreshedAt).Round(time.Second).String(),
+			Value:       e.value,
+		})
+	}
+	slices.SortFunc(entries, func(a, b Entry[T]) int { return cmp.Compare(a.ID, b.ID) })
+	return entries
+}
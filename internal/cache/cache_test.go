@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/ratelimit"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
 
@@ -373,6 +374,39 @@ func TestCache_ModeOne_WithMaxAge(t *testing.T) {
 	})
 }
 
+func TestCache_SetDefaultMaxAge(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		sc := newTestCache(ModeOne)
+
+		ctx := t.Context()
+		client := newTestClient(t)
+
+		sc.fetchOneByID = client.FetchOneByIDFunc(&hcloud.Server{ID: 1, Name: "test1"}, nil)
+
+		{
+			srv, err := sc.ByID(ctx, 1)
+			require.NoError(t, err)
+			assertServer1(t, srv)
+			assert.Equal(t, 1, client.CallCount())
+		}
+		time.Sleep(15 * time.Second)
+		sc.SetDefaultMaxAge(time.Minute)
+		{
+			srv, err := sc.ByID(ctx, 1)
+			require.NoError(t, err)
+			assertServer1(t, srv)
+			assert.Equal(t, 1, client.CallCount())
+		}
+		sc.SetDefaultMaxAge(5 * time.Second)
+		{
+			srv, err := sc.ByID(ctx, 1)
+			require.NoError(t, err)
+			assertServer1(t, srv)
+			assert.Equal(t, 2, client.CallCount())
+		}
+	})
+}
+
 func TestCache_ModeOne_WithMode(t *testing.T) {
 	synctest.Test(t, func(t *testing.T) {
 		sc := newTestCache(ModeOne)
@@ -551,3 +585,63 @@ func TestCache_Error(t *testing.T) {
 		t.Run(string(mode), func(t *testing.T) { testCase(t, mode) })
 	}
 }
+
+func TestCacheEntries(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		sc := newTestCache(ModeAll)
+		client := newTestClient(t)
+		sc.fetchAll = client.FetchAllFunc([]*hcloud.Server{{ID: 2, Name: "test2"}, {ID: 1, Name: "test1"}}, nil)
+
+		assert.Empty(t, sc.Entries())
+
+		_, err := sc.All(t.Context())
+		require.NoError(t, err)
+		refreshedAt := time.Now()
+
+		time.Sleep(5 * time.Second)
+
+		entries := sc.Entries()
+		require.Len(t, entries, 2)
+		assert.Equal(t, Entry[hcloud.Server]{ID: 1, Name: "test1", RefreshedAt: refreshedAt, Age: "5s", Value: entries[0].Value}, entries[0])
+		assert.Equal(t, int64(2), entries[1].ID)
+		// Entries does not refresh the cache.
+		assert.Equal(t, 1, client.CallCount())
+	})
+}
+
+func TestCacheLowPriorityRefreshDoesNotBlock(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		sc := newTestCache(ModeAll)
+
+		release := make(chan struct{})
+		sc.fetchAll = func(ctx context.Context) ([]*hcloud.Server, error) {
+			if ratelimit.IsLowPriority(ctx) {
+				// Throttled until the rate limit budget is refilled.
+				<-release
+				return []*hcloud.Server{{ID: 1, Name: "stale"}}, nil
+			}
+			return []*hcloud.Server{{ID: 1, Name: "test1"}}, nil
+		}
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, err := sc.All(ratelimit.WithLowPriority(t.Context()))
+			assert.NoError(t, err)
+		}()
+		synctest.Wait()
+
+		// Other callers are not blocked by the throttled refresh.
+		srv, err := sc.ByID(t.Context(), 1)
+		require.NoError(t, err)
+		assertServer1(t, srv)
+
+		close(release)
+		<-done
+
+		// The result of the throttled refresh is older, so it is discarded.
+		srv, err = sc.ByID(t.Context(), 1)
+		require.NoError(t, err)
+		assertServer1(t, srv)
+	})
+}
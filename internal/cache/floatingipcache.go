@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+var floatingIPCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "cloud_controller_manager",
+	Subsystem: "floating_ip",
+	Name:      "cache_requests_total",
+	Help:      "Total cache requests to the Floating IPs API partitioned by subsystem, mode and result.",
+}, []string{"subsystem", "mode", "result"})
+
+func init() {
+	metrics.GetRegistry().MustRegister(floatingIPCacheRequests)
+}
+
+func NewFloatingIPCache(client *hcloud.Client, defaultMode Mode, defaultMaxAge time.Duration) *Cache[hcloud.FloatingIP] {
+	return newCache[hcloud.FloatingIP](
+		func(ctx context.Context, id int64) (*hcloud.FloatingIP, error) {
+			value, _, err := client.FloatingIP.GetByID(ctx, id)
+			return value, err
+		},
+		func(ctx context.Context, name string) (*hcloud.FloatingIP, error) {
+			value, _, err := client.FloatingIP.GetByName(ctx, name)
+			return value, err
+		},
+		func(ctx context.Context) ([]*hcloud.FloatingIP, error) {
+			values, err := client.FloatingIP.All(ctx)
+			return values, err
+		},
+		func(value *hcloud.FloatingIP) int64 { return value.ID },
+		func(value *hcloud.FloatingIP) string { return value.Name },
+		floatingIPCacheRequests,
+		defaultMode,
+		defaultMaxAge,
+	)
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AddressProvider is the kind of server an [AddressPolicy] applies to.
+type AddressProvider string
+
+const (
+	AddressProviderCloud AddressProvider = "cloud"
+	AddressProviderRobot AddressProvider = "robot"
+)
+
+// AddressSource is a source of node addresses.
+type AddressSource string
+
+const (
+	// AddressSourcePublicIPv4 is the public IPv4 of the server.
+	AddressSourcePublicIPv4 AddressSource = "public-ipv4"
+	// AddressSourcePublicIPv6 is the host address in the public IPv6 network
+	// of the server.
+	AddressSourcePublicIPv6 AddressSource = "public-ipv6"
+	// AddressSourceFloatingIPv4 are the IPv4 Floating IPs assigned to a Hetzner
+	// Cloud Server.
+	AddressSourceFloatingIPv4 AddressSource = "floating-ipv4"
+	// AddressSourceFloatingIPv6 are the host addresses in the IPv6 Floating IPs
+	// assigned to a Hetzner Cloud Server.
+	AddressSourceFloatingIPv6 AddressSource = "floating-ipv6"
+	// AddressSourceNetwork is the private IP of a Hetzner Cloud Server in the
+	// configured Network.
+	AddressSourceNetwork AddressSource = "network"
+	// AddressSourceAdditionalNetworks are the private IPs of a Hetzner Cloud
+	// Server in all other Networks.
+	AddressSourceAdditionalNetworks AddressSource = "additional-networks"
+	// AddressSourceForwardedInternalIPs are the InternalIPs of a Robot server,
+	// which were set on the node, e.g. by the kubelet.
+	AddressSourceForwardedInternalIPs AddressSource = "forwarded-internal-ips"
+)
+
+// AddressSources lists all supported values of [AddressSource].
+var AddressSources = []AddressSource{
+	AddressSourcePublicIPv4,
+	AddressSourcePublicIPv6,
+	AddressSourceFloatingIPv4,
+	AddressSourceFloatingIPv6,
+	AddressSourceNetwork,
+	AddressSourceAdditionalNetworks,
+	AddressSourceForwardedInternalIPs,
+}
+
+// DefaultIPv6HostSuffix is the host part of the IPv6 address used from an IPv6
+// network, e.g. 2001:db8:1234::1 for 2001:db8:1234::/64.
+const DefaultIPv6HostSuffix = "::1"
+
+// AddressPolicy controls the addresses of the nodes it applies to. The first
+// policy matching a node is used. Nodes without a matching policy use the
+// addresses derived from the address family and the other instance settings.
+type AddressPolicy struct {
+	// Name identifies the policy in error messages.
+	Name string `json:"name"`
+	// Provider limits the policy to Hetzner Cloud Servers or Robot servers. If
+	// empty, the policy applies to both.
+	Provider AddressProvider `json:"provider"`
+	// NodeSelector limits the policy to nodes with matching labels, e.g.
+	// "role=gateway". If empty, the policy applies to all nodes.
+	NodeSelector string `json:"nodeSelector"`
+	// Sources are the sources of the addresses, in the order of the addresses
+	// of the node. The hostname is always the first address.
+	Sources []AddressSource `json:"sources"`
+	// IPv6HostSuffix is the host part used for IPv6 networks. Defaults to
+	// [DefaultIPv6HostSuffix].
+	IPv6HostSuffix string `json:"ipv6HostSuffix"`
+	// AdditionalNetworkIDs limits [AddressSourceAdditionalNetworks] to these
+	// Networks. If empty, all other Networks of the server are used.
+	AdditionalNetworkIDs []int64 `json:"additionalNetworkIDs"`
+}
+
+// Validate returns an error if the policy is invalid.
+func (p AddressPolicy) Validate() error {
+	var errs []error
+
+	if p.Provider != "" && p.Provider != AddressProviderCloud && p.Provider != AddressProviderRobot {
+		errs = append(errs, fmt.Errorf("invalid provider %q, expect one of: %s,%s", p.Provider, AddressProviderCloud, AddressProviderRobot))
+	}
+	if _, err := labels.Parse(p.NodeSelector); err != nil {
+		errs = append(errs, fmt.Errorf("invalid node selector: %w", err))
+	}
+	if len(p.Sources) == 0 {
+		errs = append(errs, errors.New("sources must not be empty"))
+	}
+	for _, source := range p.Sources {
+		if !slices.Contains(AddressSources, source) {
+			errs = append(errs, fmt.Errorf("invalid source %q", source))
+		}
+	}
+	if p.IPv6HostSuffix != "" {
+		if _, err := ParseIPv6HostSuffix(p.IPv6HostSuffix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("invalid address policy %q: %w", p.Name, err)
+	}
+	return errors.Join(errs...)
+}
+
+// ParseIPv6HostSuffix parses the host part of an IPv6 address, which must not
+// set any bits of the /64 network.
+func ParseIPv6HostSuffix(suffix string) (net.IP, error) {
+	ip := net.ParseIP(suffix)
+	if ip == nil || ip.To4() != nil {
+		return nil, fmt.Errorf("invalid IPv6 host suffix %q", suffix)
+	}
+	if !slices.Equal(ip[:8], make(net.IP, 8)) {
+		return nil, fmt.Errorf("invalid IPv6 host suffix %q, must not be longer than 64 bits", suffix)
+	}
+	return ip, nil
+}
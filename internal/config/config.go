@@ -1,36 +1,55 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
 
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/topology"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud/exp/kit/envutil"
 )
 
 const (
+	hcloudConfigFile = "HCLOUD_CONFIG_FILE"
+
 	hcloudToken    = "HCLOUD_TOKEN"
+	hcloudProjects = "HCLOUD_PROJECTS"
 	hcloudEndpoint = "HCLOUD_ENDPOINT"
 	hcloudNetwork  = "HCLOUD_NETWORK"
 	hcloudDebug    = "HCLOUD_DEBUG"
 
+	hcloudRetryBudget = "HCLOUD_RETRY_BUDGET"
+
 	robotEnabled            = "ROBOT_ENABLED"
 	robotUser               = "ROBOT_USER"
 	robotPassword           = "ROBOT_PASSWORD"
 	robotCacheTimeout       = "ROBOT_CACHE_TIMEOUT"
+	robotResetCacheTimeout  = "ROBOT_RESET_CACHE_TIMEOUT"
 	robotRateLimitWaitTime  = "ROBOT_RATE_LIMIT_WAIT_TIME"
 	robotForwardInternalIPs = "ROBOT_FORWARD_INTERNAL_IPS"
 
 	hcloudInstancesZoneLabelEnabled = "HCLOUD_INSTANCES_ZONE_LABEL_ENABLED"
 	hcloudInstancesAddressFamily    = "HCLOUD_INSTANCES_ADDRESS_FAMILY"
+	hcloudInstancesLabels           = "HCLOUD_INSTANCES_LABELS"
+	hcloudInstancesServerLabels     = "HCLOUD_INSTANCES_SERVER_LABELS"
+	hcloudInstancesStateTaints      = "HCLOUD_INSTANCES_STATE_TAINTS"
+	hcloudInstancesTopologyRegion   = "HCLOUD_INSTANCES_TOPOLOGY_REGION"
+	hcloudInstancesTopologyZone     = "HCLOUD_INSTANCES_TOPOLOGY_ZONE"
+	hcloudInstancesFloatingIPs      = "HCLOUD_INSTANCES_FLOATING_IPS"
+	hcloudInstancesNodeAgentEnabled = "HCLOUD_INSTANCES_NODE_AGENT_ENABLED"
 	hcloudServerCacheMode           = "HCLOUD_SERVER_CACHE_MODE"
 	hcloudServerCacheMaxAge         = "HCLOUD_SERVER_CACHE_MAX_AGE"
 
@@ -40,27 +59,49 @@ const (
 
 	hcloudMetricsEnabled = "HCLOUD_METRICS_ENABLED"
 	hcloudMetricsAddress = "HCLOUD_METRICS_ADDRESS"
+
+	hcloudTracingEnabled = "HCLOUD_TRACING_ENABLED"
+
+	hcloudDebugEndpointEnabled = "HCLOUD_DEBUG_ENDPOINT_ENABLED"
 )
 
 type HCloudClientConfiguration struct {
-	Token    string
-	Endpoint string
-	Debug    bool
+	Token    string `json:"-"`
+	Endpoint string `json:"endpoint"`
+	Debug    bool   `json:"debug"`
+	// RetryBudget limits the time waited between retries of a mutation, which
+	// failed because a resource was locked or in conflict.
+	RetryBudget time.Duration `json:"retryBudget"`
+	// Projects are additional projects, whose servers can be nodes of the
+	// cluster.
+	Projects []ProjectConfiguration `json:"projects"`
 }
 
 type RobotConfiguration struct {
-	Enabled           bool
-	User              string
-	Password          string // #nosec G117 -- This config is never json marshaled
-	CacheTimeout      time.Duration
-	RateLimitWaitTime time.Duration
+	Enabled           bool          `json:"enabled"`
+	User              string        `json:"-"`
+	Password          string        `json:"-"` // #nosec G117 -- This config is never json marshaled
+	CacheTimeout      time.Duration `json:"cacheTimeout"`
+	ResetCacheTimeout time.Duration `json:"resetCacheTimeout"`
+	RateLimitWaitTime time.Duration `json:"rateLimitWaitTime"`
 	// ForwardInternalIPs is enabled by default.
-	ForwardInternalIPs bool
+	ForwardInternalIPs bool `json:"forwardInternalIPs"`
 }
 
 type MetricsConfiguration struct {
-	Enabled bool
-	Address string
+	Enabled bool   `json:"enabled"`
+	Address string `json:"address"`
+}
+
+// TracingConfiguration enables the export of OpenTelemetry traces. The
+// exporter itself is configured with the standard OTEL_* environment variables.
+type TracingConfiguration struct {
+	Enabled bool `json:"enabled"`
+}
+
+// DebugConfiguration enables the /debug/hccm endpoint on the metrics server.
+type DebugConfiguration struct {
+	EndpointEnabled bool `json:"endpointEnabled"`
 }
 
 type AddressFamily string
@@ -71,76 +112,214 @@ const (
 	AddressFamilyIPv4      AddressFamily = "ipv4"
 )
 
+// InstanceLabel is the name of an optional node label, which is derived from
+// the server and set with the prefix "instance.hetzner.cloud/".
+type InstanceLabel string
+
+const (
+	InstanceLabelCPUType        InstanceLabel = "cpu-type"
+	InstanceLabelArchitecture   InstanceLabel = "architecture"
+	InstanceLabelCores          InstanceLabel = "cores"
+	InstanceLabelMemory         InstanceLabel = "memory-gb"
+	InstanceLabelDisk           InstanceLabel = "disk-gb"
+	InstanceLabelNetworkZone    InstanceLabel = "network-zone"
+	InstanceLabelPlacementGroup InstanceLabel = "placement-group"
+	InstanceLabelOSFlavor       InstanceLabel = "os-flavor"
+	InstanceLabelProduct        InstanceLabel = "product"
+	InstanceLabelDatacenter     InstanceLabel = "datacenter"
+)
+
+// InstanceLabels lists all supported values of [InstanceLabel].
+var InstanceLabels = []InstanceLabel{
+	InstanceLabelCPUType,
+	InstanceLabelArchitecture,
+	InstanceLabelCores,
+	InstanceLabelMemory,
+	InstanceLabelDisk,
+	InstanceLabelNetworkZone,
+	InstanceLabelPlacementGroup,
+	InstanceLabelOSFlavor,
+	InstanceLabelProduct,
+	InstanceLabelDatacenter,
+}
+
+// FloatingIPsMode configures whether and where the Floating IPs assigned to a
+// server are added to the ExternalIP addresses of its node.
+type FloatingIPsMode string
+
+const (
+	// FloatingIPsDisabled does not add Floating IPs.
+	FloatingIPsDisabled FloatingIPsMode = ""
+	// FloatingIPsFirst adds Floating IPs before the public IPs of the server.
+	FloatingIPsFirst FloatingIPsMode = "first"
+	// FloatingIPsLast adds Floating IPs after the public IPs of the server.
+	FloatingIPsLast FloatingIPsMode = "last"
+)
+
+// ServerState is a state of a server, which can be reflected as a node taint
+// with the key "instance.hetzner.cloud/<state>".
+type ServerState string
+
+const (
+	// ServerStateLocked is set while an action, e.g. a backup, locks a Hetzner
+	// Cloud Server.
+	ServerStateLocked ServerState = "locked"
+	// ServerStateRescue is set while the rescue system of a Hetzner Cloud Server
+	// is enabled.
+	ServerStateRescue ServerState = "rescue"
+	// ServerStateMigrating is set while a Hetzner Cloud Server is migrated to
+	// another host.
+	ServerStateMigrating ServerState = "migrating"
+	// ServerStateInProcess is set while a Robot server is not ready, e.g.
+	// during a reset or reinstallation.
+	ServerStateInProcess ServerState = "in-process"
+)
+
+// ServerStates lists all supported values of [ServerState].
+var ServerStates = []ServerState{
+	ServerStateLocked,
+	ServerStateRescue,
+	ServerStateMigrating,
+	ServerStateInProcess,
+}
+
 const ServerCacheDefaultMaxAge time.Duration = 10 * time.Second
 
 type InstanceConfiguration struct {
-	AddressFamily    AddressFamily
-	ZoneLabelEnabled bool
+	AddressFamily    AddressFamily `json:"addressFamily"`
+	ZoneLabelEnabled bool          `json:"zoneLabelEnabled"`
+	// Labels are the optional node labels set when a node is initialized.
+	Labels []InstanceLabel `json:"labels"`
+	// ServerLabels are the keys of the server labels which are synced to node
+	// labels. A key ending with "*" matches all keys with this prefix.
+	ServerLabels []string `json:"serverLabels"`
+	// StateTaints maps server states to the effect of the node taint set
+	// while the server is in this state.
+	StateTaints map[ServerState]corev1.TaintEffect `json:"stateTaints"`
+	// Topology configures the levels used for the region and zone of nodes.
+	Topology TopologyConfiguration `json:"topology"`
+	// FloatingIPs configures whether and where Floating IPs are added to the
+	// addresses of nodes.
+	FloatingIPs FloatingIPsMode `json:"floatingIPs"`
+	// AddressPolicies override the addresses of matching nodes. They can only
+	// be configured in the configuration file.
+	AddressPolicies []AddressPolicy `json:"addressPolicies"`
+	// NodeAgentEnabled resolves nodes without provider ID by the server ID
+	// published by the node agent instead of the node name.
+	NodeAgentEnabled bool `json:"nodeAgentEnabled"`
+}
+
+// TopologyConfiguration configures the levels of the Hetzner infrastructure
+// used for the region and zone labels of nodes. Empty levels use the default
+// of [topology.Mapper].
+type TopologyConfiguration struct {
+	Region topology.Level `json:"region"`
+	Zone   topology.Level `json:"zone"`
 }
 
 type ServerCacheConfiguration struct {
-	Mode   cache.Mode
-	MaxAge time.Duration
+	Mode   cache.Mode    `json:"mode"`
+	MaxAge time.Duration `json:"maxAge"`
 }
 
 type LoadBalancerConfiguration struct {
-	AlgorithmType         hcloud.LoadBalancerAlgorithmType
-	DisablePublicNetwork  *bool
-	Enabled               bool
-	HealthCheckInterval   time.Duration
-	HealthCheckRetries    int
-	HealthCheckTimeout    time.Duration
-	IPv6Enabled           bool
-	Location              string
-	NetworkZone           string
-	PrivateIngressEnabled bool
-	PrivateIPEnabled      bool
-	PrivateSubnetIPRange  string
-	ProxyProtocolEnabled  *bool
-	Type                  string
+	APICallTimeout              time.Duration                    `json:"apiCallTimeout"`
+	AlgorithmType               hcloud.LoadBalancerAlgorithmType `json:"algorithmType"`
+	Classes                     map[string]map[string]string     `json:"classes"`
+	DisablePublicNetwork        *bool                            `json:"disablePublicNetwork"`
+	Enabled                     bool                             `json:"enabled"`
+	ForeignNodeIPTargetsEnabled bool                             `json:"foreignNodeIPTargetsEnabled"`
+	HealthCheckInterval         time.Duration                    `json:"healthCheckInterval"`
+	HealthCheckRetries          int                              `json:"healthCheckRetries"`
+	HealthCheckTimeout          time.Duration                    `json:"healthCheckTimeout"`
+	IPv6Enabled                 bool                             `json:"ipv6Enabled"`
+	Location                    string                           `json:"location"`
+	NamespaceDefaultsEnabled    bool                             `json:"namespaceDefaultsEnabled"`
+	NetworkZone                 string                           `json:"networkZone"`
+	PrivateIngressEnabled       bool                             `json:"privateIngressEnabled"`
+	PrivateIPEnabled            bool                             `json:"privateIPEnabled"`
+	PrivateSubnetIPRange        string                           `json:"privateSubnetIPRange"`
+	ProfilesEnabled             bool                             `json:"profilesEnabled"`
+	ProxyProtocolEnabled        *bool                            `json:"proxyProtocolEnabled"`
+	ReconcileTimeout            time.Duration                    `json:"reconcileTimeout"`
+	Type                        string                           `json:"type"`
 }
 
 type NetworkConfiguration struct {
-	NameOrID             string
-	AttachedCheckEnabled bool
+	NameOrID             string `json:"nameOrID"`
+	AttachedCheckEnabled bool   `json:"attachedCheckEnabled"`
 }
 
 type RouteConfiguration struct {
-	Enabled bool
+	Enabled bool `json:"enabled"`
 }
 
+// HCCMConfiguration is the configuration of the hcloud-cloud-controller-manager.
+// It can be read from a YAML or JSON file, see [Read]. Secrets can only be
+// provided with environment variables.
 type HCCMConfiguration struct {
-	HCloudClient HCloudClientConfiguration
-	Robot        RobotConfiguration
-	Metrics      MetricsConfiguration
-	Instance     InstanceConfiguration
-	LoadBalancer LoadBalancerConfiguration
-	Network      NetworkConfiguration
-	Route        RouteConfiguration
-	ServerCache  ServerCacheConfiguration
+	HCloudClient HCloudClientConfiguration `json:"hcloudClient"`
+	Robot        RobotConfiguration        `json:"robot"`
+	Metrics      MetricsConfiguration      `json:"metrics"`
+	Tracing      TracingConfiguration      `json:"tracing"`
+	Debug        DebugConfiguration        `json:"debug"`
+	Instance     InstanceConfiguration     `json:"instance"`
+	LoadBalancer LoadBalancerConfiguration `json:"loadBalancer"`
+	Network      NetworkConfiguration      `json:"network"`
+	Route        RouteConfiguration        `json:"route"`
+	ServerCache  ServerCacheConfiguration  `json:"serverCache"`
+
+	// File is the path of the configuration file, if one was used.
+	File string `json:"-"`
 }
 
-// Read evaluates all environment variables and returns a [HCCMConfiguration]. It only validates as far as
-// it needs to parse the values. For business logic validation, check out [HCCMConfiguration.Validate].
+// Read evaluates the configuration file and all environment variables and returns a [HCCMConfiguration].
+// Environment variables take precedence over the configuration file. It only validates as far as it needs
+// to parse the values. For business logic validation, check out [HCCMConfiguration.Validate].
 func Read() (HCCMConfiguration, error) {
 	var err error
 	// Collect all errors and return them as one.
 	// This helps users because they will see all the errors at once
 	// instead of having to fix them one by one.
 	var errs []error
-	var cfg HCCMConfiguration
+	cfg := defaultConfiguration()
+
+	if file, ok := os.LookupEnv(hcloudConfigFile); ok && file != "" {
+		if err := readFile(file, &cfg); err != nil {
+			return HCCMConfiguration{}, err
+		}
+		cfg.File = file
+	}
 
 	cfg.HCloudClient.Token, err = envutil.LookupEnvWithFile(hcloudToken)
 	if err != nil {
 		errs = append(errs, err)
 	}
-	cfg.HCloudClient.Endpoint = os.Getenv(hcloudEndpoint)
-	cfg.HCloudClient.Debug, err = getEnvBool(hcloudDebug, false)
+	if endpoint, ok := os.LookupEnv(hcloudEndpoint); ok {
+		cfg.HCloudClient.Endpoint = endpoint
+	}
+	cfg.HCloudClient.Debug, err = getEnvBool(hcloudDebug, cfg.HCloudClient.Debug)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.HCloudClient.RetryBudget, err = getEnvDuration(hcloudRetryBudget, cfg.HCloudClient.RetryBudget)
 	if err != nil {
 		errs = append(errs, err)
 	}
+	if names, ok := getEnvList(hcloudProjects); ok {
+		cfg.HCloudClient.Projects = make([]ProjectConfiguration, 0, len(names))
+		for _, name := range names {
+			cfg.HCloudClient.Projects = append(cfg.HCloudClient.Projects, ProjectConfiguration{Name: name})
+		}
+	}
+	for i, project := range cfg.HCloudClient.Projects {
+		cfg.HCloudClient.Projects[i].Token, err = envutil.LookupEnvWithFile(ProjectTokenEnv(project.Name))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
 
-	cfg.Robot.Enabled, err = getEnvBool(robotEnabled, false)
+	cfg.Robot.Enabled, err = getEnvBool(robotEnabled, cfg.Robot.Enabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -152,53 +331,108 @@ func Read() (HCCMConfiguration, error) {
 	if err != nil {
 		errs = append(errs, err)
 	}
-	cfg.Robot.CacheTimeout, err = getEnvDuration(robotCacheTimeout)
+	cfg.Robot.CacheTimeout, err = getEnvDuration(robotCacheTimeout, cfg.Robot.CacheTimeout)
 	if err != nil {
 		errs = append(errs, err)
 	}
 	if cfg.Robot.CacheTimeout == 0 {
 		cfg.Robot.CacheTimeout = 5 * time.Minute
 	}
-	cfg.Robot.RateLimitWaitTime, err = getEnvDuration(robotRateLimitWaitTime)
+	cfg.Robot.ResetCacheTimeout, err = getEnvDuration(robotResetCacheTimeout, cfg.Robot.ResetCacheTimeout)
 	if err != nil {
 		errs = append(errs, err)
 	}
-	cfg.Robot.ForwardInternalIPs, err = getEnvBool(robotForwardInternalIPs, true)
+	if cfg.Robot.ResetCacheTimeout == 0 {
+		cfg.Robot.ResetCacheTimeout = 30 * time.Second
+	}
+	cfg.Robot.RateLimitWaitTime, err = getEnvDuration(robotRateLimitWaitTime, cfg.Robot.RateLimitWaitTime)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.Robot.ForwardInternalIPs, err = getEnvBool(robotForwardInternalIPs, cfg.Robot.ForwardInternalIPs)
 	if err != nil {
 		errs = append(errs, err)
 	}
 	// Robot needs to be enabled
 	cfg.Robot.ForwardInternalIPs = cfg.Robot.ForwardInternalIPs && cfg.Robot.Enabled
 
-	cfg.Metrics.Enabled, err = getEnvBool(hcloudMetricsEnabled, true)
+	cfg.Metrics.Enabled, err = getEnvBool(hcloudMetricsEnabled, cfg.Metrics.Enabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
 	if addr, ok := os.LookupEnv(hcloudMetricsAddress); ok {
 		cfg.Metrics.Address = addr
-	} else {
-		cfg.Metrics.Address = ":8233"
+	}
+
+	cfg.Tracing.Enabled, err = getEnvBool(hcloudTracingEnabled, cfg.Tracing.Enabled)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	cfg.Debug.EndpointEnabled, err = getEnvBool(hcloudDebugEndpointEnabled, cfg.Debug.EndpointEnabled)
+	if err != nil {
+		errs = append(errs, err)
 	}
 
 	// Validation happens in [HCCMConfiguration.Validate]
-	cfg.Instance.AddressFamily = AddressFamily(os.Getenv(hcloudInstancesAddressFamily))
+	if addressFamily, ok := os.LookupEnv(hcloudInstancesAddressFamily); ok {
+		cfg.Instance.AddressFamily = AddressFamily(addressFamily)
+	}
 	if cfg.Instance.AddressFamily == "" {
 		cfg.Instance.AddressFamily = AddressFamilyIPv4
 	}
 
-	cfg.Instance.ZoneLabelEnabled, err = getEnvBool(hcloudInstancesZoneLabelEnabled, true)
+	cfg.Instance.ZoneLabelEnabled, err = getEnvBool(hcloudInstancesZoneLabelEnabled, cfg.Instance.ZoneLabelEnabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
-	// ---- Server Cache ----
+	// Validation happens in [HCCMConfiguration.Validate]
+	if labels, ok := getEnvList(hcloudInstancesLabels); ok {
+		cfg.Instance.Labels = nil
+		for _, label := range labels {
+			cfg.Instance.Labels = append(cfg.Instance.Labels, InstanceLabel(label))
+		}
+	}
 
-	cfg.ServerCache = ServerCacheConfiguration{
-		Mode:   cache.ModeAll,
-		MaxAge: ServerCacheDefaultMaxAge,
+	if serverLabels, ok := getEnvList(hcloudInstancesServerLabels); ok {
+		cfg.Instance.ServerLabels = serverLabels
 	}
 
+	// Validation happens in [HCCMConfiguration.Validate]
+	if stateTaints, ok := getEnvList(hcloudInstancesStateTaints); ok {
+		cfg.Instance.StateTaints = make(map[ServerState]corev1.TaintEffect, len(stateTaints))
+		for _, stateTaint := range stateTaints {
+			state, effect, ok := strings.Cut(stateTaint, "=")
+			if !ok {
+				errs = append(errs, fmt.Errorf("failed to parse %s: expected <state>=<effect>, got %q", hcloudInstancesStateTaints, stateTaint))
+				continue
+			}
+			cfg.Instance.StateTaints[ServerState(strings.TrimSpace(state))] = corev1.TaintEffect(strings.TrimSpace(effect))
+		}
+	}
+
+	// Validation happens in [HCCMConfiguration.Validate]
+	if region, ok := os.LookupEnv(hcloudInstancesTopologyRegion); ok {
+		cfg.Instance.Topology.Region = topology.Level(region)
+	}
+	if zone, ok := os.LookupEnv(hcloudInstancesTopologyZone); ok {
+		cfg.Instance.Topology.Zone = topology.Level(zone)
+	}
+
+	// Validation happens in [HCCMConfiguration.Validate]
+	if floatingIPs, ok := os.LookupEnv(hcloudInstancesFloatingIPs); ok {
+		cfg.Instance.FloatingIPs = FloatingIPsMode(floatingIPs)
+	}
+
+	cfg.Instance.NodeAgentEnabled, err = getEnvBool(hcloudInstancesNodeAgentEnabled, cfg.Instance.NodeAgentEnabled)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// ---- Server Cache ----
+
 	if mode, ok := os.LookupEnv(hcloudServerCacheMode); ok {
 		klog.Warningf("Experimental: %s is experimental, breaking changes may occur within minor releases.", hcloudServerCacheMode)
 		cfg.ServerCache.Mode = cache.Mode(mode)
@@ -214,30 +448,35 @@ func Read() (HCCMConfiguration, error) {
 		}
 	}
 
-	cfg.LoadBalancer.Enabled, err = getEnvBool(hcloudLoadBalancersEnabled, true)
+	cfg.LoadBalancer.Enabled, err = getEnvBool(hcloudLoadBalancersEnabled, cfg.LoadBalancer.Enabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
-	cfg.LoadBalancer.Location = os.Getenv(hcloudLoadBalancersLocation)
-	cfg.LoadBalancer.NetworkZone = os.Getenv(hcloudLoadBalancersNetworkZone)
+	if location, ok := os.LookupEnv(hcloudLoadBalancersLocation); ok {
+		cfg.LoadBalancer.Location = location
+	}
+	if networkZone, ok := os.LookupEnv(hcloudLoadBalancersNetworkZone); ok {
+		cfg.LoadBalancer.NetworkZone = networkZone
+	}
 
-	disablePrivateIngress, err := getEnvBool(hcloudLoadBalancersDisablePrivateIngress, false)
+	disablePrivateIngress, err := getEnvBool(hcloudLoadBalancersDisablePrivateIngress, !cfg.LoadBalancer.PrivateIngressEnabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
 	cfg.LoadBalancer.PrivateIngressEnabled = !disablePrivateIngress // Invert the logic, as the env var is prefixed with DISABLE_.
 
-	cfg.LoadBalancer.PrivateIPEnabled, err = getEnvBool(hcloudLoadBalancersUsePrivateIP, false)
+	cfg.LoadBalancer.PrivateIPEnabled, err = getEnvBool(hcloudLoadBalancersUsePrivateIP, cfg.LoadBalancer.PrivateIPEnabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
-	cfg.LoadBalancer.ProxyProtocolEnabled, err = getEnvBoolPtr(hcloudLoadBalancersUsesProxyProtocol)
-	if err != nil {
+	if proxyProtocolEnabled, err := getEnvBoolPtr(hcloudLoadBalancersUsesProxyProtocol); err != nil {
 		errs = append(errs, err)
+	} else if proxyProtocolEnabled != nil {
+		cfg.LoadBalancer.ProxyProtocolEnabled = proxyProtocolEnabled
 	}
 
-	disableIPv6, err := getEnvBool(hcloudLoadBalancersDisableIPv6, false)
+	disableIPv6, err := getEnvBool(hcloudLoadBalancersDisableIPv6, !cfg.LoadBalancer.IPv6Enabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -251,12 +490,22 @@ func Read() (HCCMConfiguration, error) {
 		cfg.LoadBalancer.AlgorithmType = hcloud.LoadBalancerAlgorithmType(algorithmType)
 	}
 
-	cfg.LoadBalancer.HealthCheckInterval, err = getEnvDuration(hcloudLoadBalancersHealthCheckInterval)
+	cfg.LoadBalancer.HealthCheckInterval, err = getEnvDuration(hcloudLoadBalancersHealthCheckInterval, cfg.LoadBalancer.HealthCheckInterval)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
-	cfg.LoadBalancer.HealthCheckTimeout, err = getEnvDuration(hcloudLoadBalancersHealthCheckTimeout)
+	cfg.LoadBalancer.HealthCheckTimeout, err = getEnvDuration(hcloudLoadBalancersHealthCheckTimeout, cfg.LoadBalancer.HealthCheckTimeout)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	cfg.LoadBalancer.ReconcileTimeout, err = getEnvDuration(hcloudLoadBalancersReconcileTimeout, cfg.LoadBalancer.ReconcileTimeout)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	cfg.LoadBalancer.APICallTimeout, err = getEnvDuration(hcloudLoadBalancersAPICallTimeout, cfg.LoadBalancer.APICallTimeout)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -268,15 +517,45 @@ func Read() (HCCMConfiguration, error) {
 		}
 	}
 
-	cfg.LoadBalancer.DisablePublicNetwork, err = getEnvBoolPtr(hcloudLoadBalancersDisablePublicNetwork)
+	if disablePublicNetwork, err := getEnvBoolPtr(hcloudLoadBalancersDisablePublicNetwork); err != nil {
+		errs = append(errs, err)
+	} else if disablePublicNetwork != nil {
+		cfg.LoadBalancer.DisablePublicNetwork = disablePublicNetwork
+	}
+
+	if lbType, ok := os.LookupEnv(HcloudLoadBalancersType); ok {
+		cfg.LoadBalancer.Type = lbType
+	}
+
+	cfg.LoadBalancer.NamespaceDefaultsEnabled, err = getEnvBool(hcloudLoadBalancersNamespaceDefaultsEnabled, cfg.LoadBalancer.NamespaceDefaultsEnabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
 
-	cfg.LoadBalancer.Type = os.Getenv(HcloudLoadBalancersType)
+	cfg.LoadBalancer.ProfilesEnabled, err = getEnvBool(hcloudLoadBalancersProfilesEnabled, cfg.LoadBalancer.ProfilesEnabled)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.LoadBalancer.ForeignNodeIPTargetsEnabled, err = getEnvBool(hcloudLoadBalancersForeignNodeIPTargetsEnabled, cfg.LoadBalancer.ForeignNodeIPTargetsEnabled)
+	if err != nil {
+		errs = append(errs, err)
+	}
 
-	cfg.Network.NameOrID = os.Getenv(hcloudNetwork)
-	disableAttachedCheck, err := getEnvBool(hcloudNetworkDisableAttachedCheck, false)
+	classes, err := envutil.LookupEnvWithFile(hcloudLoadBalancersClasses)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if classes != "" {
+		cfg.LoadBalancer.Classes = nil // The environment variable replaces the classes of the configuration file.
+		if err := json.Unmarshal([]byte(classes), &cfg.LoadBalancer.Classes); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse %s: %w", hcloudLoadBalancersClasses, err))
+		}
+	}
+
+	if network, ok := os.LookupEnv(hcloudNetwork); ok {
+		cfg.Network.NameOrID = network
+	}
+	disableAttachedCheck, err := getEnvBool(hcloudNetworkDisableAttachedCheck, !cfg.Network.AttachedCheckEnabled)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -284,10 +563,12 @@ func Read() (HCCMConfiguration, error) {
 
 	// Enabling Routes only makes sense when a Network is configured, otherwise there is no network to add the routes to.
 	if cfg.Network.NameOrID != "" {
-		cfg.Route.Enabled, err = getEnvBool(hcloudNetworkRoutesEnabled, true)
+		cfg.Route.Enabled, err = getEnvBool(hcloudNetworkRoutesEnabled, cfg.Route.Enabled)
 		if err != nil {
 			errs = append(errs, err)
 		}
+	} else {
+		cfg.Route.Enabled = false
 	}
 
 	if len(errs) > 0 {
@@ -296,6 +577,45 @@ func Read() (HCCMConfiguration, error) {
 	return cfg, nil
 }
 
+// defaultConfiguration returns the configuration used, if neither the configuration file nor an
+// environment variable configures a value.
+func defaultConfiguration() HCCMConfiguration {
+	return HCCMConfiguration{
+		HCloudClient: HCloudClientConfiguration{
+			RetryBudget: 15 * time.Second,
+		},
+		Robot: RobotConfiguration{
+			CacheTimeout:       5 * time.Minute,
+			ForwardInternalIPs: true,
+		},
+		Metrics: MetricsConfiguration{
+			Enabled: true,
+			Address: ":8233",
+		},
+		Instance: InstanceConfiguration{
+			AddressFamily:    AddressFamilyIPv4,
+			ZoneLabelEnabled: true,
+		},
+		ServerCache: ServerCacheConfiguration{
+			Mode:   cache.ModeAll,
+			MaxAge: ServerCacheDefaultMaxAge,
+		},
+		LoadBalancer: LoadBalancerConfiguration{
+			Enabled:               true,
+			PrivateIngressEnabled: true,
+			IPv6Enabled:           true,
+			ReconcileTimeout:      10 * time.Minute,
+			APICallTimeout:        5 * time.Second,
+		},
+		Network: NetworkConfiguration{
+			AttachedCheckEnabled: true,
+		},
+		Route: RouteConfiguration{
+			Enabled: true,
+		},
+	}
+}
+
 func (c HCCMConfiguration) Validate() (err error) {
 	// Collect all errors and return them as one.
 	// This helps users because they will see all the errors at once
@@ -308,14 +628,81 @@ func (c HCCMConfiguration) Validate() (err error) {
 		klog.Warningf("unrecognized token format, expected 64 characters, got %d, proceeding anyway", len(c.HCloudClient.Token))
 	}
 
+	if c.HCloudClient.RetryBudget < 0 {
+		errs = append(errs, fmt.Errorf("invalid value for %q, must not be negative", hcloudRetryBudget))
+	}
+
+	errs = append(errs, validateProjects(c.HCloudClient.Projects)...)
+	if len(c.HCloudClient.Projects) > 0 && c.Route.Enabled {
+		// Routes can only point to servers attached to the Network, which are
+		// all in the default project.
+		errs = append(errs, fmt.Errorf("using Routes with %q is not supported", hcloudProjects))
+	}
+
 	if c.Instance.AddressFamily != AddressFamilyDualStack && c.Instance.AddressFamily != AddressFamilyIPv4 && c.Instance.AddressFamily != AddressFamilyIPv6 {
 		errs = append(errs, fmt.Errorf("invalid value for %q, expect one of: %s,%s,%s", hcloudInstancesAddressFamily, AddressFamilyIPv4, AddressFamilyIPv6, AddressFamilyDualStack))
 	}
 
+	if c.Instance.FloatingIPs != FloatingIPsDisabled && c.Instance.FloatingIPs != FloatingIPsFirst && c.Instance.FloatingIPs != FloatingIPsLast {
+		errs = append(errs, fmt.Errorf("invalid value for %q, expect one of: %s,%s", hcloudInstancesFloatingIPs, FloatingIPsFirst, FloatingIPsLast))
+	}
+
+	for _, policy := range c.Instance.AddressPolicies {
+		if err := policy.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, label := range c.Instance.Labels {
+		if !slices.Contains(InstanceLabels, label) {
+			errs = append(errs, fmt.Errorf("invalid value %q for %q, expect any of: %s", label, hcloudInstancesLabels, joinInstanceLabels(InstanceLabels)))
+		}
+	}
+
+	for _, key := range c.Instance.ServerLabels {
+		if strings.Contains(strings.TrimSuffix(key, "*"), "*") {
+			errs = append(errs, fmt.Errorf("invalid value %q for %q, \"*\" is only allowed at the end", key, hcloudInstancesServerLabels))
+		}
+	}
+
+	for _, state := range slices.Sorted(maps.Keys(c.Instance.StateTaints)) {
+		effect := c.Instance.StateTaints[state]
+		if !slices.Contains(ServerStates, state) {
+			errs = append(errs, fmt.Errorf("invalid state %q for %q, expect any of: %s", state, hcloudInstancesStateTaints, joinServerStates(ServerStates)))
+		}
+		if effect != corev1.TaintEffectNoSchedule && effect != corev1.TaintEffectPreferNoSchedule && effect != corev1.TaintEffectNoExecute {
+			errs = append(errs, fmt.Errorf("invalid effect %q for %q, expect one of: %s,%s,%s", effect, hcloudInstancesStateTaints, corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute))
+		}
+	}
+
+	regionValid := c.Instance.Topology.Region == "" || slices.Contains(topology.Levels, c.Instance.Topology.Region)
+	if !regionValid {
+		errs = append(errs, fmt.Errorf("invalid value for %q, expect one of: %s,%s,%s", hcloudInstancesTopologyRegion, topology.LevelNetworkZone, topology.LevelLocation, topology.LevelDatacenter))
+	}
+	zoneValid := c.Instance.Topology.Zone == "" || slices.Contains(topology.Levels, c.Instance.Topology.Zone)
+	if !zoneValid {
+		errs = append(errs, fmt.Errorf("invalid value for %q, expect one of: %s,%s,%s", hcloudInstancesTopologyZone, topology.LevelNetworkZone, topology.LevelLocation, topology.LevelDatacenter))
+	}
+	if regionValid && zoneValid && !topology.IsHierarchy(c.Instance.Topology.Region, c.Instance.Topology.Zone) {
+		errs = append(errs, fmt.Errorf("invalid value for %q/%q, the zone must not be coarser than the region", hcloudInstancesTopologyRegion, hcloudInstancesTopologyZone))
+	}
+
 	if c.ServerCache.Mode != cache.ModeAll && c.ServerCache.Mode != cache.ModeOne && c.ServerCache.Mode != cache.ModeOff {
 		errs = append(errs, fmt.Errorf("invalid value for %q, expect one of: %s,%s,%s", hcloudServerCacheMode, cache.ModeAll, cache.ModeOne, cache.ModeOff))
 	}
 
+	if c.Debug.EndpointEnabled && !c.Metrics.Enabled {
+		errs = append(errs, fmt.Errorf("%q requires %q, as the endpoint is served by the metrics server", hcloudDebugEndpointEnabled, hcloudMetricsEnabled))
+	}
+
+	if c.LoadBalancer.ReconcileTimeout < 0 {
+		errs = append(errs, fmt.Errorf("invalid value for %q, must not be negative", hcloudLoadBalancersReconcileTimeout))
+	}
+
+	if c.LoadBalancer.APICallTimeout < 0 {
+		errs = append(errs, fmt.Errorf("invalid value for %q, must not be negative", hcloudLoadBalancersAPICallTimeout))
+	}
+
 	if c.LoadBalancer.Location != "" && c.LoadBalancer.NetworkZone != "" {
 		errs = append(errs, fmt.Errorf("invalid value for %q/%q, only one of them can be set", hcloudLoadBalancersLocation, hcloudLoadBalancersNetworkZone))
 	}
@@ -332,6 +719,17 @@ func (c HCCMConfiguration) Validate() (err error) {
 		}
 	}
 
+	for class, defaults := range c.LoadBalancer.Classes {
+		if class == "" {
+			errs = append(errs, fmt.Errorf("invalid value for %q: class name must not be empty", hcloudLoadBalancersClasses))
+		}
+		for name := range defaults {
+			if !slices.Contains(annotation.ClassDefaults, annotation.Name(name)) {
+				errs = append(errs, fmt.Errorf("invalid value for %q: annotation %q is not supported for class %q", hcloudLoadBalancersClasses, name, class))
+			}
+		}
+	}
+
 	if c.Robot.Enabled {
 		// Robot credentials are optional. When only using the service
 		// controller with IP-based LB targets, the node's InternalIP from
@@ -387,12 +785,28 @@ func getEnvBoolPtr(key string) (*bool, error) {
 	return &b, nil
 }
 
+// getEnvList returns the non-empty elements of a comma-separated list.
+func getEnvList(key string) ([]string, bool) {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil, false
+	}
+
+	var list []string
+	for _, element := range strings.Split(v, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			list = append(list, element)
+		}
+	}
+	return list, true
+}
+
 // getEnvDuration returns the duration parsed from the environment variable with the given key and a potential error
-// parsing the var. Returns false if the env var is unset.
-func getEnvDuration(key string) (time.Duration, error) {
+// parsing the var. Returns the default value if the env var is unset.
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
 	v := os.Getenv(key)
 	if v == "" {
-		return 0, nil
+		return defaultValue, nil
 	}
 
 	b, err := time.ParseDuration(v)
@@ -411,3 +825,19 @@ func parseLoadBalancerAlgorithmType(value string) (hcloud.LoadBalancerAlgorithmT
 	}
 	return "", fmt.Errorf("unsupported value %q", value)
 }
+
+func joinInstanceLabels(labels []InstanceLabel) string {
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, string(label))
+	}
+	return strings.Join(names, ",")
+}
+
+func joinServerStates(states []ServerState) string {
+	names := make([]string, 0, len(states))
+	for _, state := range states {
+		names = append(names, string(state))
+	}
+	return strings.Join(names, ",")
+}
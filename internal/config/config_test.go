@@ -2,13 +2,18 @@ package config
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/testsupport"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/topology"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
 
@@ -24,14 +29,17 @@ func TestRead(t *testing.T) {
 			name: "minimal",
 			env:  map[string]string{},
 			want: HCCMConfiguration{
-				Robot:       RobotConfiguration{CacheTimeout: 5 * time.Minute},
-				Metrics:     MetricsConfiguration{Enabled: true, Address: ":8233"},
-				Instance:    InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
-				ServerCache: ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
+				Metrics:      MetricsConfiguration{Enabled: true, Address: ":8233"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
+				ServerCache:  ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
 				Network: NetworkConfiguration{
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -47,8 +55,8 @@ func TestRead(t *testing.T) {
 				"HCLOUD_NETWORK": "foobar",
 			},
 			want: HCCMConfiguration{
-				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
-				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute},
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq", RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
 				Metrics:      MetricsConfiguration{Enabled: true, Address: ":8233"},
 				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
 				ServerCache:  ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
@@ -57,6 +65,8 @@ func TestRead(t *testing.T) {
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -78,12 +88,13 @@ func TestRead(t *testing.T) {
 				"hetzner-password": `secret-password`,
 			},
 			want: HCCMConfiguration{
-				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq", RetryBudget: 15 * time.Second},
 				Robot: RobotConfiguration{
 					Enabled:            false,
 					User:               "foobar",
 					Password:           "secret-password",
 					CacheTimeout:       5 * time.Minute,
+					ResetCacheTimeout:  30 * time.Second,
 					RateLimitWaitTime:  0,
 					ForwardInternalIPs: false,
 				},
@@ -94,6 +105,8 @@ func TestRead(t *testing.T) {
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -125,9 +138,13 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 		{
 			name: "client",
 			env: map[string]string{
-				"HCLOUD_TOKEN":    "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq",
-				"HCLOUD_ENDPOINT": "https://api.example.com",
-				"HCLOUD_DEBUG":    "true",
+				"HCLOUD_TOKEN":                                  "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq",
+				"HCLOUD_ENDPOINT":                               "https://api.example.com",
+				"HCLOUD_DEBUG":                                  "true",
+				"HCLOUD_RETRY_BUDGET":                           "30s",
+				"HCLOUD_PROJECTS":                               "shared, team-a",
+				"HCLOUD_PROJECT_SHARED_TOKEN":                   "shared-token",
+				"HCLOUD_PROJECT_TEAM_A_TOKEN":                   "team-a-token",
 				"HCLOUD_LOAD_BALANCERS_PRIVATE_SUBNET_IP_RANGE": "10.1.0.0/24",
 				"HCLOUD_LOAD_BALANCERS_USES_PROXYPROTOCOL":      "true",
 				"HCLOUD_LOAD_BALANCERS_ALGORITHM_TYPE":          "least_connections",
@@ -136,14 +153,21 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 				"HCLOUD_LOAD_BALANCERS_HEALTH_CHECK_RETRIES":    "5",
 				"HCLOUD_LOAD_BALANCERS_DISABLE_PUBLIC_NETWORK":  "true",
 				"HCLOUD_LOAD_BALANCERS_TYPE":                    "lb21",
+				"HCLOUD_LOAD_BALANCERS_RECONCILE_TIMEOUT":       "1m",
+				"HCLOUD_LOAD_BALANCERS_API_CALL_TIMEOUT":        "10s",
 			},
 			want: HCCMConfiguration{
 				HCloudClient: HCloudClientConfiguration{
-					Token:    "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq",
-					Endpoint: "https://api.example.com",
-					Debug:    true,
-				},
-				Robot:       RobotConfiguration{CacheTimeout: 5 * time.Minute},
+					Token:       "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq",
+					Endpoint:    "https://api.example.com",
+					Debug:       true,
+					RetryBudget: 30 * time.Second,
+					Projects: []ProjectConfiguration{
+						{Name: "shared", Token: "shared-token"},
+						{Name: "team-a", Token: "team-a-token"},
+					},
+				},
+				Robot:       RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
 				Metrics:     MetricsConfiguration{Enabled: true, Address: ":8233"},
 				Instance:    InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
 				ServerCache: ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
@@ -151,6 +175,8 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      time.Minute,
+					APICallTimeout:        10 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -173,14 +199,42 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 				"HCLOUD_METRICS_ADDRESS": "127.0.0.1:9999",
 			},
 			want: HCCMConfiguration{
-				Robot:       RobotConfiguration{CacheTimeout: 5 * time.Minute},
-				Metrics:     MetricsConfiguration{Enabled: false, Address: "127.0.0.1:9999"},
-				Instance:    InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
-				ServerCache: ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
+				Metrics:      MetricsConfiguration{Enabled: false, Address: "127.0.0.1:9999"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
+				ServerCache:  ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
 				Network: NetworkConfiguration{
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
+					Enabled:               true,
+					PrivateIngressEnabled: true,
+					IPv6Enabled:           true,
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "tracing",
+			env: map[string]string{
+				"HCLOUD_TRACING_ENABLED": "true",
+			},
+			want: HCCMConfiguration{
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
+				Metrics:      MetricsConfiguration{Enabled: true, Address: ":8233"},
+				Tracing:      TracingConfiguration{Enabled: true},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
+				ServerCache:  ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
+				Network: NetworkConfiguration{
+					AttachedCheckEnabled: true,
+				},
+				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -196,13 +250,16 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 				"ROBOT_PASSWORD":             "secret-password",
 				"ROBOT_RATE_LIMIT_WAIT_TIME": "5m",
 				"ROBOT_CACHE_TIMEOUT":        "1m",
+				"ROBOT_RESET_CACHE_TIMEOUT":  "10s",
 			},
 			want: HCCMConfiguration{
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
 				Robot: RobotConfiguration{
 					Enabled:            true,
 					User:               "foobar",
 					Password:           "secret-password",
 					CacheTimeout:       1 * time.Minute,
+					ResetCacheTimeout:  10 * time.Second,
 					RateLimitWaitTime:  5 * time.Minute,
 					ForwardInternalIPs: true,
 				},
@@ -213,6 +270,8 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -231,11 +290,13 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 				"ROBOT_FORWARD_INTERNAL_IPS": "false",
 			},
 			want: HCCMConfiguration{
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
 				Robot: RobotConfiguration{
 					Enabled:            true,
 					User:               "foobar",
 					Password:           "secret-password",
 					CacheTimeout:       1 * time.Minute,
+					ResetCacheTimeout:  30 * time.Second,
 					RateLimitWaitTime:  5 * time.Minute,
 					ForwardInternalIPs: false,
 				},
@@ -246,6 +307,8 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -258,16 +321,38 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 			env: map[string]string{
 				"HCLOUD_INSTANCES_ADDRESS_FAMILY":     "ipv6",
 				"HCLOUD_INSTANCES_ZONE_LABEL_ENABLED": "false",
+				"HCLOUD_INSTANCES_LABELS":             "cpu-type, cores,,placement-group",
+				"HCLOUD_INSTANCES_SERVER_LABELS":      "role,team-*",
+				"HCLOUD_INSTANCES_STATE_TAINTS":       "locked=NoSchedule, in-process = NoExecute",
+				"HCLOUD_INSTANCES_TOPOLOGY_REGION":    "network-zone",
+				"HCLOUD_INSTANCES_TOPOLOGY_ZONE":      "location",
+				"HCLOUD_INSTANCES_FLOATING_IPS":       "first",
+				"HCLOUD_INSTANCES_NODE_AGENT_ENABLED": "true",
 			},
 			want: HCCMConfiguration{
-				Robot:       RobotConfiguration{CacheTimeout: 5 * time.Minute},
-				Metrics:     MetricsConfiguration{Enabled: true, Address: ":8233"},
-				Instance:    InstanceConfiguration{AddressFamily: AddressFamilyIPv6, ZoneLabelEnabled: false},
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
+				Metrics:      MetricsConfiguration{Enabled: true, Address: ":8233"},
+				Instance: InstanceConfiguration{
+					AddressFamily:    AddressFamilyIPv6,
+					ZoneLabelEnabled: false,
+					Labels:           []InstanceLabel{InstanceLabelCPUType, InstanceLabelCores, InstanceLabelPlacementGroup},
+					ServerLabels:     []string{"role", "team-*"},
+					StateTaints: map[ServerState]corev1.TaintEffect{
+						ServerStateLocked:    corev1.TaintEffectNoSchedule,
+						ServerStateInProcess: corev1.TaintEffectNoExecute,
+					},
+					Topology:         TopologyConfiguration{Region: topology.LevelNetworkZone, Zone: topology.LevelLocation},
+					FloatingIPs:      FloatingIPsFirst,
+					NodeAgentEnabled: true,
+				},
 				ServerCache: ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
 				Network: NetworkConfiguration{
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -282,11 +367,14 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 				"HCLOUD_NETWORK":                        "foobar",
 			},
 			want: HCCMConfiguration{
-				Robot:       RobotConfiguration{CacheTimeout: 5 * time.Minute},
-				Metrics:     MetricsConfiguration{Enabled: true, Address: ":8233"},
-				Instance:    InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
-				ServerCache: ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
+				Metrics:      MetricsConfiguration{Enabled: true, Address: ":8233"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
+				ServerCache:  ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -306,11 +394,14 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 				"HCLOUD_NETWORK_ROUTES_ENABLED": "false",
 			},
 			want: HCCMConfiguration{
-				Robot:       RobotConfiguration{CacheTimeout: 5 * time.Minute},
-				Metrics:     MetricsConfiguration{Enabled: true, Address: ":8233"},
-				Instance:    InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
-				ServerCache: ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
+				Metrics:      MetricsConfiguration{Enabled: true, Address: ":8233"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
+				ServerCache:  ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
 				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
 					Enabled:               true,
 					PrivateIngressEnabled: true,
 					IPv6Enabled:           true,
@@ -326,28 +417,39 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 		{
 			name: "load balancer",
 			env: map[string]string{
-				"HCLOUD_LOAD_BALANCERS_ENABLED":                 "false",
-				"HCLOUD_LOAD_BALANCERS_LOCATION":                "nbg1",
-				"HCLOUD_LOAD_BALANCERS_NETWORK_ZONE":            "eu-central",
-				"HCLOUD_LOAD_BALANCERS_DISABLE_PRIVATE_INGRESS": "true",
-				"HCLOUD_LOAD_BALANCERS_USE_PRIVATE_IP":          "true",
-				"HCLOUD_LOAD_BALANCERS_DISABLE_IPV6":            "true",
+				"HCLOUD_LOAD_BALANCERS_ENABLED":                    "false",
+				"HCLOUD_LOAD_BALANCERS_LOCATION":                   "nbg1",
+				"HCLOUD_LOAD_BALANCERS_NETWORK_ZONE":               "eu-central",
+				"HCLOUD_LOAD_BALANCERS_DISABLE_PRIVATE_INGRESS":    "true",
+				"HCLOUD_LOAD_BALANCERS_USE_PRIVATE_IP":             "true",
+				"HCLOUD_LOAD_BALANCERS_DISABLE_IPV6":               "true",
+				"HCLOUD_LOAD_BALANCERS_NAMESPACE_DEFAULTS_ENABLED": "true",
+				"HCLOUD_LOAD_BALANCERS_PROFILES_ENABLED":           "true",
+				"HCLOUD_LOAD_BALANCERS_CLASSES":                    `{"example.com/internal": {"load-balancer.hetzner.cloud/type": "lb21"}}`,
 			},
 			want: HCCMConfiguration{
-				Robot:       RobotConfiguration{CacheTimeout: 5 * time.Minute},
-				Metrics:     MetricsConfiguration{Enabled: true, Address: ":8233"},
-				Instance:    InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
-				ServerCache: ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second},
+				Metrics:      MetricsConfiguration{Enabled: true, Address: ":8233"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
+				ServerCache:  ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 10 * time.Second},
 				Network: NetworkConfiguration{
 					AttachedCheckEnabled: true,
 				},
 				LoadBalancer: LoadBalancerConfiguration{
-					Enabled:               false,
-					Location:              "nbg1",
-					NetworkZone:           "eu-central",
-					PrivateIngressEnabled: false,
-					PrivateIPEnabled:      true,
-					IPv6Enabled:           false,
+					ReconcileTimeout:         10 * time.Minute,
+					APICallTimeout:           5 * time.Second,
+					Enabled:                  false,
+					Location:                 "nbg1",
+					NetworkZone:              "eu-central",
+					PrivateIngressEnabled:    false,
+					PrivateIPEnabled:         true,
+					IPv6Enabled:              false,
+					NamespaceDefaultsEnabled: true,
+					ProfilesEnabled:          true,
+					Classes: map[string]map[string]string{
+						"example.com/internal": {"load-balancer.hetzner.cloud/type": "lb21"},
+					},
 				},
 			},
 			wantErr: nil,
@@ -359,6 +461,13 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 			},
 			wantErr: errors.New(`failed to parse HCLOUD_DEBUG: strconv.ParseBool: parsing "foo": invalid syntax`),
 		},
+		{
+			name: "error parsing state taints",
+			env: map[string]string{
+				"HCLOUD_INSTANCES_STATE_TAINTS": "locked",
+			},
+			wantErr: errors.New(`failed to parse HCLOUD_INSTANCES_STATE_TAINTS: expected <state>=<effect>, got "locked"`),
+		},
 		{
 			name: "error parsing duration values",
 			env: map[string]string{
@@ -368,6 +477,75 @@ failed to read ROBOT_PASSWORD_FILE: open /tmp/hetzner-password: no such file or
 			wantErr: errors.New(`failed to parse ROBOT_CACHE_TIMEOUT: time: invalid duration "biweekly"
 failed to parse ROBOT_RATE_LIMIT_WAIT_TIME: time: unknown unit "fortnights" in duration "42fortnights"`),
 		},
+		{
+			name: "config file",
+			env: map[string]string{
+				"HCLOUD_CONFIG_FILE":             "/tmp/hccm-config.yaml",
+				"HCLOUD_LOAD_BALANCERS_LOCATION": "hel1",
+			},
+			files: map[string]string{
+				"hccm-config.yaml": `
+robot:
+  rateLimitWaitTime: 1m
+serverCache:
+  maxAge: 30s
+loadBalancer:
+  location: fsn1
+  type: lb21
+  healthCheckInterval: 5s
+  ipv6Enabled: false
+  disablePublicNetwork: true
+`,
+			},
+			want: HCCMConfiguration{
+				HCloudClient: HCloudClientConfiguration{RetryBudget: 15 * time.Second},
+				Robot:        RobotConfiguration{CacheTimeout: 5 * time.Minute, ResetCacheTimeout: 30 * time.Second, RateLimitWaitTime: time.Minute},
+				Metrics:      MetricsConfiguration{Enabled: true, Address: ":8233"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ZoneLabelEnabled: true},
+				ServerCache:  ServerCacheConfiguration{Mode: cache.ModeAll, MaxAge: 30 * time.Second},
+				Network: NetworkConfiguration{
+					AttachedCheckEnabled: true,
+				},
+				LoadBalancer: LoadBalancerConfiguration{
+					ReconcileTimeout:      10 * time.Minute,
+					APICallTimeout:        5 * time.Second,
+					Enabled:               true,
+					Location:              "hel1",
+					Type:                  "lb21",
+					HealthCheckInterval:   5 * time.Second,
+					PrivateIngressEnabled: true,
+					IPv6Enabled:           false,
+					DisablePublicNetwork:  hcloud.Ptr(true),
+				},
+				File: "/tmp/hccm-config.yaml",
+			},
+		},
+		{
+			name: "config file with unknown field",
+			env: map[string]string{
+				"HCLOUD_CONFIG_FILE": "/tmp/hccm-config.yaml",
+			},
+			files: map[string]string{
+				"hccm-config.yaml": `
+loadBalancer:
+  loaction: fsn1
+`,
+			},
+			wantErr: errors.New(`failed to parse HCLOUD_CONFIG_FILE "/tmp/hccm-config.yaml": error unmarshaling JSON: while decoding JSON: json: unknown field "loaction"`),
+		},
+		{
+			name: "config file with invalid duration",
+			env: map[string]string{
+				"HCLOUD_CONFIG_FILE": "/tmp/hccm-config.yaml",
+			},
+			files: map[string]string{
+				"hccm-config.yaml": `
+serverCache:
+  maxAge: 30
+`,
+			},
+			wantErr: errors.New(`failed to parse HCLOUD_CONFIG_FILE "/tmp/hccm-config.yaml": error unmarshaling JSON: while decoding JSON: invalid duration 30: must be a string like "30s"`),
+		},
 	}
 
 	for _, tt := range tests {
@@ -395,6 +573,7 @@ func TestHCCMConfiguration_Validate(t *testing.T) {
 		HCloudClient HCloudClientConfiguration
 		Robot        RobotConfiguration
 		Metrics      MetricsConfiguration
+		Debug        DebugConfiguration
 		Instance     InstanceConfiguration
 		LoadBalancer LoadBalancerConfiguration
 		Network      NetworkConfiguration
@@ -438,6 +617,35 @@ func TestHCCMConfiguration_Validate(t *testing.T) {
 			},
 			wantErr: errors.New("environment variable \"HCLOUD_TOKEN\" is required"),
 		},
+		{
+			name: "retry budget negative",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{
+					Token:       "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq",
+					RetryBudget: -time.Second,
+				},
+				Instance: InstanceConfiguration{AddressFamily: AddressFamilyIPv4},
+			},
+			wantErr: errors.New("invalid value for \"HCLOUD_RETRY_BUDGET\", must not be negative"),
+		},
+		{
+			name: "reconcile timeout negative",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4},
+				LoadBalancer: LoadBalancerConfiguration{ReconcileTimeout: -time.Minute},
+			},
+			wantErr: errors.New("invalid value for \"HCLOUD_LOAD_BALANCERS_RECONCILE_TIMEOUT\", must not be negative"),
+		},
+		{
+			name: "api call timeout negative",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4},
+				LoadBalancer: LoadBalancerConfiguration{APICallTimeout: -time.Second},
+			},
+			wantErr: errors.New("invalid value for \"HCLOUD_LOAD_BALANCERS_API_CALL_TIMEOUT\", must not be negative"),
+		},
 		{
 			name: "address family invalid",
 			fields: fields{
@@ -446,6 +654,105 @@ func TestHCCMConfiguration_Validate(t *testing.T) {
 			},
 			wantErr: errors.New("invalid value for \"HCLOUD_INSTANCES_ADDRESS_FAMILY\", expect one of: ipv4,ipv6,dualstack"),
 		},
+		{
+			name: "floating ips invalid",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, FloatingIPs: "middle"},
+			},
+			wantErr: errors.New("invalid value for \"HCLOUD_INSTANCES_FLOATING_IPS\", expect one of: first,last"),
+		},
+		{
+			name: "instance label invalid",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, Labels: []InstanceLabel{InstanceLabelCores, "rack"}},
+			},
+			wantErr: errors.New("invalid value \"rack\" for \"HCLOUD_INSTANCES_LABELS\", expect any of: cpu-type,architecture,cores,memory-gb,disk-gb,network-zone,placement-group,os-flavor,product,datacenter"),
+		},
+		{
+			name: "server label with wildcard in the middle",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, ServerLabels: []string{"role", "te*am"}},
+			},
+			wantErr: errors.New("invalid value \"te*am\" for \"HCLOUD_INSTANCES_SERVER_LABELS\", \"*\" is only allowed at the end"),
+		},
+		{
+			name: "topology level invalid",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, Topology: TopologyConfiguration{Region: "country"}},
+			},
+			wantErr: errors.New("invalid value for \"HCLOUD_INSTANCES_TOPOLOGY_REGION\", expect one of: network-zone,location,datacenter"),
+		},
+		{
+			name: "topology zone coarser than region",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4, Topology: TopologyConfiguration{Zone: topology.LevelNetworkZone}},
+			},
+			wantErr: errors.New("invalid value for \"HCLOUD_INSTANCES_TOPOLOGY_REGION\"/\"HCLOUD_INSTANCES_TOPOLOGY_ZONE\", the zone must not be coarser than the region"),
+		},
+		{
+			name: "state taint invalid",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance: InstanceConfiguration{AddressFamily: AddressFamilyIPv4, StateTaints: map[ServerState]corev1.TaintEffect{
+					ServerStateLocked: corev1.TaintEffectNoSchedule,
+					"off":             "Evict",
+				}},
+			},
+			wantErr: errors.New("invalid state \"off\" for \"HCLOUD_INSTANCES_STATE_TAINTS\", expect any of: locked,rescue,migrating,in-process\n" +
+				"invalid effect \"Evict\" for \"HCLOUD_INSTANCES_STATE_TAINTS\", expect one of: NoSchedule,PreferNoSchedule,NoExecute"),
+		},
+		{
+			name: "projects invalid",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{
+					Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq",
+					Projects: []ProjectConfiguration{
+						{Name: "team-a", Token: "team-a-token"},
+						{Name: "team-a", Token: "team-a-token"},
+						{Name: "bm-legacy", Token: "legacy-token"},
+						{Name: "Team_B", Token: "team-b-token"},
+						{Name: "team-c"},
+					},
+				},
+				Instance: InstanceConfiguration{AddressFamily: AddressFamilyIPv4},
+			},
+			wantErr: errors.New("duplicate project name \"team-a\" for \"HCLOUD_PROJECTS\"\n" +
+				"invalid project name \"bm-legacy\" for \"HCLOUD_PROJECTS\": must not start with \"bm-\"\n" +
+				"invalid project name \"Team_B\" for \"HCLOUD_PROJECTS\": a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')\n" +
+				"environment variable \"HCLOUD_PROJECT_TEAM_C_TOKEN\" is required for project \"team-c\""),
+		},
+		{
+			name: "projects & routes activated",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{
+					Token:    "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq",
+					Projects: []ProjectConfiguration{{Name: "team-a", Token: "team-a-token"}},
+				},
+				Instance: InstanceConfiguration{AddressFamily: AddressFamilyIPv4},
+				Route:    RouteConfiguration{Enabled: true},
+			},
+			wantErr: errors.New("using Routes with \"HCLOUD_PROJECTS\" is not supported"),
+		},
+		{
+			name: "address policy invalid",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance: InstanceConfiguration{AddressFamily: AddressFamilyIPv4, AddressPolicies: []AddressPolicy{
+					{Name: "valid", Provider: AddressProviderCloud, NodeSelector: "role=gateway", Sources: []AddressSource{AddressSourcePublicIPv6}, IPv6HostSuffix: "::10"},
+					{Name: "gateways", Provider: "dedicated", Sources: []AddressSource{"private"}, IPv6HostSuffix: "1::1"},
+					{Name: "empty"},
+				}},
+			},
+			wantErr: errors.New("invalid address policy \"gateways\": invalid provider \"dedicated\", expect one of: cloud,robot\n" +
+				"invalid address policy \"gateways\": invalid source \"private\"\n" +
+				"invalid address policy \"gateways\": invalid IPv6 host suffix \"1::1\", must not be longer than 64 bits\n" +
+				"invalid address policy \"empty\": sources must not be empty"),
+		},
 		{
 			name: "cache mode invalid",
 			fields: fields{
@@ -455,6 +762,16 @@ func TestHCCMConfiguration_Validate(t *testing.T) {
 			},
 			wantErr: errors.New("invalid value for \"HCLOUD_SERVER_CACHE_MODE\", expect one of: all,one,off"),
 		},
+		{
+			name: "debug endpoint without metrics",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4},
+				Metrics:      MetricsConfiguration{Enabled: false},
+				Debug:        DebugConfiguration{EndpointEnabled: true},
+			},
+			wantErr: errors.New(`"HCLOUD_DEBUG_ENDPOINT_ENABLED" requires "HCLOUD_METRICS_ENABLED", as the endpoint is served by the metrics server`),
+		},
 		{
 			name: "LB location and network zone set",
 			fields: fields{
@@ -489,6 +806,19 @@ func TestHCCMConfiguration_Validate(t *testing.T) {
 			},
 			wantErr: errors.New("invalid value for \"HCLOUD_LOAD_BALANCERS_ALGORITHM_TYPE\": unsupported value \"invalid\""),
 		},
+		{
+			name: "LB class with unsupported annotation",
+			fields: fields{
+				HCloudClient: HCloudClientConfiguration{Token: "jr5g7ZHpPptyhJzZyHw2Pqu4g9gTqDvEceYpngPf79jN_NOT_VALID_dzhepnahq"},
+				Instance:     InstanceConfiguration{AddressFamily: AddressFamilyIPv4},
+				LoadBalancer: LoadBalancerConfiguration{
+					Classes: map[string]map[string]string{
+						"example.com/internal": {"load-balancer.hetzner.cloud/name": "foo"},
+					},
+				},
+			},
+			wantErr: errors.New("invalid value for \"HCLOUD_LOAD_BALANCERS_CLASSES\": annotation \"load-balancer.hetzner.cloud/name\" is not supported for class \"example.com/internal\""),
+		},
 		{
 			name: "robot enabled without credentials (valid)",
 			fields: fields{
@@ -557,6 +887,7 @@ func TestHCCMConfiguration_Validate(t *testing.T) {
 				HCloudClient: tt.fields.HCloudClient,
 				Robot:        tt.fields.Robot,
 				Metrics:      tt.fields.Metrics,
+				Debug:        tt.fields.Debug,
 				Instance:     tt.fields.Instance,
 				LoadBalancer: tt.fields.LoadBalancer,
 				Network:      tt.fields.Network,
@@ -572,3 +903,54 @@ func TestHCCMConfiguration_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestHCCMConfiguration_Reload(t *testing.T) {
+	current := HCCMConfiguration{
+		Network:      NetworkConfiguration{NameOrID: "foo"},
+		LoadBalancer: LoadBalancerConfiguration{Enabled: true, Location: "fsn1"},
+		ServerCache:  ServerCacheConfiguration{MaxAge: 10 * time.Second},
+	}
+	next := HCCMConfiguration{
+		// Credentials are rotated separately and must not be reported.
+		HCloudClient: HCloudClientConfiguration{Token: "rotated"},
+		Network:      NetworkConfiguration{NameOrID: "bar"},
+		LoadBalancer: LoadBalancerConfiguration{Enabled: false, Location: "hel1", DisablePublicNetwork: hcloud.Ptr(true)},
+		ServerCache:  ServerCacheConfiguration{MaxAge: 30 * time.Second},
+	}
+
+	reloaded, restartRequired := current.Reload(next)
+	assert.Equal(t, HCCMConfiguration{
+		Network:      NetworkConfiguration{NameOrID: "foo"},
+		LoadBalancer: LoadBalancerConfiguration{Enabled: true, Location: "hel1", DisablePublicNetwork: hcloud.Ptr(true)},
+		ServerCache:  ServerCacheConfiguration{MaxAge: 30 * time.Second},
+	}, reloaded)
+	assert.Equal(t, []string{"LoadBalancer.Enabled", "Network.NameOrID"}, restartRequired)
+
+	// The original configuration is not modified.
+	assert.Equal(t, "fsn1", current.LoadBalancer.Location)
+}
+
+func TestReadCredentials(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token-from-file\n"), 0o600))
+
+	t.Setenv("HCLOUD_TOKEN_FILE", tokenFile)
+	t.Setenv("ROBOT_USER", "user")
+	t.Setenv("ROBOT_USER_FILE", "/does/not/exist")
+
+	projectTokenFile := filepath.Join(t.TempDir(), "project-token")
+	require.NoError(t, os.WriteFile(projectTokenFile, []byte("project-token-from-file\n"), 0o600))
+	t.Setenv("HCLOUD_PROJECT_TEAM_A_TOKEN_FILE", projectTokenFile)
+	projects := []ProjectConfiguration{{Name: "team-a"}}
+
+	creds, err := ReadCredentials(projects)
+	require.NoError(t, err)
+	assert.Equal(t, Credentials{
+		HCloudToken:   "token-from-file",
+		RobotUser:     "user",
+		ProjectTokens: map[string]string{"team-a": "project-token-from-file"},
+	}, creds)
+
+	// ROBOT_USER takes precedence, so its file is not watched.
+	assert.Equal(t, []string{tokenFile, projectTokenFile}, CredentialFiles(projects))
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"os"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/exp/kit/envutil"
+)
+
+// Credentials are the secrets used to authenticate against the Hetzner APIs.
+type Credentials struct {
+	HCloudToken   string
+	RobotUser     string
+	RobotPassword string
+	// ProjectTokens are the API tokens of the additional projects by name.
+	ProjectTokens map[string]string
+}
+
+// ReadCredentials reads the credentials from the environment variables or the
+// files referenced by them, like [Read] does. The tokens of projects are read
+// from the environment variables returned by [ProjectTokenEnv].
+func ReadCredentials(projects []ProjectConfiguration) (Credentials, error) {
+	var creds Credentials
+	var err error
+	var errs []error
+
+	creds.HCloudToken, err = envutil.LookupEnvWithFile(hcloudToken)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	creds.RobotUser, err = envutil.LookupEnvWithFile(robotUser)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	creds.RobotPassword, err = envutil.LookupEnvWithFile(robotPassword)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(projects) > 0 {
+		creds.ProjectTokens = make(map[string]string, len(projects))
+	}
+	for _, project := range projects {
+		creds.ProjectTokens[project.Name], err = envutil.LookupEnvWithFile(ProjectTokenEnv(project.Name))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return creds, errors.Join(errs...)
+}
+
+// CredentialFiles returns the paths of all files the credentials, including the
+// tokens of projects, are read from. Credentials set directly in an environment
+// variable take precedence, so their files are not returned.
+func CredentialFiles(projects []ProjectConfiguration) []string {
+	keys := []string{hcloudToken, robotUser, robotPassword}
+	for _, project := range projects {
+		keys = append(keys, ProjectTokenEnv(project.Name))
+	}
+
+	var files []string
+	for _, key := range keys {
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		if file, ok := os.LookupEnv(key + "_FILE"); ok && file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// readFile reads the YAML or JSON configuration file at path into cfg. Values
+// which are not set in the file are left unchanged. Unknown fields are
+// rejected to catch typos.
+func readFile(path string, cfg *HCCMConfiguration) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", hcloudConfigFile, err)
+	}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return fmt.Errorf("failed to parse %s %q: %w", hcloudConfigFile, path, err)
+	}
+	return nil
+}
+
+// duration is a [time.Duration], which is represented as a duration string
+// like "30s" in the configuration file.
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration %s: must be a string like \"30s\"", data)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+func (c *RobotConfiguration) UnmarshalJSON(data []byte) error {
+	type plain RobotConfiguration
+	aux := struct {
+		*plain
+		CacheTimeout      *duration `json:"cacheTimeout"`
+		RateLimitWaitTime *duration `json:"rateLimitWaitTime"`
+	}{plain: (*plain)(c)}
+
+	if err := decodeStrict(data, &aux); err != nil {
+		return err
+	}
+	setDuration(&c.CacheTimeout, aux.CacheTimeout)
+	setDuration(&c.RateLimitWaitTime, aux.RateLimitWaitTime)
+	return nil
+}
+
+func (c *ServerCacheConfiguration) UnmarshalJSON(data []byte) error {
+	type plain ServerCacheConfiguration
+	aux := struct {
+		*plain
+		MaxAge *duration `json:"maxAge"`
+	}{plain: (*plain)(c)}
+
+	if err := decodeStrict(data, &aux); err != nil {
+		return err
+	}
+	setDuration(&c.MaxAge, aux.MaxAge)
+	return nil
+}
+
+func (c *LoadBalancerConfiguration) UnmarshalJSON(data []byte) error {
+	type plain LoadBalancerConfiguration
+	aux := struct {
+		*plain
+		HealthCheckInterval *duration `json:"healthCheckInterval"`
+		HealthCheckTimeout  *duration `json:"healthCheckTimeout"`
+	}{plain: (*plain)(c)}
+
+	if err := decodeStrict(data, &aux); err != nil {
+		return err
+	}
+	setDuration(&c.HealthCheckInterval, aux.HealthCheckInterval)
+	setDuration(&c.HealthCheckTimeout, aux.HealthCheckTimeout)
+	return nil
+}
+
+// decodeStrict is used by the custom unmarshalers, as the strictness of
+// [yaml.UnmarshalStrict] does not propagate to them.
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func setDuration(dst *time.Duration, d *duration) {
+	if d != nil {
+		*dst = time.Duration(*d)
+	}
+}
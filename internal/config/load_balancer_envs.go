@@ -92,4 +92,51 @@ const (
 	// Type: bool
 	// Default: false
 	hcloudLoadBalancersUsesProxyProtocol = "HCLOUD_LOAD_BALANCERS_USES_PROXYPROTOCOL"
+
+	// hcloudLoadBalancersNamespaceDefaultsEnabled enables reading Load Balancer annotations from the
+	// Namespace of a Service. They are used as defaults, which override the environment variables
+	// and are overridden by the annotations of the Service.
+	//
+	// Type: bool
+	// Default: false
+	hcloudLoadBalancersNamespaceDefaultsEnabled = "HCLOUD_LOAD_BALANCERS_NAMESPACE_DEFAULTS_ENABLED"
+
+	// hcloudLoadBalancersClasses configures the Load Balancer classes handled in addition to
+	// Services without a spec.loadBalancerClass. The value is a JSON object, which maps each
+	// class name to a JSON object of annotations. The annotations are used as defaults for all
+	// Services of this class, e.g. {"example.com/internal": {"load-balancer.hetzner.cloud/type": "lb21"}}.
+	//
+	// Can also be read from the file referenced by HCLOUD_LOAD_BALANCERS_CLASSES_FILE.
+	//
+	// Type: string
+	hcloudLoadBalancersClasses = "HCLOUD_LOAD_BALANCERS_CLASSES"
+
+	// hcloudLoadBalancersProfilesEnabled enables the HCloudLoadBalancerProfile custom resource.
+	// The custom resource definition must be installed in the cluster.
+	//
+	// Type: bool
+	// Default: false
+	hcloudLoadBalancersProfilesEnabled = "HCLOUD_LOAD_BALANCERS_PROFILES_ENABLED"
+
+	// hcloudLoadBalancersForeignNodeIPTargetsEnabled adds nodes with a provider ID of another
+	// provider, e.g. on-premise nodes connected via VPN or vSwitch, as IP targets with their
+	// InternalIP. Nodes without a provider ID are never added.
+	//
+	// Type: bool
+	// Default: false
+	hcloudLoadBalancersForeignNodeIPTargetsEnabled = "HCLOUD_LOAD_BALANCERS_FOREIGN_NODE_IP_TARGETS_ENABLED"
+
+	// hcloudLoadBalancersReconcileTimeout limits the duration of a single reconcile of a Service.
+	// When it is exceeded, the reconcile is aborted and retried later. 0 disables the limit.
+	//
+	// Type: duration
+	// Default: 10m
+	hcloudLoadBalancersReconcileTimeout = "HCLOUD_LOAD_BALANCERS_RECONCILE_TIMEOUT"
+
+	// hcloudLoadBalancersAPICallTimeout limits the duration of single API calls during a reconcile,
+	// e.g. the lookup of certificates, in addition to the reconcile timeout. 0 disables the limit.
+	//
+	// Type: duration
+	// Default: 5s
+	hcloudLoadBalancersAPICallTimeout = "HCLOUD_LOAD_BALANCERS_API_CALL_TIMEOUT"
 )
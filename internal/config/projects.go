@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ProjectConfiguration is an additional Hetzner Cloud project, whose servers can
+// be nodes of the cluster. Load Balancers and Networks are always managed in the
+// project of [HCloudClientConfiguration.Token].
+type ProjectConfiguration struct {
+	// Name identifies the project in the ProviderID of its servers. It must not
+	// change as long as nodes of the project exist.
+	Name string `json:"name"`
+	// Token is read from the environment variable returned by [ProjectTokenEnv].
+	Token string `json:"-"`
+}
+
+// ProjectTokenEnv returns the environment variable of the API token of a
+// project, e.g. HCLOUD_PROJECT_TEAM_A_TOKEN for the project team-a.
+func ProjectTokenEnv(name string) string {
+	return "HCLOUD_PROJECT_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_TOKEN"
+}
+
+func validateProjects(projects []ProjectConfiguration) []error {
+	var errs []error
+
+	names := make(map[string]bool, len(projects))
+	for _, project := range projects {
+		if msgs := validation.IsDNS1123Label(project.Name); len(msgs) > 0 {
+			errs = append(errs, fmt.Errorf("invalid project name %q for %q: %s", project.Name, hcloudProjects, strings.Join(msgs, ", ")))
+			continue
+		}
+		// The prefix is used by the legacy ProviderID of Robot servers.
+		if strings.HasPrefix(project.Name, "bm-") {
+			errs = append(errs, fmt.Errorf("invalid project name %q for %q: must not start with \"bm-\"", project.Name, hcloudProjects))
+			continue
+		}
+		if names[project.Name] {
+			errs = append(errs, fmt.Errorf("duplicate project name %q for %q", project.Name, hcloudProjects))
+			continue
+		}
+		names[project.Name] = true
+
+		if project.Token == "" {
+			errs = append(errs, fmt.Errorf("environment variable %q is required for project %q", ProjectTokenEnv(project.Name), project.Name))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// reloadableFields lists the fields of [HCCMConfiguration], which can safely be
+// changed without a restart.
+var reloadableFields = []string{
+	"LoadBalancer.APICallTimeout",
+	"LoadBalancer.AlgorithmType",
+	"LoadBalancer.DisablePublicNetwork",
+	"LoadBalancer.ForeignNodeIPTargetsEnabled",
+	"LoadBalancer.HealthCheckInterval",
+	"LoadBalancer.HealthCheckRetries",
+	"LoadBalancer.HealthCheckTimeout",
+	"LoadBalancer.IPv6Enabled",
+	"LoadBalancer.Location",
+	"LoadBalancer.NetworkZone",
+	"LoadBalancer.PrivateIngressEnabled",
+	"LoadBalancer.PrivateIPEnabled",
+	"LoadBalancer.PrivateSubnetIPRange",
+	"LoadBalancer.ProxyProtocolEnabled",
+	"LoadBalancer.ReconcileTimeout",
+	"LoadBalancer.Type",
+	"Robot.RateLimitWaitTime",
+	"ServerCache.MaxAge",
+}
+
+// Store provides concurrency safe access to a [HCCMConfiguration], which can
+// change at runtime.
+type Store struct {
+	mu  sync.RWMutex
+	cfg HCCMConfiguration
+}
+
+// NewStore returns a [Store] holding cfg.
+func NewStore(cfg HCCMConfiguration) *Store {
+	return &Store{cfg: cfg}
+}
+
+// Get returns the current configuration.
+func (s *Store) Get() HCCMConfiguration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Set replaces the current configuration.
+func (s *Store) Set(cfg HCCMConfiguration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
+
+// Reload returns c with the changes of all fields applied, which can be
+// changed at runtime. It also returns the names of all other changed fields.
+// These changes are not applied, as they require a restart.
+func (c HCCMConfiguration) Reload(next HCCMConfiguration) (HCCMConfiguration, []string) {
+	var restartRequired []string
+
+	current := reflect.ValueOf(&c).Elem()
+	desired := reflect.ValueOf(next)
+	for i := range current.NumField() {
+		section := current.Type().Field(i)
+		if section.Type.Kind() != reflect.Struct {
+			continue
+		}
+
+		for j := range section.Type.NumField() {
+			// Secrets are not part of the configuration file and are rotated
+			// separately.
+			if section.Type.Field(j).Tag.Get("json") == "-" {
+				continue
+			}
+
+			field := current.Field(i).Field(j)
+			nextField := desired.Field(i).Field(j)
+			if reflect.DeepEqual(field.Interface(), nextField.Interface()) {
+				continue
+			}
+
+			name := section.Name + "." + section.Type.Field(j).Name
+			if slices.Contains(reloadableFields, name) {
+				field.Set(nextField)
+			} else {
+				restartRequired = append(restartRequired, name)
+			}
+		}
+	}
+
+	return c, restartRequired
+}
+
+// WatchFile calls onChange whenever the content of the file at path changed.
+// The file is checked every interval until stop is closed. Polling is used
+// instead of file system events, as files mounted from a ConfigMap are
+// replaced by changing a symlink.
+func WatchFile(path string, interval time.Duration, stop <-chan struct{}, onChange func()) {
+	last, err := os.ReadFile(path)
+	if err != nil {
+		klog.ErrorS(err, "failed to read configuration file", "path", path)
+	}
+
+	wait.Until(func() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			klog.ErrorS(err, "failed to read configuration file", "path", path)
+			return
+		}
+		if bytes.Equal(data, last) {
+			return
+		}
+		last = data
+		onChange()
+	}, interval, stop)
+}
@@ -0,0 +1,70 @@
+// Package credentials allows replacing the credentials of API clients at
+// runtime, without recreating the clients.
+package credentials
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Transport is a [http.RoundTripper], which authenticates every request with
+// the current credentials. This overrides any credentials set by the client.
+type Transport struct {
+	// Base is used to send the requests. If nil, [http.DefaultTransport] is used.
+	Base http.RoundTripper
+
+	mu        sync.RWMutex
+	authorize func(req *http.Request)
+}
+
+// NewBearerTokenTransport returns a [Transport], which uses token as bearer
+// token in the Authorization header.
+func NewBearerTokenTransport(base http.RoundTripper, token string) *Transport {
+	t := &Transport{Base: base}
+	t.SetBearerToken(token)
+	return t
+}
+
+// NewBasicAuthTransport returns a [Transport], which uses HTTP basic
+// authentication with the given user and password.
+func NewBasicAuthTransport(base http.RoundTripper, user, password string) *Transport {
+	t := &Transport{Base: base}
+	t.SetBasicAuth(user, password)
+	return t
+}
+
+// SetBearerToken replaces the credentials with the bearer token.
+func (t *Transport) SetBearerToken(token string) {
+	t.set(func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	})
+}
+
+// SetBasicAuth replaces the credentials with user and password.
+func (t *Transport) SetBasicAuth(user, password string) {
+	t.set(func(req *http.Request) {
+		req.SetBasicAuth(user, password)
+	})
+}
+
+func (t *Transport) set(authorize func(req *http.Request)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.authorize = authorize
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	authorize := t.authorize
+	t.mu.RUnlock()
+
+	// A RoundTripper must not modify the request.
+	req = req.Clone(req.Context())
+	authorize(req)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,51 @@
+package credentials
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	do := func(transport *Transport) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer stale")
+
+		resp, err := (&http.Client{Transport: transport}).Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, "Bearer stale", req.Header.Get("Authorization"), "request must not be modified")
+	}
+
+	t.Run("bearer token", func(t *testing.T) {
+		transport := NewBearerTokenTransport(nil, "old")
+		do(transport)
+		assert.Equal(t, "Bearer old", authorization)
+
+		transport.SetBearerToken("new")
+		do(transport)
+		assert.Equal(t, "Bearer new", authorization)
+	})
+
+	t.Run("basic auth", func(t *testing.T) {
+		transport := NewBasicAuthTransport(nil, "user", "old")
+		do(transport)
+		assert.Equal(t, "Basic dXNlcjpvbGQ=", authorization)
+
+		transport.SetBasicAuth("user", "new")
+		do(transport)
+		assert.Equal(t, "Basic dXNlcjpuZXc=", authorization)
+	})
+}
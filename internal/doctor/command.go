@@ -0,0 +1,114 @@
+package doctor
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	hrobot "github.com/syself/hrobot-go"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+const apiClientTimeout = 15 * time.Second
+
+// NewCommand returns the doctor subcommand.
+func NewCommand(version string) *cobra.Command {
+	var kubeconfig string
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the configuration and environment of the hcloud-cloud-controller-manager",
+		Long: `Diagnose the configuration and environment of the hcloud-cloud-controller-manager.
+
+The configuration is read from the same environment variables and files as
+the hcloud-cloud-controller-manager. The checks only read from the APIs and
+do not change anything.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			d, report := newDoctor(kubeconfig, version)
+			if d != nil {
+				report = append(report, d.Run(cmd.Context())...)
+			}
+
+			report.Print(cmd.OutOrStdout())
+			if report.Failed() {
+				return errors.New("some checks failed")
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig file. Required to check the Nodes, if not running in a cluster.")
+
+	// The usage function of the root command prints its own flags.
+	cmd.SetUsageFunc((&cobra.Command{}).UsageFunc())
+	cmd.SetHelpFunc((&cobra.Command{}).HelpFunc())
+
+	return cmd
+}
+
+// newDoctor reads the configuration and creates the API clients. It returns
+// nil, if the configuration is invalid.
+func newDoctor(kubeconfig string, version string) (*Doctor, Report) {
+	const check = "Configuration"
+
+	cfg, err := config.Read()
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		return nil, Report{{
+			Check:   check,
+			Status:  StatusFail,
+			Message: err.Error(),
+			Hint:    "Check the environment variables and the configuration file.",
+		}}
+	}
+	report := Report{{Check: check, Status: StatusPass, Message: "the configuration is valid"}}
+
+	opts := []hcloud.ClientOption{
+		hcloud.WithToken(cfg.HCloudClient.Token),
+		hcloud.WithApplication("hcloud-cloud-controller", version),
+		hcloud.WithHTTPClient(&http.Client{Timeout: apiClientTimeout}),
+	}
+	if cfg.HCloudClient.Endpoint != "" {
+		opts = append(opts, hcloud.WithEndpoint(cfg.HCloudClient.Endpoint))
+	}
+
+	d := &Doctor{
+		Cfg:    cfg,
+		Client: hcloud.NewClient(opts...),
+	}
+
+	if cfg.Robot.User != "" && cfg.Robot.Password != "" {
+		d.RobotClient = hrobot.NewBasicAuthClientWithCustomHttpClient(
+			cfg.Robot.User,
+			cfg.Robot.Password,
+			&http.Client{Timeout: apiClientTimeout},
+		)
+	}
+
+	// Without a kubeconfig, the in-cluster configuration is used.
+	if kubeconfig != "" || os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+		if err == nil {
+			d.KubeClient, err = kubernetes.NewForConfig(restConfig)
+		}
+		if err != nil {
+			report = append(report, Result{
+				Check:   "Kubernetes API",
+				Status:  StatusFail,
+				Message: err.Error(),
+				Hint:    "Check the kubeconfig passed with --kubeconfig.",
+			})
+		}
+	}
+
+	return d, report
+}
@@ -0,0 +1,296 @@
+// Package doctor diagnoses common setup problems of the
+// hcloud-cloud-controller-manager, without starting any controllers.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	hrobot "github.com/syself/hrobot-go"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/providerid"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// Doctor runs the checks against the configured APIs.
+type Doctor struct {
+	Cfg    config.HCCMConfiguration
+	Client *hcloud.Client
+	// RobotClient is nil, if no Robot credentials are configured.
+	RobotClient hrobot.RobotClient
+	// KubeClient is nil, if no connection to the Kubernetes API is available.
+	KubeClient kubernetes.Interface
+
+	report       Report
+	network      *hcloud.Network
+	robotServers []hrobotmodels.Server
+}
+
+// Run runs all checks and returns their results.
+func (d *Doctor) Run(ctx context.Context) Report {
+	d.report = nil
+
+	if d.checkToken(ctx) {
+		d.checkNetwork(ctx)
+		d.checkRobot()
+		d.checkNodes(ctx)
+	}
+
+	return d.report
+}
+
+func (d *Doctor) add(check string, status Status, hint string, format string, args ...any) {
+	d.report = append(d.report, Result{
+		Check:   check,
+		Status:  status,
+		Message: fmt.Sprintf(format, args...),
+		Hint:    hint,
+	})
+}
+
+func (d *Doctor) checkToken(ctx context.Context) bool {
+	const check = "Hetzner Cloud API token"
+
+	_, _, err := d.Client.Location.List(ctx, hcloud.LocationListOpts{ListOpts: hcloud.ListOpts{PerPage: 1}})
+	switch {
+	case hcloud.IsError(err, hcloud.ErrorCodeUnauthorized):
+		d.add(check, StatusFail,
+			"Create a new API token with Read & Write permissions in the Hetzner Console and update HCLOUD_TOKEN.",
+			"the token is invalid")
+		return false
+	case err != nil:
+		d.add(check, StatusFail,
+			"Check the network connectivity to the Hetzner Cloud API and HCLOUD_ENDPOINT.",
+			"%s", err)
+		return false
+	}
+
+	d.add(check, StatusPass, "", "the token is valid")
+	return true
+}
+
+func (d *Doctor) checkNetwork(ctx context.Context) {
+	const check = "Network"
+
+	if d.Cfg.Network.NameOrID == "" {
+		d.add(check, StatusSkip, "", "HCLOUD_NETWORK is not set")
+		return
+	}
+
+	network, _, err := d.Client.Network.Get(ctx, d.Cfg.Network.NameOrID)
+	if err != nil {
+		d.add(check, StatusFail, "", "%s", err)
+		return
+	}
+	if network == nil {
+		d.add(check, StatusFail,
+			"Set HCLOUD_NETWORK to the name or ID of an existing Network in the project of the API token.",
+			"Network %q not found", d.Cfg.Network.NameOrID)
+		return
+	}
+	d.network = network
+
+	if len(network.Subnets) == 0 {
+		d.add(check, StatusWarn,
+			"Add a subnet to the Network and attach the servers of the cluster to it.",
+			"Network %s (%d) has no subnets", network.Name, network.ID)
+		return
+	}
+
+	subnets := make([]string, 0, len(network.Subnets))
+	for _, subnet := range network.Subnets {
+		subnets = append(subnets, fmt.Sprintf("%s (%s)", subnet.IPRange, subnet.Type))
+	}
+	d.add(check, StatusPass, "", "Network %s (%d) with IP range %s and subnets %s",
+		network.Name, network.ID, network.IPRange, strings.Join(subnets, ", "))
+}
+
+func (d *Doctor) checkRobot() {
+	const check = "Robot"
+
+	if !d.Cfg.Robot.Enabled {
+		d.add(check, StatusSkip, "", "ROBOT_ENABLED is not set")
+		return
+	}
+	if d.RobotClient == nil {
+		// Without credentials, only Load Balancer targets from the InternalIP of
+		// the Nodes are supported. This is a valid setup, if the Nodes are
+		// initialized by other means.
+		d.add(check, StatusWarn,
+			"Without ROBOT_USER and ROBOT_PASSWORD, Robot servers are only supported as private Load Balancer targets. Provide the credentials of a Robot webservice user to support the node controllers.",
+			"ROBOT_USER and ROBOT_PASSWORD are not set")
+		return
+	}
+
+	servers, err := d.RobotClient.ServerGetList()
+	switch {
+	case hrobotmodels.IsError(err, hrobotmodels.ErrorCodeNotFound):
+		// The Robot API returns an error if there are no servers.
+		servers = []hrobotmodels.Server{}
+	case hrobotmodels.IsError(err, hrobotmodels.ErrorCodeUnauthorized):
+		d.add(check, StatusFail,
+			"Check ROBOT_USER and ROBOT_PASSWORD. These are the credentials of the webservice user, not of the Robot login.",
+			"the credentials are invalid")
+		return
+	case hrobotmodels.IsError(err, hrobotmodels.ErrorCodeRateLimitExceeded):
+		d.add(check, StatusFail,
+			"The Robot API limits the number of requests per hour. Wait before retrying, and consider increasing ROBOT_CACHE_TIMEOUT.",
+			"the rate limit is exceeded")
+		return
+	case err != nil:
+		d.add(check, StatusFail, "", "%s", err)
+		return
+	}
+	d.robotServers = servers
+
+	d.add(check, StatusPass, "", "the credentials are valid, found %d servers", len(servers))
+}
+
+func (d *Doctor) checkNodes(ctx context.Context) {
+	const check = "Nodes"
+
+	if d.KubeClient == nil {
+		d.add(check, StatusSkip, "", "no connection to the Kubernetes API, pass --kubeconfig to check the Nodes")
+		return
+	}
+
+	nodes, err := d.KubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		d.add(check, StatusFail,
+			"Check the kubeconfig and the permission to list Nodes.",
+			"%s", err)
+		return
+	}
+	if len(nodes.Items) == 0 {
+		d.add(check, StatusWarn, "", "the cluster has no Nodes")
+		return
+	}
+
+	d.add(check, StatusPass, "", "found %d Nodes", len(nodes.Items))
+	for i := range nodes.Items {
+		d.checkNode(ctx, &nodes.Items[i])
+	}
+}
+
+func (d *Doctor) checkNode(ctx context.Context, node *corev1.Node) {
+	check := "Node " + node.Name
+	resultsBefore := len(d.report)
+
+	if node.Spec.ProviderID == "" {
+		d.add(check, StatusWarn,
+			"The providerID is set once the Node was initialized by the hcloud-cloud-controller-manager. Make sure the kubelet runs with --cloud-provider=external.",
+			"the Node has no providerID")
+		return
+	}
+
+	id, isCloudServer, err := providerid.ToServerID(node.Spec.ProviderID)
+	if err != nil {
+		d.add(check, StatusFail,
+			"The providerID must be hcloud://<server-id> or hcloud://<project>/<server-id> for Cloud servers or hrobot://<server-number> for Robot servers.",
+			"%s", err)
+		return
+	}
+	if project, _ := providerid.ToProject(node.Spec.ProviderID); project != "" {
+		d.add(check, StatusSkip, "", "Cloud server %d is in the additional project %s", id, project)
+		return
+	}
+
+	var summary string
+	if isCloudServer {
+		server, _, err := d.Client.Server.GetByID(ctx, id)
+		switch {
+		case err != nil:
+			d.add(check, StatusFail, "", "%s", err)
+			return
+		case server == nil:
+			d.add(check, StatusFail,
+				"The server was deleted or belongs to another project. Delete the Node, if the server does not exist anymore.",
+				"server %d does not exist", id)
+			return
+		}
+
+		if d.network != nil && !slices.ContainsFunc(server.PrivateNet, func(n hcloud.ServerPrivateNet) bool {
+			return n.Network.ID == d.network.ID
+		}) {
+			d.add(check, StatusWarn,
+				"Attach the server to the Network, otherwise it is not reachable by routes and private Load Balancer targets.",
+				"server %d is not attached to Network %s", id, d.network.Name)
+		}
+		summary = fmt.Sprintf("Cloud server %d", id)
+	} else {
+		switch {
+		case !d.Cfg.Robot.Enabled:
+			d.add(check, StatusFail,
+				"Set ROBOT_ENABLED=true and provide ROBOT_USER and ROBOT_PASSWORD to support Robot servers.",
+				"the Node is a Robot server, but Robot support is not configured")
+			return
+		case d.RobotClient == nil:
+			d.add(check, StatusSkip, "", "Robot server %d can not be verified without ROBOT_USER and ROBOT_PASSWORD", id)
+			return
+		case d.robotServers != nil && !slices.ContainsFunc(d.robotServers, func(s hrobotmodels.Server) bool {
+			return int64(s.ServerNumber) == id
+		}):
+			d.add(check, StatusFail,
+				"The server was cancelled or belongs to another Robot account. Delete the Node, if the server does not exist anymore.",
+				"Robot server %d does not exist", id)
+			return
+		}
+		summary = fmt.Sprintf("Robot server %d", id)
+	}
+
+	d.checkPodCIDRs(check, node)
+
+	if len(d.report) == resultsBefore {
+		d.add(check, StatusPass, "", "%s", summary)
+	}
+}
+
+// checkPodCIDRs verifies that the routes for the pod CIDRs of the Node can be
+// created in the Network.
+func (d *Doctor) checkPodCIDRs(check string, node *corev1.Node) {
+	if !d.Cfg.Route.Enabled || d.network == nil {
+		return
+	}
+
+	podCIDRs := node.Spec.PodCIDRs
+	if len(podCIDRs) == 0 && node.Spec.PodCIDR != "" {
+		podCIDRs = []string{node.Spec.PodCIDR}
+	}
+
+	for _, podCIDR := range podCIDRs {
+		ip, cidr, err := net.ParseCIDR(podCIDR)
+		if err != nil {
+			d.add(check, StatusFail, "", "invalid pod CIDR %q: %s", podCIDR, err)
+			continue
+		}
+		if ip.To4() == nil {
+			// Networks only support IPv4 routes.
+			continue
+		}
+
+		networkPrefixLen, _ := d.network.IPRange.Mask.Size()
+		podPrefixLen, _ := cidr.Mask.Size()
+		if !d.network.IPRange.Contains(cidr.IP) || podPrefixLen < networkPrefixLen {
+			d.add(check, StatusFail,
+				"Choose a cluster CIDR within the IP range of the Network.",
+				"pod CIDR %s is not within the IP range %s of Network %s", cidr, d.network.IPRange, d.network.Name)
+			continue
+		}
+
+		for _, subnet := range d.network.Subnets {
+			if subnet.IPRange.Contains(cidr.IP) || cidr.Contains(subnet.IPRange.IP) {
+				d.add(check, StatusFail,
+					"Choose a cluster CIDR, which does not overlap with the subnets of the Network.",
+					"pod CIDR %s overlaps with subnet %s of Network %s", cidr, subnet.IPRange, d.network.Name)
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package doctor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/mocks"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud/schema"
+)
+
+func newTestServer(t *testing.T, authorized bool) *hcloud.Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /locations", func(w http.ResponseWriter, _ *http.Request) {
+		if !authorized {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(schema.ErrorResponse{Error: schema.Error{Code: string(hcloud.ErrorCodeUnauthorized)}})
+			return
+		}
+		json.NewEncoder(w).Encode(schema.LocationListResponse{Locations: []schema.Location{}})
+	})
+	mux.HandleFunc("GET /networks", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.NetworkListResponse{Networks: []schema.Network{{
+			ID:      1,
+			Name:    "my-network",
+			IPRange: "10.0.0.0/8",
+			Subnets: []schema.NetworkSubnet{{Type: "cloud", IPRange: "10.0.0.0/24", NetworkZone: "eu-central"}},
+		}}})
+	})
+	mux.HandleFunc("GET /servers/1", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.ServerGetResponse{Server: schema.Server{
+			ID:         1,
+			PrivateNet: []schema.ServerPrivateNet{{Network: 1, IP: "10.0.0.2"}},
+		}})
+	})
+	mux.HandleFunc("GET /servers/2", func(w http.ResponseWriter, _ *http.Request) {
+		json.NewEncoder(w).Encode(schema.ServerGetResponse{Server: schema.Server{ID: 2}})
+	})
+	mux.HandleFunc("GET /servers/3", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(schema.ErrorResponse{Error: schema.Error{Code: string(hcloud.ErrorCodeNotFound)}})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return hcloud.NewClient(
+		hcloud.WithEndpoint(server.URL),
+		hcloud.WithRetryOpts(hcloud.RetryOpts{BackoffFunc: hcloud.ConstantBackoff(0), MaxRetries: 0}),
+	)
+}
+
+func node(name, providerID string, podCIDRs ...string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{ProviderID: providerID, PodCIDRs: podCIDRs},
+	}
+}
+
+func TestDoctor_Run(t *testing.T) {
+	t.Run("invalid token", func(t *testing.T) {
+		d := &Doctor{Client: newTestServer(t, false)}
+
+		assert.Equal(t, Report{{
+			Check:   "Hetzner Cloud API token",
+			Status:  StatusFail,
+			Message: "the token is invalid",
+			Hint:    "Create a new API token with Read & Write permissions in the Hetzner Console and update HCLOUD_TOKEN.",
+		}}, d.Run(t.Context()))
+	})
+
+	t.Run("without Kubernetes API", func(t *testing.T) {
+		d := &Doctor{Client: newTestServer(t, true)}
+
+		report := d.Run(t.Context())
+		assert.False(t, report.Failed())
+		assert.Equal(t, Report{
+			{Check: "Hetzner Cloud API token", Status: StatusPass, Message: "the token is valid"},
+			{Check: "Network", Status: StatusSkip, Message: "HCLOUD_NETWORK is not set"},
+			{Check: "Robot", Status: StatusSkip, Message: "ROBOT_ENABLED is not set"},
+			{Check: "Nodes", Status: StatusSkip, Message: "no connection to the Kubernetes API, pass --kubeconfig to check the Nodes"},
+		}, report)
+	})
+
+	t.Run("nodes", func(t *testing.T) {
+		robotClient := &mocks.RobotClient{}
+		robotClient.On("ServerGetList").Return([]hrobotmodels.Server{{ServerNumber: 321}}, nil)
+
+		cfg := config.HCCMConfiguration{}
+		cfg.Network.NameOrID = "my-network"
+		cfg.Route.Enabled = true
+		cfg.Robot.Enabled = true
+
+		d := &Doctor{
+			Cfg:         cfg,
+			Client:      newTestServer(t, true),
+			RobotClient: robotClient,
+			KubeClient: fake.NewClientset(
+				node("healthy", "hcloud://1", "10.244.0.0/24", "fd00::/64"),
+				node("detached", "hcloud://2"),
+				node("deleted", "hcloud://3"),
+				node("robot", "hrobot://321"),
+				node("cancelled-robot", "hrobot://123"),
+				node("uninitialized", ""),
+				node("invalid", "aws:///eu-central-1a/i-123"),
+				node("outside-network", "hcloud://1", "192.168.0.0/24"),
+				node("overlapping-subnet", "hcloud://1", "10.0.0.0/16"),
+				node("other-project", "hcloud://team-a/4"),
+			),
+		}
+
+		report := d.Run(t.Context())
+		assert.True(t, report.Failed())
+
+		got := make([]string, 0, len(report))
+		for _, result := range report {
+			got = append(got, string(result.Status)+" "+result.Check+": "+result.Message)
+		}
+		assert.Equal(t, []string{
+			"PASS Hetzner Cloud API token: the token is valid",
+			"PASS Network: Network my-network (1) with IP range 10.0.0.0/8 and subnets 10.0.0.0/24 (cloud)",
+			"PASS Robot: the credentials are valid, found 1 servers",
+			"PASS Nodes: found 10 Nodes",
+			"FAIL Node cancelled-robot: Robot server 123 does not exist",
+			"FAIL Node deleted: server 3 does not exist",
+			"WARN Node detached: server 2 is not attached to Network my-network",
+			"PASS Node healthy: Cloud server 1",
+			`FAIL Node invalid: Provider ID does not have one of the the expected prefixes (hcloud://, hrobot://, hcloud://bm-): aws:///eu-central-1a/i-123`,
+			"SKIP Node other-project: Cloud server 4 is in the additional project team-a",
+			"FAIL Node outside-network: pod CIDR 192.168.0.0/24 is not within the IP range 10.0.0.0/8 of Network my-network",
+			"FAIL Node overlapping-subnet: pod CIDR 10.0.0.0/16 overlaps with subnet 10.0.0.0/24 of Network my-network",
+			"PASS Node robot: Robot server 321",
+			"WARN Node uninitialized: the Node has no providerID",
+		}, got)
+	})
+
+	t.Run("robot without credentials", func(t *testing.T) {
+		cfg := config.HCCMConfiguration{}
+		cfg.Robot.Enabled = true
+
+		d := &Doctor{
+			Cfg:        cfg,
+			Client:     newTestServer(t, true),
+			KubeClient: fake.NewClientset(node("robot", "hrobot://321")),
+		}
+
+		report := d.Run(t.Context())
+		assert.False(t, report.Failed())
+
+		got := make([]string, 0, len(report))
+		for _, result := range report {
+			got = append(got, string(result.Status)+" "+result.Check+": "+result.Message)
+		}
+		assert.Equal(t, []string{
+			"PASS Hetzner Cloud API token: the token is valid",
+			"SKIP Network: HCLOUD_NETWORK is not set",
+			"WARN Robot: ROBOT_USER and ROBOT_PASSWORD are not set",
+			"PASS Nodes: found 1 Nodes",
+			"SKIP Node robot: Robot server 321 can not be verified without ROBOT_USER and ROBOT_PASSWORD",
+		}, got)
+	})
+}
+
+func TestReport_Print(t *testing.T) {
+	report := Report{
+		{Check: "Configuration", Status: StatusPass, Message: "the configuration is valid", Hint: "not printed"},
+		{Check: "Network", Status: StatusFail, Message: `Network "foo" not found`, Hint: "Set HCLOUD_NETWORK."},
+		{Check: "Robot", Status: StatusSkip, Message: "ROBOT_ENABLED is not set"},
+	}
+
+	var out bytes.Buffer
+	report.Print(&out)
+	assert.Equal(t, `[PASS] Configuration: the configuration is valid
+[FAIL] Network: Network "foo" not found
+       Hint: Set HCLOUD_NETWORK.
+[SKIP] Robot: ROBOT_ENABLED is not set
+
+1 passed, 0 warnings, 1 failed, 1 skipped
+`, out.String())
+}
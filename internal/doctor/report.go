@@ -0,0 +1,55 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// Status is the outcome of a single check.
+type Status string
+
+const (
+	StatusPass Status = "PASS"
+	StatusWarn Status = "WARN"
+	StatusFail Status = "FAIL"
+	StatusSkip Status = "SKIP"
+)
+
+// Result is the outcome of a single check, with a hint on how to resolve it.
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+	Hint    string
+}
+
+// Report contains the results of all checks in the order they were run.
+type Report []Result
+
+// Failed returns whether at least one check failed.
+func (r Report) Failed() bool {
+	return slices.ContainsFunc(r, func(result Result) bool {
+		return result.Status == StatusFail
+	})
+}
+
+// Print writes a human-readable version of the report to w.
+func (r Report) Print(w io.Writer) {
+	counts := map[Status]int{}
+	for _, result := range r {
+		counts[result.Status]++
+
+		fmt.Fprintf(w, "[%s] %s", result.Status, result.Check)
+		if result.Message != "" {
+			fmt.Fprintf(w, ": %s", result.Message)
+		}
+		fmt.Fprintln(w)
+		if result.Hint != "" && (result.Status == StatusFail || result.Status == StatusWarn) {
+			fmt.Fprintf(w, "       Hint: %s\n", result.Hint)
+		}
+	}
+
+	fmt.Fprintf(w, "\n%d passed, %d warnings, %d failed, %d skipped\n",
+		counts[StatusPass], counts[StatusWarn], counts[StatusFail], counts[StatusSkip])
+}
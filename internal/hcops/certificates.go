@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
 
@@ -12,15 +13,18 @@ import (
 type CertificateOps struct {
 	ActionClient hcloud.IActionClient
 	CertClient   hcloud.ICertificateClient
+	Retry        RetryPolicy
 }
 
 // GetCertificateByNameOrID obtains a certificate from the Hetzner Cloud
 // backend using its ID or Name.
 //
 // If a certificate could not be found the returned error wraps ErrNotFound.
-func (co *CertificateOps) GetCertificateByNameOrID(ctx context.Context, idOrName string) (*hcloud.Certificate, error) {
+func (co *CertificateOps) GetCertificateByNameOrID(ctx context.Context, idOrName string) (_ *hcloud.Certificate, err error) {
 	const op = "hcops/CertificateOps.GetCertificateByNameOrID"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	cert, _, err := co.CertClient.Get(ctx, idOrName)
 	if err != nil {
@@ -37,9 +41,11 @@ func (co *CertificateOps) GetCertificateByNameOrID(ctx context.Context, idOrName
 // If the label matches more than one certificate a wrapped ErrNonUniqueResult
 // is returned. If no certificate could be found a wrapped ErrNotFound is
 // returned.
-func (co *CertificateOps) GetCertificateByLabel(ctx context.Context, label string) (*hcloud.Certificate, error) {
+func (co *CertificateOps) GetCertificateByLabel(ctx context.Context, label string) (_ *hcloud.Certificate, err error) {
 	const op = "hcops/CertificateOps.GetCertificateByLabel"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	opts := hcloud.CertificateListOpts{ListOpts: hcloud.ListOpts{LabelSelector: label}}
 	certs, err := co.CertClient.AllWithOpts(ctx, opts)
@@ -61,11 +67,20 @@ func (co *CertificateOps) GetCertificateByLabel(ctx context.Context, label strin
 // certificate already exists.
 func (co *CertificateOps) CreateManagedCertificate(
 	ctx context.Context, opts hcloud.CertificateCreateOpts,
-) error {
+) (err error) {
 	const op = "hcops/CertificateOps.CreateManagedCertificate"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
-	result, _, err := co.CertClient.CreateCertificate(ctx, opts)
+	// Only the request is retried, as the certificate exists once it was
+	// accepted.
+	var result hcloud.CertificateCreateResult
+	err = Retry(ctx, op, co.Retry, func() error {
+		var err error
+		result, _, err = co.CertClient.CreateCertificate(ctx, opts)
+		return err
+	})
 	if hcloud.IsError(err, hcloud.ErrorCodeUniquenessError) {
 		return fmt.Errorf("%s: %w", op, ErrAlreadyExists)
 	}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"maps"
 	"net"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,8 +19,10 @@ import (
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/lbprofile"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/providerid"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/utils"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud/exp/deprecationutil"
@@ -41,10 +45,12 @@ type LoadBalancerOps struct {
 	RobotClient   hrobot.RobotClient
 	LBTypeCache   *cache.Cache[hcloud.LoadBalancerType]
 	CertOps       *CertificateOps
-	RetryDelay    time.Duration
+	Retry         RetryPolicy
 	NetworkID     int64
-	Cfg           config.HCCMConfiguration
+	Cfg           *config.Store
 	Recorder      record.EventRecorder
+	// Profiles is only set if HCloudLoadBalancerProfiles are enabled.
+	Profiles *lbprofile.Lister
 }
 
 // GetByK8SServiceUID tries to find a Load Balancer by its Kubernetes service
@@ -53,9 +59,11 @@ type LoadBalancerOps struct {
 // If no Load Balancer could be found ErrNotFound is returned. Likewise,
 // ErrNonUniqueResult is returned if more than one matching Load Balancer is
 // found.
-func (l *LoadBalancerOps) GetByK8SServiceUID(ctx context.Context, svc *corev1.Service) (*hcloud.LoadBalancer, error) {
+func (l *LoadBalancerOps) GetByK8SServiceUID(ctx context.Context, svc *corev1.Service) (_ *hcloud.LoadBalancer, err error) {
 	const op = "hcops/LoadBalancerOps.GetByK8SServiceUID"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	opts := hcloud.LoadBalancerListOpts{
 		ListOpts: hcloud.ListOpts{
@@ -80,9 +88,11 @@ func (l *LoadBalancerOps) GetByK8SServiceUID(ctx context.Context, svc *corev1.Se
 //
 // If no Load Balancer with name could be found, a wrapped ErrNotFound is
 // returned.
-func (l *LoadBalancerOps) GetByName(ctx context.Context, name string) (*hcloud.LoadBalancer, error) {
+func (l *LoadBalancerOps) GetByName(ctx context.Context, name string) (_ *hcloud.LoadBalancer, err error) {
 	const op = "hcops/LoadBalancerOps.GetByName"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	lb, _, err := l.LBClient.GetByName(ctx, name)
 	if err != nil {
@@ -101,9 +111,11 @@ func (l *LoadBalancerOps) GetByName(ctx context.Context, name string) (*hcloud.L
 //
 // If no Load Balancer with id could be found, a wrapped ErrNotFound is
 // returned.
-func (l *LoadBalancerOps) GetByID(ctx context.Context, id int64) (*hcloud.LoadBalancer, error) {
+func (l *LoadBalancerOps) GetByID(ctx context.Context, id int64) (_ *hcloud.LoadBalancer, err error) {
 	const op = "hcops/LoadBalancerOps.GetByName"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	lb, _, err := l.LBClient.GetByID(ctx, id)
 	if err != nil {
@@ -123,8 +135,8 @@ func (l *LoadBalancerOps) getType(ctx context.Context, svc *corev1.Service) (*hc
 	var lbTypeName string
 	var unset bool
 
-	if l.Cfg.LoadBalancer.Type != "" {
-		lbTypeName = l.Cfg.LoadBalancer.Type
+	if l.Cfg.Get().LoadBalancer.Type != "" {
+		lbTypeName = l.Cfg.Get().LoadBalancer.Type
 	}
 
 	if v, ok := annotation.LBType.StringFromService(svc); ok {
@@ -174,9 +186,11 @@ func (l *LoadBalancerOps) getType(ctx context.Context, svc *corev1.Service) (*hc
 // It adds annotations identifying the HC Load Balancer to svc.
 func (l *LoadBalancerOps) Create(
 	ctx context.Context, lbName string, svc *corev1.Service,
-) (*hcloud.LoadBalancer, error) {
+) (_ *hcloud.LoadBalancer, err error) {
 	const op = "hcops/LoadBalancerOps.Create"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	opts := hcloud.LoadBalancerCreateOpts{
 		Name:             lbName,
@@ -192,8 +206,16 @@ func (l *LoadBalancerOps) Create(
 	}
 	opts.LoadBalancerType = lbType
 
-	if l.Cfg.LoadBalancer.Location != "" {
-		opts.Location = &hcloud.Location{Name: l.Cfg.LoadBalancer.Location}
+	svcLabels, err := annotation.LBLabels.LabelsFromService(svc)
+	if err != nil && !errors.Is(err, annotation.ErrNotSet) {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	maps.Copy(opts.Labels, svcLabels)
+	// The service UID must not be overwritten by a user defined label.
+	opts.Labels[LabelServiceUID] = string(svc.ObjectMeta.UID)
+
+	if l.Cfg.Get().LoadBalancer.Location != "" {
+		opts.Location = &hcloud.Location{Name: l.Cfg.Get().LoadBalancer.Location}
 	}
 	if v, ok := annotation.LBLocation.StringFromService(svc); ok {
 		if v == "" {
@@ -204,7 +226,7 @@ func (l *LoadBalancerOps) Create(
 			opts.Location = &hcloud.Location{Name: v}
 		}
 	}
-	opts.NetworkZone = hcloud.NetworkZone(l.Cfg.LoadBalancer.NetworkZone)
+	opts.NetworkZone = hcloud.NetworkZone(l.Cfg.Get().LoadBalancer.NetworkZone)
 	if v, ok := annotation.LBNetworkZone.StringFromService(svc); ok {
 		opts.NetworkZone = hcloud.NetworkZone(v)
 	}
@@ -220,8 +242,8 @@ func (l *LoadBalancerOps) Create(
 	case err == nil:
 		opts.Algorithm = &hcloud.LoadBalancerAlgorithm{Type: algType}
 	case errors.Is(err, annotation.ErrNotSet):
-		if l.Cfg.LoadBalancer.AlgorithmType != "" {
-			opts.Algorithm = &hcloud.LoadBalancerAlgorithm{Type: l.Cfg.LoadBalancer.AlgorithmType}
+		if l.Cfg.Get().LoadBalancer.AlgorithmType != "" {
+			opts.Algorithm = &hcloud.LoadBalancerAlgorithm{Type: l.Cfg.Get().LoadBalancer.AlgorithmType}
 		}
 	default:
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -232,14 +254,21 @@ func (l *LoadBalancerOps) Create(
 	case err == nil:
 		opts.PublicInterface = new(!disablePubIface)
 	case errors.Is(err, annotation.ErrNotSet):
-		if l.Cfg.LoadBalancer.DisablePublicNetwork != nil {
-			opts.PublicInterface = new(!*l.Cfg.LoadBalancer.DisablePublicNetwork)
+		if l.Cfg.Get().LoadBalancer.DisablePublicNetwork != nil {
+			opts.PublicInterface = new(!*l.Cfg.Get().LoadBalancer.DisablePublicNetwork)
 		}
 	default:
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	result, _, err := l.LBClient.Create(ctx, opts)
+	// Only the request is retried, as the Load Balancer exists once it was
+	// accepted.
+	var result hcloud.LoadBalancerCreateResult
+	err = Retry(ctx, op, l.Retry, func() error {
+		var err error
+		result, _, err = l.LBClient.Create(ctx, opts)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
 	}
@@ -255,28 +284,43 @@ func (l *LoadBalancerOps) Create(
 }
 
 // Delete removes a Hetzner Cloud load balancer from the backend.
-func (l *LoadBalancerOps) Delete(ctx context.Context, lb *hcloud.LoadBalancer) error {
+func (l *LoadBalancerOps) Delete(ctx context.Context, lb *hcloud.LoadBalancer) (err error) {
 	const op = "hcops/LoadBalancerOps.Delete"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
-	_, err := l.LBClient.Delete(ctx, lb)
-	if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
-		return nil
-	}
-	if err != nil {
+	err = Retry(ctx, op, l.Retry, func() error {
+		_, err := l.LBClient.Delete(ctx, lb)
+		return err
+	})
+	if err != nil && !hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	metrics.DeleteLoadBalancer(lb.Name)
 	return nil
 }
 
 // ReconcileHCLB configures the Hetzner Cloud Load Balancer to match what is
 // defined for the K8S Load Balancer svc.
-func (l *LoadBalancerOps) ReconcileHCLB(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) ReconcileHCLB(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.ReconcileHCLB"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	var changed bool
 
+	if problems := annotation.UnsupportedFromService(svc); len(problems) > 0 {
+		utils.WarnEventLogf(
+			l.Recorder,
+			svc,
+			"UnsupportedAnnotations",
+			"Ignoring unsupported annotations: %s",
+			strings.Join(problems, "; "),
+		)
+	}
+
 	labelSet, err := l.changeHCLBInfo(ctx, lb, svc)
 	if err != nil {
 		return changed, fmt.Errorf("%s: %w", op, err)
@@ -334,21 +378,35 @@ func (l *LoadBalancerOps) ReconcileHCLB(ctx context.Context, lb *hcloud.LoadBala
 // This is implemented in one method as both changes need to be made using
 // hcloud.LoadBalancerUpdateOpts. Using one method reduces the number of API
 // requests should more than one change be necessary.
-func (l *LoadBalancerOps) changeHCLBInfo(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) changeHCLBInfo(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.changeHCLBInfo"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	var (
 		update bool
 		opts   hcloud.LoadBalancerUpdateOpts
 	)
 
-	if lb.Labels[LabelServiceUID] != string(svc.ObjectMeta.UID) {
-		// Make a defensive copy of labels. This way we do not modify lb unless
-		// updating is really successful. The service UID is set after copying,
-		// so that it replaces a stale value instead of being overwritten by it.
-		labels := make(map[string]string, len(lb.Labels)+1)
+	svcLabels, err := annotation.LBLabels.LabelsFromService(svc)
+	if err != nil && !errors.Is(err, annotation.ErrNotSet) {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Make a defensive copy of labels. This way we do not modify lb unless
+	// updating is really successful. The service UID is set after copying,
+	// so that it replaces a stale value instead of being overwritten by it.
+	updateLabels := lb.Labels[LabelServiceUID] != string(svc.ObjectMeta.UID)
+	for k, v := range svcLabels {
+		if cur, ok := lb.Labels[k]; !ok || cur != v {
+			updateLabels = true
+		}
+	}
+	if updateLabels {
+		labels := make(map[string]string, len(lb.Labels)+len(svcLabels)+1)
 		maps.Copy(labels, lb.Labels)
+		maps.Copy(labels, svcLabels)
 		labels[LabelServiceUID] = string(svc.ObjectMeta.UID)
 		opts.Labels = labels
 		update = true
@@ -363,19 +421,29 @@ func (l *LoadBalancerOps) changeHCLBInfo(ctx context.Context, lb *hcloud.LoadBal
 		return false, nil
 	}
 
-	updated, _, err := l.LBClient.Update(ctx, lb, opts)
+	var updated *hcloud.LoadBalancer
+	err = Retry(ctx, op, l.Retry, func() error {
+		var err error
+		updated, _, err = l.LBClient.Update(ctx, lb, opts)
+		return err
+	})
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
 	}
+	if updated.Name != lb.Name {
+		metrics.DeleteLoadBalancer(lb.Name)
+	}
 	lb.Name = updated.Name
 	lb.Labels = updated.Labels
 
 	return true, nil
 }
 
-func (l *LoadBalancerOps) changeIPv4RDNS(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) changeIPv4RDNS(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.changeIPv4RDNS"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	rdns, ok := annotation.LBPublicIPv4RDNS.StringFromService(svc)
 	// If the annotation is not set, no changes are needed
@@ -387,20 +455,24 @@ func (l *LoadBalancerOps) changeIPv4RDNS(ctx context.Context, lb *hcloud.LoadBal
 		return false, nil
 	}
 
-	action, _, err := l.LBClient.ChangeDNSPtr(ctx, lb, lb.PublicNet.IPv4.IP.String(), &rdns)
-	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
-	}
-	err = l.ActionClient.WaitFor(ctx, action)
+	err = Retry(ctx, op, l.Retry, func() error {
+		action, _, err := l.LBClient.ChangeDNSPtr(ctx, lb, lb.PublicNet.IPv4.IP.String(), &rdns)
+		if err != nil {
+			return withInvalidInputFields(err)
+		}
+		return l.ActionClient.WaitFor(ctx, action)
+	})
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 	return true, nil
 }
 
-func (l *LoadBalancerOps) changeIPv6RDNS(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) changeIPv6RDNS(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.changeIPv6RDNS"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	rdns, ok := annotation.LBPublicIPv6RDNS.StringFromService(svc)
 	// If the annotation is not set, no changes are needed
@@ -412,28 +484,32 @@ func (l *LoadBalancerOps) changeIPv6RDNS(ctx context.Context, lb *hcloud.LoadBal
 		return false, nil
 	}
 
-	action, _, err := l.LBClient.ChangeDNSPtr(ctx, lb, lb.PublicNet.IPv6.IP.String(), &rdns)
-	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
-	}
-	err = l.ActionClient.WaitFor(ctx, action)
+	err = Retry(ctx, op, l.Retry, func() error {
+		action, _, err := l.LBClient.ChangeDNSPtr(ctx, lb, lb.PublicNet.IPv6.IP.String(), &rdns)
+		if err != nil {
+			return withInvalidInputFields(err)
+		}
+		return l.ActionClient.WaitFor(ctx, action)
+	})
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 	return true, nil
 }
 
-func (l *LoadBalancerOps) changeAlgorithm(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) changeAlgorithm(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.changeAlgorithm"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	at, err := annotation.LBAlgorithmType.LBAlgorithmTypeFromService(svc)
 	if err != nil {
 		if errors.Is(err, annotation.ErrNotSet) {
-			if l.Cfg.LoadBalancer.AlgorithmType == "" {
+			if l.Cfg.Get().LoadBalancer.AlgorithmType == "" {
 				return false, nil
 			}
-			at = l.Cfg.LoadBalancer.AlgorithmType
+			at = l.Cfg.Get().LoadBalancer.AlgorithmType
 		} else {
 			return false, fmt.Errorf("%s: %w", op, err)
 		}
@@ -443,20 +519,24 @@ func (l *LoadBalancerOps) changeAlgorithm(ctx context.Context, lb *hcloud.LoadBa
 	}
 
 	opts := hcloud.LoadBalancerChangeAlgorithmOpts{Type: at}
-	action, _, err := l.LBClient.ChangeAlgorithm(ctx, lb, opts)
-	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
-	}
-	err = l.ActionClient.WaitFor(ctx, action)
+	err = Retry(ctx, op, l.Retry, func() error {
+		action, _, err := l.LBClient.ChangeAlgorithm(ctx, lb, opts)
+		if err != nil {
+			return withInvalidInputFields(err)
+		}
+		return l.ActionClient.WaitFor(ctx, action)
+	})
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 	return true, nil
 }
 
-func (l *LoadBalancerOps) changeType(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) changeType(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.changeType"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	opts := hcloud.LoadBalancerChangeTypeOpts{}
 
@@ -477,20 +557,24 @@ func (l *LoadBalancerOps) changeType(ctx context.Context, lb *hcloud.LoadBalance
 		return false, nil
 	}
 
-	action, _, err := l.LBClient.ChangeType(ctx, lb, opts)
-	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
-	}
-	err = l.ActionClient.WaitFor(ctx, action)
+	err = Retry(ctx, op, l.Retry, func() error {
+		action, _, err := l.LBClient.ChangeType(ctx, lb, opts)
+		if err != nil {
+			return withInvalidInputFields(err)
+		}
+		return l.ActionClient.WaitFor(ctx, action)
+	})
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 	return true, nil
 }
 
-func (l *LoadBalancerOps) detachFromNetwork(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) detachFromNetwork(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.detachFromNetwork"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	var changed bool
 
@@ -505,28 +589,31 @@ func (l *LoadBalancerOps) detachFromNetwork(ctx context.Context, lb *hcloud.Load
 		klog.InfoS("detach from network", "op", op, "loadBalancerID", lb.ID, "networkID", lbpn.Network.ID, "privateIPv4", lbpn.IP.String())
 
 		opts := hcloud.LoadBalancerDetachFromNetworkOpts{Network: lbpn.Network}
-		a, _, err := l.LBClient.DetachFromNetwork(ctx, lb, opts)
+		err := Retry(ctx, op, l.Retry, func() error {
+			a, _, err := l.LBClient.DetachFromNetwork(ctx, lb, opts)
+			if err != nil {
+				return err
+			}
+			return l.ActionClient.WaitFor(ctx, a)
+		})
 		if err != nil {
 			return changed, fmt.Errorf("%s: %w", op, err)
 		}
-		if err := l.ActionClient.WaitFor(ctx, a); err != nil {
-			return changed, fmt.Errorf("%s: %w", op, err)
-		}
 		changed = true
 	}
 	return changed, nil
 }
 
-func (l *LoadBalancerOps) attachToNetwork(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) attachToNetwork(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.attachToNetwork"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
-
-	var err error
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	privateIPv4String, privateIPv4configured := annotation.LBPrivateIPv4.StringFromService(svc)
 	subnetString, subnetConfigured := annotation.PrivateSubnetIPRange.StringFromService(svc)
-	if !subnetConfigured && l.Cfg.LoadBalancer.PrivateSubnetIPRange != "" {
-		subnetString = l.Cfg.LoadBalancer.PrivateSubnetIPRange
+	if !subnetConfigured && l.Cfg.Get().LoadBalancer.PrivateSubnetIPRange != "" {
+		subnetString = l.Cfg.Get().LoadBalancer.PrivateSubnetIPRange
 		subnetConfigured = true
 	}
 	// Don't attach the Load Balancer if network is not set, or the load
@@ -565,10 +652,6 @@ func (l *LoadBalancerOps) attachToNetwork(ctx context.Context, lb *hcloud.LoadBa
 		return false, fmt.Errorf("%s: %d: not found", op, l.NetworkID)
 	}
 
-	retryDelay := l.RetryDelay
-	if retryDelay == 0 {
-		retryDelay = time.Second
-	}
 	opts := hcloud.LoadBalancerAttachToNetworkOpts{Network: nw}
 	if privateIPv4 != nil {
 		opts.IP = privateIPv4
@@ -576,30 +659,25 @@ func (l *LoadBalancerOps) attachToNetwork(ctx context.Context, lb *hcloud.LoadBa
 	if subnet != nil {
 		opts.IPRange = subnet
 	}
-	a, _, err := l.LBClient.AttachToNetwork(ctx, lb, opts)
-	if hcloud.IsError(err, hcloud.ErrorCodeConflict, hcloud.ErrorCodeLocked) {
-		klog.InfoS("retry due to conflict or lock",
-			"op", op, "delay", fmt.Sprintf("%v", retryDelay), "err", fmt.Sprintf("%v", err))
-
-		time.Sleep(retryDelay)
-		a, _, err = l.LBClient.AttachToNetwork(ctx, lb, opts)
-	}
+	err = Retry(ctx, op, l.Retry, func() error {
+		a, _, err := l.LBClient.AttachToNetwork(ctx, lb, opts)
+		if err != nil {
+			return withInvalidInputFields(err)
+		}
+		return l.ActionClient.WaitFor(ctx, a)
+	})
 	if err != nil {
-		return false, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
-	}
-
-	if err := l.ActionClient.WaitFor(ctx, a); err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return true, nil
 }
 
-func (l *LoadBalancerOps) togglePublicInterface(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (bool, error) {
+func (l *LoadBalancerOps) togglePublicInterface(ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.togglePublicInterface"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
-
-	var a *hcloud.Action
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	disable, err := annotation.LBDisablePublicNetwork.BoolFromService(svc)
 	var desiredDisable *bool
@@ -607,7 +685,7 @@ func (l *LoadBalancerOps) togglePublicInterface(ctx context.Context, lb *hcloud.
 	case err == nil:
 		desiredDisable = new(disable)
 	case errors.Is(err, annotation.ErrNotSet):
-		desiredDisable = l.Cfg.LoadBalancer.DisablePublicNetwork
+		desiredDisable = l.Cfg.Get().LoadBalancer.DisablePublicNetwork
 	default:
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
@@ -620,18 +698,22 @@ func (l *LoadBalancerOps) togglePublicInterface(ctx context.Context, lb *hcloud.
 		return false, nil
 	}
 
-	if *desiredDisable {
-		a, _, err = l.LBClient.DisablePublicInterface(ctx, lb)
-	} else {
-		a, _, err = l.LBClient.EnablePublicInterface(ctx, lb)
-	}
+	err = Retry(ctx, op, l.Retry, func() error {
+		var a *hcloud.Action
+		var err error
+		if *desiredDisable {
+			a, _, err = l.LBClient.DisablePublicInterface(ctx, lb)
+		} else {
+			a, _, err = l.LBClient.EnablePublicInterface(ctx, lb)
+		}
+		if err != nil {
+			return err
+		}
+		return l.ActionClient.WaitFor(ctx, a)
+	})
 	if err != nil {
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
-
-	if err := l.ActionClient.WaitFor(ctx, a); err != nil {
-		return false, fmt.Errorf("%s: %w", op, err)
-	}
 	return true, nil
 }
 
@@ -639,9 +721,11 @@ func (l *LoadBalancerOps) togglePublicInterface(ctx context.Context, lb *hcloud.
 // Load Balancer when nodes are added or removed to the K8S cluster.
 func (l *LoadBalancerOps) ReconcileHCLBTargets(
 	ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service, nodes []*corev1.Node,
-) (bool, error) {
+) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.ReconcileHCLBTargets"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	var (
 		// Set of all K8S server IDs currently assigned as nodes to this
@@ -658,6 +742,10 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 		// the node with this server id is assigned to the K8S cluster.
 		hclbTargetIDs = make(map[int64]bool)
 
+		// InternalIPs of the nodes of other providers, which are added as IP
+		// targets if enabled.
+		foreignNodeIPs = make(map[string]*corev1.Node)
+
 		// Set of server IPs assigned as targets to the HC Load Balancer. Some
 		// of the entries may get deleted during reconcilement. In this case
 		// the hclbTargetIPs[id] is always false. If hclbTargetIPs[id] is true,
@@ -680,6 +768,24 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 		id, isCloudServer, err := providerid.ToServerID(node.Spec.ProviderID)
 		if err != nil {
 			if errors.As(err, new(*providerid.UnkownPrefixError)) {
+				// Nodes without a ProviderID are not initialized yet, so they
+				// could still turn out to be Cloud or Robot servers.
+				if l.Cfg.Get().LoadBalancer.ForeignNodeIPTargetsEnabled && node.Spec.ProviderID != "" {
+					internalIP := getNodeInternalIP(node)
+					if internalIP == "" {
+						utils.WarnEventLogf(
+							l.Recorder,
+							node,
+							"InternalIPNotConfigured",
+							"Node could not be added to Load Balancer for service %s because it has no InternalIP",
+							svc.Name,
+						)
+						continue
+					}
+					foreignNodeIPs[internalIP] = node
+					continue
+				}
+
 				// ProviderID has unknown prefix, cluster might have non-hccm nodes that can not be added to the
 				// Load Balancer. Emitting an event and ignoring that Node in this reconciliation loop.
 				utils.WarnEventLogf(
@@ -693,6 +799,18 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 			}
 			return changed, fmt.Errorf("%s: %w", op, err)
 		}
+		if project, _ := providerid.ToProject(node.Spec.ProviderID); project != "" {
+			// The Load Balancer can only target servers in its own project.
+			utils.WarnEventLogf(
+				l.Recorder,
+				node,
+				"ServerInOtherProject",
+				"Node could not be added to Load Balancer for service %s because its server is in the project %s",
+				svc.Name,
+				project,
+			)
+			continue
+		}
 		if isCloudServer {
 			k8sNodeIDsHCloud[id] = true
 		} else {
@@ -704,8 +822,8 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 	// List all robot servers to check whether the ip targets of the load balancer
 	// correspond to a dedicated server
 
-	useRobotAPI := l.Cfg.Robot.Enabled && l.RobotClient != nil
-	useRobotInternalIPs := l.Cfg.Robot.Enabled && l.RobotClient == nil && privateIPEnabled
+	useRobotAPI := l.Cfg.Get().Robot.Enabled && l.RobotClient != nil
+	useRobotInternalIPs := l.Cfg.Get().Robot.Enabled && l.RobotClient == nil && privateIPEnabled
 
 	// Use Robot API to either fetch ExternalIP or use InternalIP from Node objects
 	if useRobotAPI {
@@ -804,13 +922,16 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 			klog.InfoS("remove target", "op", op, "service", svc.ObjectMeta.Name, "targetName", nodeName)
 			// Target needs to be re-created or node currently not in use by k8s
 			// Load Balancer. Remove it from the HC Load Balancer
-			a, _, err := l.LBClient.RemoveServerTarget(ctx, lb, target.Server.Server)
+			err := Retry(ctx, op, l.Retry, func() error {
+				a, _, err := l.LBClient.RemoveServerTarget(ctx, lb, target.Server.Server)
+				if err != nil {
+					return err
+				}
+				return l.ActionClient.WaitFor(ctx, a)
+			})
 			if err != nil {
 				return changed, fmt.Errorf("%s: target: %s: %w", op, nodeName, err)
 			}
-			if err := l.ActionClient.WaitFor(ctx, a); err != nil {
-				return changed, fmt.Errorf("%s: target: %s: %w", op, nodeName, err)
-			}
 			changed = true
 			numberOfTargets--
 		}
@@ -820,7 +941,7 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 		if target.Type == hcloud.LoadBalancerTargetTypeIP {
 			ip := target.IP.IP
 			id, foundServer := robotIPsToIDs[ip]
-			hclbTargetIPs[ip] = foundServer && k8sNodeIDsRobot[id]
+			hclbTargetIPs[ip] = (foundServer && k8sNodeIDsRobot[id]) || foreignNodeIPs[ip] != nil
 			if hclbTargetIPs[ip] {
 				continue
 			}
@@ -835,17 +956,14 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 
 			klog.InfoS("remove target", "op", op, "service", svc.ObjectMeta.Name, "targetName", nodeName)
 			// Node currently not in use by k8s Load Balancer. Remove it from the HC Load Balancer.
-			a, _, err := l.LBClient.RemoveIPTarget(ctx, lb, net.ParseIP(ip))
-			if err != nil {
-				var e error
-				if foundServer {
-					e = fmt.Errorf("%s: target: %s: %w", op, nodeName, err)
-				} else {
-					e = fmt.Errorf("%s: targetIP: %s: %w", op, ip, err)
+			err := Retry(ctx, op, l.Retry, func() error {
+				a, _, err := l.LBClient.RemoveIPTarget(ctx, lb, net.ParseIP(ip))
+				if err != nil {
+					return err
 				}
-				return changed, e
-			}
-			if err := l.ActionClient.WaitFor(ctx, a); err != nil {
+				return l.ActionClient.WaitFor(ctx, a)
+			})
+			if err != nil {
 				var e error
 				if foundServer {
 					e = fmt.Errorf("%s: target: %s: %w", op, nodeName, err)
@@ -879,7 +997,13 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 			Server:       &hcloud.Server{ID: id},
 			UsePrivateIP: &privateIPEnabled,
 		}
-		a, _, err := l.LBClient.AddServerTarget(ctx, lb, opts)
+		err := Retry(ctx, op, l.Retry, func() error {
+			a, _, err := l.LBClient.AddServerTarget(ctx, lb, opts)
+			if err != nil {
+				return err
+			}
+			return l.ActionClient.WaitFor(ctx, a)
+		})
 		if err != nil {
 			if hcloud.IsError(err, hcloud.ErrorCodeResourceLimitExceeded) {
 				l.emitMaxTargetsReachedError(node, svc, op)
@@ -888,14 +1012,11 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 			}
 			return changed, fmt.Errorf("%s: target %s: %w", op, node.Name, err)
 		}
-		if err := l.ActionClient.WaitFor(ctx, a); err != nil {
-			return changed, fmt.Errorf("%s: target %s: %w", op, node.Name, err)
-		}
 		changed = true
 		numberOfTargets++
 	}
 
-	if l.Cfg.Robot.Enabled {
+	if l.Cfg.Get().Robot.Enabled {
 		// Assign the dedicated servers which are currently assigned as nodes
 		// to the K8S Load Balancer as IP targets to the HC Load Balancer.
 		for id := range k8sNodeIDsRobot {
@@ -922,7 +1043,13 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 			opts := hcloud.LoadBalancerAddIPTargetOpts{
 				IP: net.ParseIP(ip),
 			}
-			a, _, err := l.LBClient.AddIPTarget(ctx, lb, opts)
+			err := Retry(ctx, op, l.Retry, func() error {
+				a, _, err := l.LBClient.AddIPTarget(ctx, lb, opts)
+				if err != nil {
+					return err
+				}
+				return l.ActionClient.WaitFor(ctx, a)
+			})
 			if err != nil {
 				if hcloud.IsError(err, hcloud.ErrorCodeResourceLimitExceeded) {
 					l.emitMaxTargetsReachedError(node, svc, op)
@@ -930,13 +1057,46 @@ func (l *LoadBalancerOps) ReconcileHCLBTargets(
 				}
 				return changed, fmt.Errorf("%s: target %s: %w", op, node, err)
 			}
-			if err := l.ActionClient.WaitFor(ctx, a); err != nil {
-				return changed, fmt.Errorf("%s: target %s: %w", op, node, err)
-			}
 			changed = true
 			numberOfTargets++
 		}
 	}
+	// Assign the nodes of other providers as IP targets. Sorted to add the
+	// targets in a stable order, if the max number of targets is reached.
+	for _, ip := range slices.Sorted(maps.Keys(foreignNodeIPs)) {
+		node := foreignNodeIPs[ip]
+		if hclbTargetIPs[ip] {
+			continue
+		}
+
+		if numberOfTargets >= lb.LoadBalancerType.MaxTargets {
+			l.emitMaxTargetsReachedError(node, svc, op)
+			continue
+		}
+
+		klog.InfoS("add target (foreign node)", "op", op, "service", svc.ObjectMeta.Name, "targetName", node.Name, "ip", ip)
+		opts := hcloud.LoadBalancerAddIPTargetOpts{
+			IP: net.ParseIP(ip),
+		}
+		err := Retry(ctx, op, l.Retry, func() error {
+			a, _, err := l.LBClient.AddIPTarget(ctx, lb, opts)
+			if err != nil {
+				return err
+			}
+			return l.ActionClient.WaitFor(ctx, a)
+		})
+		if err != nil {
+			if hcloud.IsError(err, hcloud.ErrorCodeResourceLimitExceeded) {
+				l.emitMaxTargetsReachedError(node, svc, op)
+				continue
+			}
+			return changed, fmt.Errorf("%s: target %s: %w", op, node.Name, err)
+		}
+		changed = true
+		numberOfTargets++
+	}
+
+	metrics.SetLoadBalancerTargets(lb.Name, numberOfTargets)
 
 	return changed, nil
 }
@@ -953,7 +1113,7 @@ func (l *LoadBalancerOps) getPrivateIPEnabled(svc *corev1.Service) (bool, error)
 	usePrivateIP, err := annotation.LBUsePrivateIP.BoolFromService(svc)
 	if err != nil {
 		if errors.Is(err, annotation.ErrNotSet) {
-			return l.Cfg.LoadBalancer.PrivateIPEnabled, nil
+			return l.Cfg.Get().LoadBalancer.PrivateIPEnabled, nil
 		}
 		return false, err
 	}
@@ -964,9 +1124,11 @@ func (l *LoadBalancerOps) getPrivateIPEnabled(svc *corev1.Service) (bool, error)
 // Load Balancer with the kubernetes cluster.
 func (l *LoadBalancerOps) ReconcileHCLBServices(
 	ctx context.Context, lb *hcloud.LoadBalancer, svc *corev1.Service,
-) (bool, error) {
+) (_ bool, err error) {
 	const op = "hcops/LoadBalancerOps.ReconcileHCLBServices"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	var changed bool
 
@@ -986,7 +1148,6 @@ func (l *LoadBalancerOps) ReconcileHCLBServices(
 		var (
 			addOpts hcloud.LoadBalancerAddServiceOpts
 			updOpts hcloud.LoadBalancerUpdateServiceOpts
-			action  *hcloud.Action
 
 			err error
 		)
@@ -1007,37 +1168,50 @@ func (l *LoadBalancerOps) ReconcileHCLBServices(
 		portExists := hclbListenPorts[portNo]
 		delete(hclbListenPorts, portNo)
 
+		portSvc := svc
+		if l.Profiles != nil {
+			portSvc, err = l.Profiles.ServiceForPort(svc, port.Port)
+			if err != nil {
+				return changed, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
 		b := &hclbServiceOptsBuilder{
 			Port:    port,
-			Service: svc,
+			Service: portSvc,
 			CertOps: l.CertOps,
-			cfg:     l.Cfg.LoadBalancer,
+			cfg:     l.Cfg.Get().LoadBalancer,
 		}
 		if portExists {
 			klog.InfoS("update service", "op", op, "port", portNo, "loadBalancerID", lb.ID)
 
-			updOpts, err = b.buildUpdateServiceOpts()
+			updOpts, err = b.buildUpdateServiceOpts(ctx)
 			if err != nil {
 				return changed, fmt.Errorf("%s: %w", op, err)
 			}
-			action, _, err = l.LBClient.UpdateService(ctx, lb, b.listenPort, updOpts)
-			if err != nil {
-				return changed, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
-			}
+			err = Retry(ctx, op, l.Retry, func() error {
+				action, _, err := l.LBClient.UpdateService(ctx, lb, b.listenPort, updOpts)
+				if err != nil {
+					return withInvalidInputFields(err)
+				}
+				return l.ActionClient.WaitFor(ctx, action)
+			})
 		} else {
 			klog.InfoS("add service", "op", op, "port", portNo, "loadBalancerID", lb.ID)
 
-			addOpts, err = b.buildAddServiceOpts()
+			addOpts, err = b.buildAddServiceOpts(ctx)
 			if err != nil {
 				return changed, fmt.Errorf("%s: %w", op, err)
 			}
-			action, _, err = l.LBClient.AddService(ctx, lb, addOpts)
-			if err != nil {
-				return changed, fmt.Errorf("%s: %w", op, withInvalidInputFields(err))
-			}
+			err = Retry(ctx, op, l.Retry, func() error {
+				action, _, err := l.LBClient.AddService(ctx, lb, addOpts)
+				if err != nil {
+					return withInvalidInputFields(err)
+				}
+				return l.ActionClient.WaitFor(ctx, action)
+			})
 		}
-
-		if err = l.ActionClient.WaitFor(ctx, action); err != nil {
+		if err != nil {
 			return changed, fmt.Errorf("%s: %w", op, err)
 		}
 		changed = true
@@ -1046,23 +1220,27 @@ func (l *LoadBalancerOps) ReconcileHCLBServices(
 	// Remove any left-over services from the hc Load Balancer.
 	for p := range hclbListenPorts {
 		klog.InfoS("remove service", "op", op, "port", p, "loadBalancerID", lb.ID)
-		a, _, err := l.LBClient.DeleteService(ctx, lb, p)
+		err := Retry(ctx, op, l.Retry, func() error {
+			a, _, err := l.LBClient.DeleteService(ctx, lb, p)
+			if err != nil {
+				return err
+			}
+			return l.ActionClient.WaitFor(ctx, a)
+		})
 		if err != nil {
 			return changed, fmt.Errorf("%s: port %d: %w", op, p, err)
 		}
-		err = l.ActionClient.WaitFor(ctx, a)
-		if err != nil {
-			return changed, fmt.Errorf("%s: port: %d: %w", op, p, err)
-		}
 		changed = true
 	}
 
 	return changed, nil
 }
 
-func (l *LoadBalancerOps) reconcileManagedCertificate(ctx context.Context, svc *corev1.Service) error {
+func (l *LoadBalancerOps) reconcileManagedCertificate(ctx context.Context, svc *corev1.Service) (err error) {
 	const op = "hcops/LoadBalancerOps.reconcileManagedCertificate"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	if typ, ok := annotation.LBSvcHTTPCertificateType.StringFromService(svc); !ok || typ != string(hcloud.CertificateTypeManaged) {
 		return nil
@@ -1138,9 +1316,9 @@ type hclbServiceOptsBuilder struct {
 	err  error
 }
 
-func (b *hclbServiceOptsBuilder) extract() {
+func (b *hclbServiceOptsBuilder) extract(ctx context.Context) {
 	const op = "hcops/hclbServiceOptsBuilder.extract"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(nil)
 
 	b.listenPort = int(b.Port.Port)
 	b.destinationPort = int(b.Port.NodePort)
@@ -1217,9 +1395,6 @@ func (b *hclbServiceOptsBuilder) extract() {
 			return fmt.Errorf("%s: %w", op, err)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
 		certs, err = b.resolveCertsByNameOrID(ctx, certs)
 		if err != nil {
 			return fmt.Errorf("%s: %w", op, err)
@@ -1236,7 +1411,7 @@ func (b *hclbServiceOptsBuilder) extract() {
 			return nil
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := b.withAPICallTimeout(ctx)
 		defer cancel()
 
 		svcUID := b.Service.ObjectMeta.UID
@@ -1278,9 +1453,11 @@ func (b *hclbServiceOptsBuilder) extract() {
 	b.extractHealthCheck()
 }
 
-func (b *hclbServiceOptsBuilder) resolveCertsByNameOrID(ctx context.Context, cs []*hcloud.Certificate) ([]*hcloud.Certificate, error) {
+func (b *hclbServiceOptsBuilder) resolveCertsByNameOrID(ctx context.Context, cs []*hcloud.Certificate) (_ []*hcloud.Certificate, err error) {
 	const op = "hcops/hclbServiceOptsBuilder.resolveCertsByNameOrID"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
 
 	resolved := make([]*hcloud.Certificate, len(cs))
 	for i, c := range cs {
@@ -1289,7 +1466,9 @@ func (b *hclbServiceOptsBuilder) resolveCertsByNameOrID(ctx context.Context, cs
 			continue
 		}
 
-		c, err := b.CertOps.GetCertificateByNameOrID(ctx, c.Name)
+		callCtx, cancel := b.withAPICallTimeout(ctx)
+		c, err := b.CertOps.GetCertificateByNameOrID(callCtx, c.Name)
+		cancel()
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -1298,9 +1477,18 @@ func (b *hclbServiceOptsBuilder) resolveCertsByNameOrID(ctx context.Context, cs
 	return resolved, nil
 }
 
+// withAPICallTimeout limits a single API call to the configured timeout. The
+// deadline of ctx, e.g. of the reconcile of the Service, still applies.
+func (b *hclbServiceOptsBuilder) withAPICallTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if b.cfg.APICallTimeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, b.cfg.APICallTimeout)
+}
+
 func (b *hclbServiceOptsBuilder) extractHealthCheck() {
 	const op = "hcops/hclbServiceOptsBuilder.extractHealthCheck"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(nil)
 
 	b.do(func() error {
 		p, err := annotation.LBSvcHealthCheckProtocol.LBSvcProtocolFromService(b.Service)
@@ -1421,8 +1609,10 @@ func (b *hclbServiceOptsBuilder) extractHealthCheck() {
 	})
 }
 
-func (b *hclbServiceOptsBuilder) initialize() error {
-	b.once.Do(b.extract)
+// initialize extracts the options from the Service once. ctx is only used by
+// the first call.
+func (b *hclbServiceOptsBuilder) initialize(ctx context.Context) error {
+	b.once.Do(func() { b.extract(ctx) })
 	return b.err
 }
 
@@ -1433,11 +1623,11 @@ func (b *hclbServiceOptsBuilder) do(f func() error) {
 	b.err = f()
 }
 
-func (b *hclbServiceOptsBuilder) buildAddServiceOpts() (hcloud.LoadBalancerAddServiceOpts, error) {
+func (b *hclbServiceOptsBuilder) buildAddServiceOpts(ctx context.Context) (_ hcloud.LoadBalancerAddServiceOpts, err error) {
 	const op = "hcops/hclbServiceOptsBuilder.buildAddServiceOpts"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
 
-	if err := b.initialize(); err != nil {
+	if err := b.initialize(ctx); err != nil {
 		return hcloud.LoadBalancerAddServiceOpts{}, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -1489,11 +1679,11 @@ func (b *hclbServiceOptsBuilder) buildAddServiceOpts() (hcloud.LoadBalancerAddSe
 	return opts, nil
 }
 
-func (b *hclbServiceOptsBuilder) buildUpdateServiceOpts() (hcloud.LoadBalancerUpdateServiceOpts, error) {
+func (b *hclbServiceOptsBuilder) buildUpdateServiceOpts(ctx context.Context) (_ hcloud.LoadBalancerUpdateServiceOpts, err error) {
 	const op = "hcops/hclbServiceOptsBuilder.buildUpdateServiceOpts"
-	metrics.OperationCalled.WithLabelValues(op).Inc()
+	defer metrics.ObserveOperation(op)(&err)
 
-	if err := b.initialize(); err != nil {
+	if err := b.initialize(ctx); err != nil {
 		return hcloud.LoadBalancerUpdateServiceOpts{}, fmt.Errorf("%s: %w", op, err)
 	}
 
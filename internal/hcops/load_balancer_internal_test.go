@@ -1,9 +1,11 @@
 package hcops
 
 import (
+	"context"
 	"fmt"
 	"maps"
 	"testing"
+	"testing/synctest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -453,13 +455,50 @@ func TestHCLBServiceOptsBuilder(t *testing.T) {
 				cfg:     tt.cfg,
 			}
 			maps.Copy(builder.Service.Annotations, tt.serviceAnnotations)
-			addOpts, err := builder.buildAddServiceOpts()
+			addOpts, err := builder.buildAddServiceOpts(t.Context())
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedAddOpts, addOpts)
 
-			updateOpts, err := builder.buildUpdateServiceOpts()
+			updateOpts, err := builder.buildUpdateServiceOpts(t.Context())
 			assert.NoError(t, err)
 			assert.Equal(t, tt.expectedUpdateOpts, updateOpts)
 		})
 	}
 }
+
+func TestHCLBServiceOptsBuilder_APICallTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		certClient := &mocks.CertificateClient{}
+		certClient.Test(t)
+		certClient.
+			On("Get", mock.Anything, "my-cert").
+			Run(func(args mock.Arguments) {
+				<-args.Get(0).(context.Context).Done()
+			}).
+			Return(nil, nil, context.DeadlineExceeded)
+
+		builder := &hclbServiceOptsBuilder{
+			Port: corev1.ServicePort{Port: 443, NodePort: 8443},
+			Service: &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						string(annotation.LBSvcProtocol):         string(hcloud.LoadBalancerServiceProtocolHTTPS),
+						string(annotation.LBSvcHTTPCertificates): "my-cert",
+					},
+				},
+			},
+			CertOps: &CertificateOps{CertClient: certClient},
+			cfg:     config.LoadBalancerConfiguration{APICallTimeout: 5 * time.Second},
+		}
+
+		// The reconcile deadline is much longer than the API call timeout.
+		ctx, cancel := context.WithTimeout(t.Context(), time.Minute)
+		defer cancel()
+
+		start := time.Now()
+		_, err := builder.buildAddServiceOpts(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 5*time.Second, time.Since(start))
+		assert.NoError(t, ctx.Err())
+	})
+}
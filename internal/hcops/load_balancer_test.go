@@ -14,11 +14,16 @@ import (
 	hrobotmodels "github.com/syself/hrobot-go/models"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/hcops"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/lbprofile"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
 
@@ -411,6 +416,24 @@ func TestLoadBalancerOps_Create(t *testing.T) {
 			},
 			lb: &hcloud.LoadBalancer{ID: 3},
 		},
+		{
+			name: "set Load Balancer labels",
+			serviceAnnotations: map[string]string{
+				string(annotation.LBLabels):   "team=a,env=prod",
+				string(annotation.LBLocation): "nbg1",
+			},
+			createOpts: hcloud.LoadBalancerCreateOpts{
+				Name:             "another-lb",
+				LoadBalancerType: &hcloud.LoadBalancerType{ID: 1, Name: "lb11"},
+				Location:         &hcloud.Location{Name: "nbg1"},
+				Labels: map[string]string{
+					hcops.LabelServiceUID: "another-lb-uid",
+					"team":                "a",
+					"env":                 "prod",
+				},
+			},
+			lb: &hcloud.LoadBalancer{ID: 3},
+		},
 		{
 			name: "set Load Balancer algorithm type",
 			serviceAnnotations: map[string]string{
@@ -501,7 +524,7 @@ func TestLoadBalancerOps_Create(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			fx := hcops.NewLoadBalancerOpsFixture(t)
 
-			fx.LBOps.Cfg = tt.cfg
+			fx.LBOps.Cfg = config.NewStore(tt.cfg)
 
 			if tt.mock == nil {
 				tt.mock = func(_ *testing.T, tt *testCase, fx *hcops.LoadBalancerOpsFixture) {
@@ -594,7 +617,7 @@ func (tt *LBReconcilementTestCase) run(t *testing.T) {
 	t.Helper()
 
 	tt.fx = hcops.NewLoadBalancerOpsFixture(t)
-	tt.fx.LBOps.Cfg = tt.cfg
+	tt.fx.LBOps.Cfg = config.NewStore(tt.cfg)
 
 	if tt.service == nil {
 		tt.service = &corev1.Service{
@@ -685,6 +708,35 @@ func TestLoadBalancerOps_ReconcileHCLB(t *testing.T) {
 				assert.False(t, changed)
 			},
 		},
+		{
+			name: "warn about unsupported annotations",
+			serviceAnnotations: map[string]string{
+				"load-balancer.hetzner.cloud/healthcheck-port": "8080",
+				string(annotation.LBPublicIPv4):                "1.2.3.4",
+			},
+			initialLB: &hcloud.LoadBalancer{
+				ID: 3,
+				Algorithm: hcloud.LoadBalancerAlgorithm{
+					Type: hcloud.LoadBalancerAlgorithmTypeRoundRobin,
+				},
+				PublicNet: hcloud.LoadBalancerPublicNet{
+					Enabled: true,
+				},
+			},
+			perform: func(t *testing.T, tt *LBReconcilementTestCase) {
+				recorder := record.NewFakeRecorder(10)
+				tt.fx.LBOps.Recorder = recorder
+
+				changed, err := tt.fx.LBOps.ReconcileHCLB(tt.fx.Ctx, tt.initialLB, tt.service)
+				assert.NoError(t, err)
+				assert.False(t, changed)
+				assert.Equal(t,
+					`Warning UnsupportedAnnotations Ignoring unsupported annotations: `+
+						`annotation "load-balancer.hetzner.cloud/ipv4" is read-only; `+
+						`unknown annotation "load-balancer.hetzner.cloud/healthcheck-port", did you mean "load-balancer.hetzner.cloud/health-check-port"?`,
+					<-recorder.Events)
+			},
+		},
 		{
 			name: "update type",
 			serviceAnnotations: map[string]string{
@@ -1288,6 +1340,65 @@ func TestLoadBalancerOps_ReconcileHCLB(t *testing.T) {
 				tt.fx.LBClient.AssertNumberOfCalls(t, "Update", 1)
 			},
 		},
+		{
+			name:       "add labels from annotation",
+			serviceUID: "13",
+			serviceAnnotations: map[string]string{
+				string(annotation.LBLabels): "team=a",
+			},
+			initialLB: &hcloud.LoadBalancer{
+				ID: 13,
+				Labels: map[string]string{
+					hcops.LabelServiceUID: "13",
+					"some-label":          "some-value",
+				},
+				PublicNet: hcloud.LoadBalancerPublicNet{
+					Enabled: true,
+				},
+			},
+			mock: func(_ *testing.T, tt *LBReconcilementTestCase) {
+				labels := map[string]string{
+					hcops.LabelServiceUID: tt.serviceUID,
+					"some-label":          "some-value",
+					"team":                "a",
+				}
+				updated := *tt.initialLB
+				updated.Labels = labels
+				opts := hcloud.LoadBalancerUpdateOpts{Labels: labels}
+				tt.fx.LBClient.
+					On("Update", tt.fx.Ctx, tt.initialLB, opts).
+					Return(&updated, nil, nil)
+			},
+			perform: func(t *testing.T, tt *LBReconcilementTestCase) {
+				changed, err := tt.fx.LBOps.ReconcileHCLB(tt.fx.Ctx, tt.initialLB, tt.service)
+				assert.NoError(t, err)
+				assert.True(t, changed)
+				assert.Equal(t, "a", tt.initialLB.Labels["team"])
+				assert.Equal(t, "some-value", tt.initialLB.Labels["some-label"])
+			},
+		},
+		{
+			name:       "don't update unchanged labels from annotation",
+			serviceUID: "14",
+			serviceAnnotations: map[string]string{
+				string(annotation.LBLabels): "team=a",
+			},
+			initialLB: &hcloud.LoadBalancer{
+				ID: 14,
+				Labels: map[string]string{
+					hcops.LabelServiceUID: "14",
+					"team":                "a",
+				},
+				PublicNet: hcloud.LoadBalancerPublicNet{
+					Enabled: true,
+				},
+			},
+			perform: func(t *testing.T, tt *LBReconcilementTestCase) {
+				changed, err := tt.fx.LBOps.ReconcileHCLB(tt.fx.Ctx, tt.initialLB, tt.service)
+				assert.NoError(t, err)
+				assert.False(t, changed)
+			},
+		},
 		{
 			name:       "rename load balancer",
 			serviceUID: "11",
@@ -1503,6 +1614,33 @@ func TestLoadBalancerOps_ReconcileHCLBTargets(t *testing.T) {
 				assert.False(t, changed)
 			},
 		},
+		{
+			name: "skip nodes of other projects",
+			k8sNodes: []*corev1.Node{
+				{Spec: corev1.NodeSpec{ProviderID: "hcloud://1"}},
+				{Spec: corev1.NodeSpec{ProviderID: "hcloud://team-a/2"}},
+			},
+			initialLB: &hcloud.LoadBalancer{
+				ID: 5,
+				Targets: []hcloud.LoadBalancerTarget{
+					{
+						Type:   hcloud.LoadBalancerTargetTypeServer,
+						Server: &hcloud.LoadBalancerTargetServer{Server: &hcloud.Server{ID: 1}},
+					},
+				},
+				LoadBalancerType: &hcloud.LoadBalancerType{
+					MaxTargets: 2,
+				},
+			},
+			mock: func(_ *testing.T, _ *LBReconcilementTestCase) {
+				// Nothing to mock because no action will be taken besides emitting an event
+			},
+			perform: func(t *testing.T, tt *LBReconcilementTestCase) {
+				changed, err := tt.fx.LBOps.ReconcileHCLBTargets(tt.fx.Ctx, tt.initialLB, tt.service, tt.k8sNodes)
+				assert.NoError(t, err)
+				assert.False(t, changed)
+			},
+		},
 		{
 			name: "enable use of private network via default",
 			cfg: config.HCCMConfiguration{
@@ -1688,6 +1826,107 @@ func TestLoadBalancerOps_ReconcileHCLBTargets(t *testing.T) {
 				assert.True(t, changed)
 			},
 		},
+		{
+			name: "add foreign nodes as IP targets",
+			k8sNodes: []*corev1.Node{
+				{Spec: corev1.NodeSpec{ProviderID: "hcloud://1"}},
+				{
+					Spec:       corev1.NodeSpec{ProviderID: "aws:///eu-central-1a/i-123"},
+					ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+					Status: corev1.NodeStatus{
+						Addresses: []corev1.NodeAddress{
+							{Type: corev1.NodeInternalIP, Address: "10.0.2.10"},
+						},
+					},
+				},
+				{
+					Spec:       corev1.NodeSpec{ProviderID: "kind://docker/kind/onprem"},
+					ObjectMeta: metav1.ObjectMeta{Name: "onprem"},
+					Status: corev1.NodeStatus{
+						Addresses: []corev1.NodeAddress{
+							{Type: corev1.NodeInternalIP, Address: "10.0.2.11"},
+						},
+					},
+				},
+				{
+					Spec:       corev1.NodeSpec{ProviderID: "kind://docker/kind/no-ip"},
+					ObjectMeta: metav1.ObjectMeta{Name: "no-ip"},
+				},
+				{
+					// Not initialized yet
+					ObjectMeta: metav1.ObjectMeta{Name: "uninitialized"},
+					Status: corev1.NodeStatus{
+						Addresses: []corev1.NodeAddress{
+							{Type: corev1.NodeInternalIP, Address: "10.0.2.12"},
+						},
+					},
+				},
+			},
+			initialLB: &hcloud.LoadBalancer{
+				ID: 1,
+				Targets: []hcloud.LoadBalancerTarget{
+					{
+						Type:   hcloud.LoadBalancerTargetTypeServer,
+						Server: &hcloud.LoadBalancerTargetServer{Server: &hcloud.Server{ID: 1}},
+					},
+					{
+						Type: hcloud.LoadBalancerTargetTypeIP,
+						IP:   &hcloud.LoadBalancerTargetIP{IP: "10.0.2.10"},
+					},
+					{
+						Type: hcloud.LoadBalancerTargetTypeIP,
+						IP:   &hcloud.LoadBalancerTargetIP{IP: "10.0.2.99"},
+					},
+				},
+				LoadBalancerType: &hcloud.LoadBalancerType{
+					MaxTargets: 25,
+				},
+			},
+			cfg: config.HCCMConfiguration{
+				LoadBalancer: config.LoadBalancerConfiguration{ForeignNodeIPTargetsEnabled: true},
+			},
+			mock: func(_ *testing.T, tt *LBReconcilementTestCase) {
+				action := tt.fx.MockRemoveIPTarget(tt.initialLB, net.ParseIP("10.0.2.99"), nil)
+				tt.fx.ActionClient.On("WaitFor", tt.fx.Ctx, action).Return(nil)
+
+				opts := hcloud.LoadBalancerAddIPTargetOpts{IP: net.ParseIP("10.0.2.11")}
+				action = tt.fx.MockAddIPTarget(tt.initialLB, opts, nil)
+				tt.fx.ActionClient.On("WaitFor", tt.fx.Ctx, action).Return(nil)
+			},
+			perform: func(t *testing.T, tt *LBReconcilementTestCase) {
+				changed, err := tt.fx.LBOps.ReconcileHCLBTargets(tt.fx.Ctx, tt.initialLB, tt.service, tt.k8sNodes)
+				assert.NoError(t, err)
+				assert.True(t, changed)
+			},
+		},
+		{
+			name: "foreign nodes are skipped if IP targets are disabled",
+			k8sNodes: []*corev1.Node{
+				{
+					Spec:       corev1.NodeSpec{ProviderID: "aws:///eu-central-1a/i-123"},
+					ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+					Status: corev1.NodeStatus{
+						Addresses: []corev1.NodeAddress{
+							{Type: corev1.NodeInternalIP, Address: "10.0.2.10"},
+						},
+					},
+				},
+			},
+			initialLB: &hcloud.LoadBalancer{
+				ID: 1,
+				LoadBalancerType: &hcloud.LoadBalancerType{
+					MaxTargets: 25,
+				},
+			},
+			mock: func(_ *testing.T, _ *LBReconcilementTestCase) {
+				// Nothing to mock because no action will be taken besides emitting an event
+			},
+			perform: func(t *testing.T, tt *LBReconcilementTestCase) {
+				changed, err := tt.fx.LBOps.ReconcileHCLBTargets(tt.fx.Ctx, tt.initialLB, tt.service, tt.k8sNodes)
+				assert.NoError(t, err)
+				assert.False(t, changed)
+			},
+		},
 		{
 			name: "robot enabled without credentials skips nodes without InternalIP",
 			k8sNodes: []*corev1.Node{
@@ -1747,6 +1986,64 @@ func TestLoadBalancerOps_ReconcileHCLBServices(t *testing.T) {
 				assert.NoError(t, err)
 			},
 		},
+		{
+			name: "add services with port settings of profile",
+			servicePorts: []corev1.ServicePort{
+				{Port: 80, NodePort: 8080},
+				{Port: 443, NodePort: 8443},
+			},
+			serviceAnnotations: map[string]string{
+				string(annotation.LBProfile):          "proxy",
+				string(annotation.LBSvcProxyProtocol): "false",
+			},
+			initialLB: &hcloud.LoadBalancer{
+				ID: 4,
+				LoadBalancerType: &hcloud.LoadBalancerType{
+					MaxTargets: 25,
+				},
+			},
+			mock: func(t *testing.T, tt *LBReconcilementTestCase) {
+				tt.fx.LBOps.Profiles = newProfileLister(t, &lbprofile.HCloudLoadBalancerProfile{
+					ObjectMeta: metav1.ObjectMeta{Name: "proxy"},
+					Spec: lbprofile.Spec{
+						Ports: []lbprofile.PortConfig{
+							{Port: 443, ServiceConfig: lbprofile.ServiceConfig{ProxyProtocol: hcloud.Ptr(true)}},
+						},
+					},
+				})
+
+				opts := hcloud.LoadBalancerAddServiceOpts{
+					Protocol:        hcloud.LoadBalancerServiceProtocolTCP,
+					ListenPort:      new(80),
+					DestinationPort: new(8080),
+					Proxyprotocol:   hcloud.Ptr(false),
+					HealthCheck: &hcloud.LoadBalancerAddServiceOptsHealthCheck{
+						Protocol: hcloud.LoadBalancerServiceProtocolTCP,
+						Port:     new(8080),
+					},
+				}
+				action := tt.fx.MockAddService(opts, tt.initialLB, nil)
+				tt.fx.ActionClient.On("WaitFor", tt.fx.Ctx, action).Return(nil)
+
+				opts = hcloud.LoadBalancerAddServiceOpts{
+					Protocol:        hcloud.LoadBalancerServiceProtocolTCP,
+					ListenPort:      new(443),
+					DestinationPort: new(8443),
+					Proxyprotocol:   hcloud.Ptr(true),
+					HealthCheck: &hcloud.LoadBalancerAddServiceOptsHealthCheck{
+						Protocol: hcloud.LoadBalancerServiceProtocolTCP,
+						Port:     new(8443),
+					},
+				}
+				action = tt.fx.MockAddService(opts, tt.initialLB, nil)
+				tt.fx.ActionClient.On("WaitFor", tt.fx.Ctx, action).Return(nil)
+			},
+			perform: func(t *testing.T, tt *LBReconcilementTestCase) {
+				changed, err := tt.fx.LBOps.ReconcileHCLBServices(tt.fx.Ctx, tt.initialLB, tt.service)
+				assert.NoError(t, err)
+				assert.True(t, changed)
+			},
+		},
 		{
 			name: "add services to hc Load Balancer",
 			servicePorts: []corev1.ServicePort{
@@ -2061,3 +2358,19 @@ func TestLoadBalancerOps_ReconcileHCLBServices(t *testing.T) {
 		t.Run(tt.name, tt.run)
 	}
 }
+
+func newProfileLister(t *testing.T, profiles ...*lbprofile.HCloudLoadBalancerProfile) *lbprofile.Lister {
+	t.Helper()
+
+	indexer := toolscache.NewIndexer(toolscache.MetaNamespaceKeyFunc, toolscache.Indexers{})
+	for _, p := range profiles {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := indexer.Add(&unstructured.Unstructured{Object: obj}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return lbprofile.NewLister(toolscache.NewGenericLister(indexer, lbprofile.GroupVersionResource.GroupResource()))
+}
@@ -0,0 +1,84 @@
+package hcops
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// RetryPolicy configures how [Retry] retries a mutation, which failed because
+// the resource was locked or in conflict with a parallel change.
+type RetryPolicy struct {
+	// Budget is the maximum time waited between all attempts. If zero, the
+	// mutation is not retried.
+	Budget time.Duration
+	// InitialDelay is the delay before the first retry. It is doubled for every
+	// further retry.
+	InitialDelay time.Duration
+	// MaxDelay limits the delay between two attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used for all mutations of the Hetzner
+// Cloud API with the given budget.
+func DefaultRetryPolicy(budget time.Duration) RetryPolicy {
+	return RetryPolicy{
+		Budget:       budget,
+		InitialDelay: time.Second,
+		MaxDelay:     8 * time.Second,
+	}
+}
+
+// Retry calls fn until it succeeds, returns an error which is not retryable,
+// the budget of policy is used up or ctx is done. fn should send the mutation
+// and wait for its action, as actions also fail if the resource is locked.
+//
+// The last error of fn is returned unwrapped.
+func Retry(ctx context.Context, op string, policy RetryPolicy, fn func() error) error {
+	delay := policy.InitialDelay
+	var waited time.Duration
+
+	for {
+		err := fn()
+		if err == nil || !IsRetryable(err) {
+			return err
+		}
+
+		if delay <= 0 || waited+delay > policy.Budget {
+			return err
+		}
+
+		metrics.OperationRetries.WithLabelValues(op, metrics.ErrorClass(err)).Inc()
+		klog.InfoS("retry due to conflict or lock", "op", op, "delay", delay, "err", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		waited += delay
+		delay = min(2*delay, policy.MaxDelay)
+	}
+}
+
+// IsRetryable reports whether err was caused by a locked resource or a
+// conflicting change, which usually resolves after a short time.
+func IsRetryable(err error) bool {
+	if hcloud.IsError(err, hcloud.ErrorCodeConflict, hcloud.ErrorCodeLocked) {
+		return true
+	}
+
+	var actionErr hcloud.ActionError
+	if errors.As(err, &actionErr) {
+		return actionErr.Code == string(hcloud.ErrorCodeConflict) || actionErr.Code == string(hcloud.ErrorCodeLocked)
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package hcops_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/hcops"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+func TestRetry(t *testing.T) {
+	locked := hcloud.Error{Code: hcloud.ErrorCodeLocked}
+	policy := hcops.RetryPolicy{Budget: 10 * time.Second, InitialDelay: time.Second, MaxDelay: 4 * time.Second}
+
+	tests := []struct {
+		name      string
+		policy    hcops.RetryPolicy
+		errs      []error
+		wantErr   error
+		wantCalls int
+		wantTime  time.Duration
+	}{
+		{
+			name:      "success",
+			policy:    policy,
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "retry until success",
+			policy:    policy,
+			errs:      []error{locked, hcloud.ActionError{Code: string(hcloud.ErrorCodeConflict)}, nil},
+			wantCalls: 3,
+			wantTime:  3 * time.Second,
+		},
+		{
+			name:      "not retryable",
+			policy:    policy,
+			errs:      []error{hcloud.Error{Code: hcloud.ErrorCodeInvalidInput}},
+			wantErr:   hcloud.Error{Code: hcloud.ErrorCodeInvalidInput},
+			wantCalls: 1,
+		},
+		{
+			// Waits 1s, 2s, 4s, the next 4s would exceed the budget.
+			name:      "budget used up",
+			policy:    policy,
+			errs:      []error{locked, locked, locked, locked, locked},
+			wantErr:   locked,
+			wantCalls: 4,
+			wantTime:  7 * time.Second,
+		},
+		{
+			name:      "no budget",
+			policy:    hcops.RetryPolicy{},
+			errs:      []error{locked},
+			wantErr:   locked,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				start := time.Now()
+				calls := 0
+				err := hcops.Retry(context.Background(), "test/"+tt.name, tt.policy, func() error {
+					err := tt.errs[calls]
+					calls++
+					return err
+				})
+
+				if tt.wantErr != nil {
+					require.ErrorIs(t, err, tt.wantErr)
+				} else {
+					require.NoError(t, err)
+				}
+				assert.Equal(t, tt.wantCalls, calls)
+				assert.Equal(t, tt.wantTime, time.Since(start))
+				assert.Equal(t, float64(tt.wantCalls-1), testutil.ToFloat64(metrics.OperationRetries.WithLabelValues("test/"+tt.name, metrics.ErrorClassConflict)))
+			})
+		})
+	}
+}
+
+func TestRetry_ContextDone(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		defer cancel()
+
+		calls := 0
+		err := hcops.Retry(ctx, "test/context", hcops.DefaultRetryPolicy(time.Minute), func() error {
+			calls++
+			return hcloud.Error{Code: hcloud.ErrorCodeConflict}
+		})
+
+		assert.True(t, hcloud.IsError(err, hcloud.ErrorCodeConflict))
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, hcops.IsRetryable(hcloud.Error{Code: hcloud.ErrorCodeLocked}))
+	assert.True(t, hcops.IsRetryable(hcloud.ActionError{Code: string(hcloud.ErrorCodeConflict)}))
+	assert.False(t, hcops.IsRetryable(hcloud.ActionError{Code: "action_failed"}))
+	assert.False(t, hcops.IsRetryable(hcloud.Error{Code: hcloud.ErrorCodeNotFound}))
+	assert.False(t, hcops.IsRetryable(errors.New("something")))
+}
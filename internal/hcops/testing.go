@@ -13,6 +13,7 @@ import (
 	"k8s.io/client-go/tools/record"
 
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/cache"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/config"
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/mocks"
 	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
@@ -66,6 +67,8 @@ func NewLoadBalancerOpsFixture(t *testing.T) *LoadBalancerOpsFixture {
 		NetworkClient: fx.NetworkClient,
 		RobotClient:   fx.RobotClient,
 		LBTypeCache:   newLBTypeCacheFixture(t),
+		Retry:         RetryPolicy{Budget: time.Second, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		Cfg:           config.NewStore(config.HCCMConfiguration{}),
 		Recorder:      &record.FakeRecorder{},
 	}
 
@@ -0,0 +1,170 @@
+// Package health provides the /healthz and /readyz endpoints of the
+// hcloud-cloud-controller-manager.
+//
+// The checks run periodically in the background, so the probes do not cause
+// additional requests to the APIs, which are subject to rate limits.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// Status is the status of a check.
+type Status string
+
+const (
+	StatusOK Status = "ok"
+	// StatusDegraded signals a problem, which resolves on its own and does not
+	// affect the readiness, e.g. an exceeded rate limit.
+	StatusDegraded Status = "degraded"
+	StatusFailed   Status = "failed"
+)
+
+// Result is the result of a check.
+type Result struct {
+	Status    Status    `json:"status"`
+	Message   string    `json:"message,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// CheckFunc checks a subsystem. The CheckedAt field of the result is set by
+// the [Checker].
+type CheckFunc func(ctx context.Context) Result
+
+// Checker runs the checks of all subsystems periodically and serves their last
+// results.
+type Checker struct {
+	interval time.Duration
+	timeout  time.Duration
+	names    []string
+	checks   map[string]CheckFunc
+
+	mu      sync.RWMutex
+	results map[string]Result
+	started time.Time
+	lastRun time.Time
+}
+
+// NewChecker returns a [Checker], which runs the checks every interval.
+func NewChecker(interval time.Duration) *Checker {
+	return &Checker{
+		interval: interval,
+		timeout:  interval / 2,
+		checks:   make(map[string]CheckFunc),
+		results:  make(map[string]Result),
+		started:  time.Now(),
+	}
+}
+
+// Add adds a check for the subsystem name. All checks must be added before
+// [Checker.Run] is called.
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.names = append(c.names, name)
+	c.checks[name] = check
+}
+
+// Run runs the checks until stop is closed.
+func (c *Checker) Run(stop <-chan struct{}) {
+	wait.Until(c.runChecks, c.interval, stop)
+}
+
+func (c *Checker) runChecks() {
+	results := make(map[string]Result, len(c.names))
+	for _, name := range c.names {
+		ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+		result := c.checks[name](ctx)
+		cancel()
+
+		result.CheckedAt = time.Now()
+		if result.Status != StatusOK {
+			klog.InfoS("health check did not succeed", "check", name, "status", result.Status, "message", result.Message)
+		}
+		results[name] = result
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = results
+	c.lastRun = time.Now()
+}
+
+type response struct {
+	Status         Status               `json:"status"`
+	Message        string               `json:"message,omitempty"`
+	Checks         map[string]Result    `json:"checks,omitempty"`
+	LastReconciles map[string]time.Time `json:"lastReconciles,omitempty"`
+}
+
+// Handlers returns the handlers for the /healthz and /readyz endpoints.
+//
+// /healthz only fails if the checks stopped running, as a restart does not
+// resolve problems with the APIs. /readyz fails if any check failed.
+func (c *Checker) Handlers() map[string]http.Handler {
+	return map[string]http.Handler{
+		"/healthz": http.HandlerFunc(c.serveHealthz),
+		"/readyz":  http.HandlerFunc(c.serveReadyz),
+	}
+}
+
+func (c *Checker) serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	resp := response{Status: StatusOK}
+
+	// Allow some slack for slow checks, before the checks are considered stuck.
+	last := c.lastRun
+	if last.IsZero() {
+		last = c.started
+	}
+	if time.Since(last) > 3*c.interval {
+		resp.Status = StatusFailed
+		resp.Message = fmt.Sprintf("the checks did not complete since %s", last.Format(time.RFC3339))
+	}
+
+	writeResponse(w, resp)
+}
+
+func (c *Checker) serveReadyz(w http.ResponseWriter, _ *http.Request) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	resp := response{
+		Status:         StatusOK,
+		Checks:         c.results,
+		LastReconciles: LastReconciles(),
+	}
+
+	if c.lastRun.IsZero() {
+		resp.Status = StatusFailed
+		resp.Message = "the checks did not run yet"
+	}
+	for _, result := range c.results {
+		switch {
+		case result.Status == StatusFailed:
+			resp.Status = StatusFailed
+		case result.Status == StatusDegraded && resp.Status == StatusOK:
+			resp.Status = StatusDegraded
+		}
+	}
+
+	writeResponse(w, resp)
+}
+
+func writeResponse(w http.ResponseWriter, resp response) {
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Status == StatusFailed {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		klog.ErrorS(err, "failed to write health response")
+	}
+}
@@ -0,0 +1,88 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, c *Checker, path string) (int, response) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c.Handlers()[path].ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var resp response
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	return rec.Code, resp
+}
+
+func TestChecker(t *testing.T) {
+	hcloudResult := Result{Status: StatusOK}
+	robotResult := Result{Status: StatusOK}
+
+	c := NewChecker(time.Minute)
+	c.Add("hcloud", func(context.Context) Result { return hcloudResult })
+	c.Add("robot", func(context.Context) Result { return robotResult })
+
+	code, resp := get(t, c, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "the checks did not run yet", resp.Message)
+
+	code, _ = get(t, c, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+
+	c.runChecks()
+	code, resp = get(t, c, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, resp.Status)
+	assert.Len(t, resp.Checks, 2)
+
+	robotResult = Result{Status: StatusDegraded, Message: "the rate limit is exceeded"}
+	c.runChecks()
+	code, resp = get(t, c, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusDegraded, resp.Status)
+	assert.Equal(t, "the rate limit is exceeded", resp.Checks["robot"].Message)
+
+	hcloudResult = Result{Status: StatusFailed, Message: "the token is invalid"}
+	c.runChecks()
+	code, resp = get(t, c, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFailed, resp.Status)
+
+	// An API problem does not affect the liveness.
+	code, _ = get(t, c, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+
+	// The checks got stuck.
+	c.lastRun = time.Now().Add(-time.Hour)
+	code, resp = get(t, c, "/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, resp.Message, "the checks did not complete since")
+}
+
+func TestObserveReconcile(t *testing.T) {
+	failed := func() (err error) {
+		defer ObserveReconcile("test-failed", &err)
+		return errors.New("failed")
+	}
+	succeeded := func() (err error) {
+		defer ObserveReconcile("test-succeeded", &err)
+		return nil
+	}
+
+	_ = failed()
+	_ = succeeded()
+
+	reconciles := LastReconciles()
+	assert.NotContains(t, reconciles, "test-failed")
+	assert.WithinDuration(t, time.Now(), reconciles["test-succeeded"], time.Second)
+}
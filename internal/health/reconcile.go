@@ -0,0 +1,42 @@
+package health
+
+import (
+	"maps"
+	"sync"
+	"time"
+)
+
+// Subsystems, whose reconciles are recorded with [ObserveReconcile].
+const (
+	Instances     = "instances"
+	LoadBalancers = "loadBalancers"
+	Routes        = "routes"
+)
+
+var (
+	reconcilesMu sync.Mutex
+	reconciles   = map[string]time.Time{}
+)
+
+// ObserveReconcile records the time of a successful reconcile of subsystem, if
+// err is nil. It is meant to be deferred with a pointer to the error result of
+// the reconcile:
+//
+//	defer health.ObserveReconcile(health.Routes, &err)
+func ObserveReconcile(subsystem string, err *error) {
+	if err != nil && *err != nil {
+		return
+	}
+
+	reconcilesMu.Lock()
+	defer reconcilesMu.Unlock()
+	reconciles[subsystem] = time.Now()
+}
+
+// LastReconciles returns the time of the last successful reconcile of each
+// subsystem.
+func LastReconciles() map[string]time.Time {
+	reconcilesMu.Lock()
+	defer reconcilesMu.Unlock()
+	return maps.Clone(reconciles)
+}
@@ -0,0 +1,121 @@
+package lbprofile
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+)
+
+// Annotations returns the Service annotations equivalent to the settings of
+// the profile, which apply to all ports.
+func (p *HCloudLoadBalancerProfile) Annotations() map[string]string {
+	a := make(map[string]string)
+	s := p.Spec
+
+	setString(a, annotation.LBType, s.Type)
+	setString(a, annotation.LBLocation, s.Location)
+	setString(a, annotation.LBNetworkZone, s.NetworkZone)
+	setString(a, annotation.LBAlgorithmType, s.Algorithm)
+	setBool(a, annotation.LBIPv6Disabled, s.IPv6Disabled)
+
+	if len(s.Labels) > 0 {
+		labels := make([]string, 0, len(s.Labels))
+		for k, v := range s.Labels {
+			labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+		}
+		slices.Sort(labels)
+		a[string(annotation.LBLabels)] = strings.Join(labels, ",")
+	}
+
+	if n := s.Network; n != nil {
+		setBool(a, annotation.LBUsePrivateIP, n.UsePrivateIP)
+		setBool(a, annotation.LBDisablePublicNetwork, n.DisablePublicNetwork)
+		setBool(a, annotation.LBDisablePrivateIngress, n.DisablePrivateIngress)
+		setString(a, annotation.PrivateSubnetIPRange, n.PrivateSubnetIPRange)
+	}
+
+	if c := s.Certificates; c != nil {
+		setString(a, annotation.LBSvcHTTPCertificateType, c.Type)
+		setStrings(a, annotation.LBSvcHTTPCertificates, c.Uploaded)
+		setString(a, annotation.LBSvcHTTPManagedCertificateName, c.ManagedName)
+		setStrings(a, annotation.LBSvcHTTPManagedCertificateDomains, c.ManagedDomains)
+	}
+
+	s.Service.addAnnotations(a)
+
+	return a
+}
+
+// PortAnnotations returns the Service annotations equivalent to the settings
+// of the profile, which only apply to the given port. It returns nil if the
+// profile does not configure the port.
+func (p *HCloudLoadBalancerProfile) PortAnnotations(port int32) map[string]string {
+	i := slices.IndexFunc(p.Spec.Ports, func(c PortConfig) bool { return c.Port == port })
+	if i < 0 {
+		return nil
+	}
+
+	a := make(map[string]string)
+	p.Spec.Ports[i].ServiceConfig.addAnnotations(a)
+	return a
+}
+
+func (c *ServiceConfig) addAnnotations(a map[string]string) {
+	if c == nil {
+		return
+	}
+
+	setString(a, annotation.LBSvcProtocol, c.Protocol)
+	setBool(a, annotation.LBSvcProxyProtocol, c.ProxyProtocol)
+
+	if h := c.HTTP; h != nil {
+		setString(a, annotation.LBSvcHTTPCookieName, h.CookieName)
+		setString(a, annotation.LBSvcHTTPCookieLifetime, h.CookieLifetime)
+		setString(a, annotation.LBSvcHTTPTimeoutIdle, h.TimeoutIdle)
+		setBool(a, annotation.LBSvcRedirectHTTP, h.RedirectHTTP)
+		setBool(a, annotation.LBSvcHTTPStickySessions, h.StickySessions)
+		setStrings(a, annotation.LBSvcHTTPCertificates, h.Certificates)
+	}
+
+	if hc := c.HealthCheck; hc != nil {
+		setString(a, annotation.LBSvcHealthCheckProtocol, hc.Protocol)
+		setInt(a, annotation.LBSvcHealthCheckPort, hc.Port)
+		setString(a, annotation.LBSvcHealthCheckInterval, hc.Interval)
+		setString(a, annotation.LBSvcHealthCheckTimeout, hc.Timeout)
+		setInt(a, annotation.LBSvcHealthCheckRetries, hc.Retries)
+
+		if h := hc.HTTP; h != nil {
+			setString(a, annotation.LBSvcHealthCheckHTTPDomain, h.Domain)
+			setString(a, annotation.LBSvcHealthCheckHTTPPath, h.Path)
+			setBool(a, annotation.LBSvcHealthCheckHTTPValidateCertificate, h.ValidateCertificate)
+			setStrings(a, annotation.LBSvcHealthCheckHTTPStatusCodes, h.StatusCodes)
+		}
+	}
+}
+
+func setString(a map[string]string, name annotation.Name, v string) {
+	if v != "" {
+		a[string(name)] = v
+	}
+}
+
+func setStrings(a map[string]string, name annotation.Name, v []string) {
+	if len(v) > 0 {
+		a[string(name)] = strings.Join(v, ",")
+	}
+}
+
+func setBool(a map[string]string, name annotation.Name, v *bool) {
+	if v != nil {
+		a[string(name)] = strconv.FormatBool(*v)
+	}
+}
+
+func setInt(a map[string]string, name annotation.Name, v *int32) {
+	if v != nil {
+		a[string(name)] = strconv.FormatInt(int64(*v), 10)
+	}
+}
@@ -0,0 +1,72 @@
+package lbprofile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+func TestHCloudLoadBalancerProfile_Annotations(t *testing.T) {
+	p := &HCloudLoadBalancerProfile{
+		Spec: Spec{
+			Type:      "lb21",
+			Location:  "fsn1",
+			Algorithm: "least_connections",
+			Labels:    map[string]string{"team": "a", "env": "prod"},
+			Network: &Network{
+				UsePrivateIP:         hcloud.Ptr(true),
+				DisablePublicNetwork: hcloud.Ptr(false),
+			},
+			Certificates: &Certificates{
+				Type:     "uploaded",
+				Uploaded: []string{"cert-1", "2"},
+			},
+			Service: &ServiceConfig{
+				Protocol: "http",
+				HealthCheck: &HealthCheck{
+					Interval: "5s",
+					Retries:  hcloud.Ptr[int32](3),
+					HTTP: &HealthCheckHTTP{
+						StatusCodes: []string{"2??", "3??"},
+					},
+				},
+			},
+			Ports: []PortConfig{
+				{
+					Port: 443,
+					ServiceConfig: ServiceConfig{
+						Protocol: "https",
+						HTTP: &HTTP{
+							RedirectHTTP: hcloud.Ptr(true),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		string(annotation.LBType):                          "lb21",
+		string(annotation.LBLocation):                      "fsn1",
+		string(annotation.LBAlgorithmType):                 "least_connections",
+		string(annotation.LBLabels):                        "env=prod,team=a",
+		string(annotation.LBUsePrivateIP):                  "true",
+		string(annotation.LBDisablePublicNetwork):          "false",
+		string(annotation.LBSvcHTTPCertificateType):        "uploaded",
+		string(annotation.LBSvcHTTPCertificates):           "cert-1,2",
+		string(annotation.LBSvcProtocol):                   "http",
+		string(annotation.LBSvcHealthCheckInterval):        "5s",
+		string(annotation.LBSvcHealthCheckRetries):         "3",
+		string(annotation.LBSvcHealthCheckHTTPStatusCodes): "2??,3??",
+	}, p.Annotations())
+
+	assert.Equal(t, map[string]string{
+		string(annotation.LBSvcProtocol):     "https",
+		string(annotation.LBSvcRedirectHTTP): "true",
+	}, p.PortAnnotations(443))
+
+	assert.Nil(t, p.PortAnnotations(80))
+}
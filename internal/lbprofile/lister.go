@@ -0,0 +1,114 @@
+package lbprofile
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+)
+
+// Lister retrieves HCloudLoadBalancerProfiles from the cache of a dynamic
+// informer.
+type Lister struct {
+	lister toolscache.GenericLister
+}
+
+// NewLister creates a new [Lister] using the cache of lister.
+func NewLister(lister toolscache.GenericLister) *Lister {
+	return &Lister{lister: lister}
+}
+
+// Get returns the profile with the given name.
+func (l *Lister) Get(name string) (_ *HCloudLoadBalancerProfile, err error) {
+	const op = "lbprofile/Lister.Get"
+	defer metrics.ObserveOperation(op)(&err)
+
+	obj, err := l.lister.Get(name)
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%s: %s %q not found", op, Kind, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	p, err := fromObject(obj)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	return p, nil
+}
+
+// List returns all profiles.
+func (l *Lister) List() (_ []*HCloudLoadBalancerProfile, err error) {
+	const op = "lbprofile/Lister.List"
+	defer metrics.ObserveOperation(op)(&err)
+
+	objs, err := l.lister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	profiles := make([]*HCloudLoadBalancerProfile, 0, len(objs))
+	for _, obj := range objs {
+		p, err := fromObject(obj)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+// ServiceWithDefaults returns svc with the settings of the profile referenced
+// by [annotation.LBProfile] applied. The annotations of svc take precedence
+// over the profile. If svc does not reference a profile, svc is returned
+// unchanged.
+func (l *Lister) ServiceWithDefaults(svc *corev1.Service) (*corev1.Service, error) {
+	name, ok := annotation.LBProfile.StringFromService(svc)
+	if !ok {
+		return svc, nil
+	}
+
+	p, err := l.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return annotation.ServiceWithProfileDefaults(svc, p.Annotations()), nil
+}
+
+// ServiceForPort returns svc with the settings for port of the profile
+// referenced by [annotation.LBProfile] applied. As they are the most
+// specific settings, they take precedence over the annotations of svc. If svc
+// does not reference a profile, svc is returned unchanged.
+func (l *Lister) ServiceForPort(svc *corev1.Service, port int32) (*corev1.Service, error) {
+	name, ok := annotation.LBProfile.StringFromService(svc)
+	if !ok {
+		return svc, nil
+	}
+
+	p, err := l.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return annotation.ServiceWithOverrides(svc, p.PortAnnotations(port)), nil
+}
+
+func fromObject(obj runtime.Object) (*HCloudLoadBalancerProfile, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unexpected object type %T", obj)
+	}
+
+	p := &HCloudLoadBalancerProfile{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), p); err != nil {
+		return nil, fmt.Errorf("failed to convert %s %q: %w", Kind, u.GetName(), err)
+	}
+	return p, nil
+}
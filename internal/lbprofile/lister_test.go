@@ -0,0 +1,137 @@
+package lbprofile
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	toolscache "k8s.io/client-go/tools/cache"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+)
+
+func newProfile(t *testing.T, name string, spec Spec, status Status) *unstructured.Unstructured {
+	t.Helper()
+
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&HCloudLoadBalancerProfile{
+		TypeMeta:   metav1.TypeMeta{APIVersion: Group + "/" + Version, Kind: Kind},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       spec,
+		Status:     status,
+	})
+	require.NoError(t, err)
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func newTestLister(t *testing.T, objs ...*unstructured.Unstructured) *Lister {
+	t.Helper()
+
+	indexer := toolscache.NewIndexer(toolscache.MetaNamespaceKeyFunc, toolscache.Indexers{})
+	for _, obj := range objs {
+		require.NoError(t, indexer.Add(obj))
+	}
+	return NewLister(toolscache.NewGenericLister(indexer, GroupVersionResource.GroupResource()))
+}
+
+func newService(name string, annotations map[string]string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: annotations},
+		Spec:       corev1.ServiceSpec{Type: corev1.ServiceTypeLoadBalancer},
+	}
+}
+
+func TestLister_ServiceWithDefaults(t *testing.T) {
+	l := newTestLister(t, newProfile(t, "internal", Spec{
+		Type:     "lb21",
+		Location: "fsn1",
+		Ports: []PortConfig{
+			{Port: 443, ServiceConfig: ServiceConfig{Protocol: "https"}},
+		},
+	}, Status{}))
+
+	t.Run("without profile", func(t *testing.T) {
+		svc := newService("svc", nil)
+
+		actual, err := l.ServiceWithDefaults(svc)
+		assert.NoError(t, err)
+		assert.Same(t, svc, actual)
+	})
+
+	t.Run("with profile", func(t *testing.T) {
+		svc := newService("svc", map[string]string{
+			string(annotation.LBProfile): "internal",
+			string(annotation.LBType):    "lb31",
+		})
+
+		actual, err := l.ServiceWithDefaults(svc)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			string(annotation.LBProfile):  "internal",
+			string(annotation.LBType):     "lb31",
+			string(annotation.LBLocation): "fsn1",
+		}, actual.Annotations)
+
+		actual, err = l.ServiceForPort(actual, 443)
+		assert.NoError(t, err)
+		assert.Equal(t, "https", actual.Annotations[string(annotation.LBSvcProtocol)])
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		svc := newService("svc", map[string]string{
+			string(annotation.LBProfile): "unknown",
+		})
+
+		_, err := l.ServiceWithDefaults(svc)
+		assert.EqualError(t, err, `lbprofile/Lister.Get: HCloudLoadBalancerProfile "unknown" not found`)
+	})
+}
+
+func TestLister_SyncStatus(t *testing.T) {
+	used := newProfile(t, "used", Spec{}, Status{})
+	unchanged := newProfile(t, "unchanged", Spec{}, Status{Services: []string{"default/b"}})
+	stale := newProfile(t, "stale", Spec{}, Status{Services: []string{"default/old"}})
+
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{GroupVersionResource: Kind + "List"},
+		used, unchanged, stale,
+	)
+	l := newTestLister(t, used, unchanged, stale)
+
+	services := []*corev1.Service{
+		newService("a", map[string]string{string(annotation.LBProfile): "used"}),
+		newService("c", map[string]string{string(annotation.LBProfile): "used"}),
+		newService("b", map[string]string{string(annotation.LBProfile): "unchanged"}),
+		newService("no-profile", nil),
+	}
+	services[1].Spec.Type = corev1.ServiceTypeClusterIP
+
+	err := l.SyncStatus(context.Background(), client, services)
+	require.NoError(t, err)
+
+	var updated []string
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+			obj := action.(clienttesting.UpdateAction).GetObject().(*unstructured.Unstructured)
+			p, err := fromObject(obj)
+			require.NoError(t, err)
+			updated = append(updated, p.Name)
+
+			switch p.Name {
+			case "used":
+				assert.Equal(t, []string{"default/a"}, p.Status.Services)
+			case "stale":
+				assert.Empty(t, p.Status.Services)
+			}
+		}
+	}
+	assert.ElementsMatch(t, []string{"used", "stale"}, updated)
+}
@@ -0,0 +1,64 @@
+package lbprofile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/annotation"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/tracing"
+)
+
+// SyncStatus updates the status of all profiles with the Load Balancer
+// Services referencing them. Only profiles with a changed status are updated.
+func (l *Lister) SyncStatus(ctx context.Context, client dynamic.Interface, services []*corev1.Service) (err error) {
+	const op = "lbprofile/Lister.SyncStatus"
+	defer metrics.ObserveOperation(op)(&err)
+	ctx, endSpan := tracing.Start(ctx, op)
+	defer endSpan(&err)
+
+	used := make(map[string][]string)
+	for _, svc := range services {
+		if svc.Spec.Type != corev1.ServiceTypeLoadBalancer {
+			continue
+		}
+		if name, ok := annotation.LBProfile.StringFromService(svc); ok {
+			used[name] = append(used[name], svc.Namespace+"/"+svc.Name)
+		}
+	}
+
+	profiles, err := l.List()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var errs []error
+	for _, p := range profiles {
+		services := used[p.Name]
+		slices.Sort(services)
+		if slices.Equal(services, p.Status.Services) {
+			continue
+		}
+
+		p.Status.Services = services
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(p)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", op, p.Name, err))
+			continue
+		}
+		_, err = client.Resource(GroupVersionResource).
+			UpdateStatus(ctx, &unstructured.Unstructured{Object: obj}, metav1.UpdateOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", op, p.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
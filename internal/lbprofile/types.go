@@ -0,0 +1,123 @@
+// Package lbprofile implements the HCloudLoadBalancerProfile custom resource.
+//
+// A profile describes a complete Load Balancer configuration. Services
+// reference a profile with the [annotation.LBProfile] annotation. The settings
+// of the profile are used as defaults for the annotations of the Service.
+package lbprofile
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	Group    = "load-balancer.hetzner.cloud"
+	Version  = "v1alpha1"
+	Kind     = "HCloudLoadBalancerProfile"
+	Resource = "hcloudloadbalancerprofiles"
+)
+
+// GroupVersionResource identifies the HCloudLoadBalancerProfile resource.
+var GroupVersionResource = schema.GroupVersionResource{Group: Group, Version: Version, Resource: Resource}
+
+// HCloudLoadBalancerProfile is a cluster-scoped resource describing the
+// configuration of Hetzner Cloud Load Balancers.
+type HCloudLoadBalancerProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   Spec   `json:"spec,omitempty"`
+	Status Status `json:"status,omitempty"`
+}
+
+// Spec is the desired configuration of the Load Balancers using the profile.
+// All fields are optional. Unset fields fall back to the annotations of the
+// Service and the configured defaults.
+type Spec struct {
+	Type         string            `json:"type,omitempty"`
+	Location     string            `json:"location,omitempty"`
+	NetworkZone  string            `json:"networkZone,omitempty"`
+	Algorithm    string            `json:"algorithm,omitempty"`
+	IPv6Disabled *bool             `json:"ipv6Disabled,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Network      *Network          `json:"network,omitempty"`
+	Certificates *Certificates     `json:"certificates,omitempty"`
+
+	// Service configures all ports of the Service.
+	Service *ServiceConfig `json:"service,omitempty"`
+	// Ports configures single ports of the Service. The settings take
+	// precedence over the ones in Service.
+	Ports []PortConfig `json:"ports,omitempty"`
+}
+
+// Network configures the private network of the Load Balancer.
+type Network struct {
+	UsePrivateIP          *bool  `json:"usePrivateIP,omitempty"`
+	DisablePublicNetwork  *bool  `json:"disablePublicNetwork,omitempty"`
+	DisablePrivateIngress *bool  `json:"disablePrivateIngress,omitempty"`
+	PrivateSubnetIPRange  string `json:"privateSubnetIPRange,omitempty"`
+}
+
+// Certificates configures the certificates of the HTTPS services.
+type Certificates struct {
+	// Type is either "uploaded" or "managed".
+	Type string `json:"type,omitempty"`
+	// Uploaded contains the IDs or names of uploaded certificates.
+	Uploaded []string `json:"uploaded,omitempty"`
+	// ManagedName is the name of the managed certificate.
+	ManagedName string `json:"managedName,omitempty"`
+	// ManagedDomains contains the domains of the managed certificate.
+	ManagedDomains []string `json:"managedDomains,omitempty"`
+}
+
+// ServiceConfig configures the services of the Load Balancer.
+type ServiceConfig struct {
+	Protocol      string       `json:"protocol,omitempty"`
+	ProxyProtocol *bool        `json:"proxyProtocol,omitempty"`
+	HTTP          *HTTP        `json:"http,omitempty"`
+	HealthCheck   *HealthCheck `json:"healthCheck,omitempty"`
+}
+
+// PortConfig configures the service of a single port of the Load Balancer.
+type PortConfig struct {
+	Port int32 `json:"port"`
+
+	ServiceConfig `json:",inline"`
+}
+
+// HTTP configures the HTTP and HTTPS services of the Load Balancer.
+type HTTP struct {
+	CookieName     string `json:"cookieName,omitempty"`
+	CookieLifetime string `json:"cookieLifetime,omitempty"`
+	TimeoutIdle    string `json:"timeoutIdle,omitempty"`
+	RedirectHTTP   *bool  `json:"redirectHTTP,omitempty"`
+	StickySessions *bool  `json:"stickySessions,omitempty"`
+	// Certificates contains the IDs or names of uploaded certificates. This
+	// overrides the uploaded certificates of the profile.
+	Certificates []string `json:"certificates,omitempty"`
+}
+
+// HealthCheck configures the health check of the services.
+type HealthCheck struct {
+	Protocol string           `json:"protocol,omitempty"`
+	Port     *int32           `json:"port,omitempty"`
+	Interval string           `json:"interval,omitempty"`
+	Timeout  string           `json:"timeout,omitempty"`
+	Retries  *int32           `json:"retries,omitempty"`
+	HTTP     *HealthCheckHTTP `json:"http,omitempty"`
+}
+
+// HealthCheckHTTP configures the HTTP health check of the services.
+type HealthCheckHTTP struct {
+	Domain              string   `json:"domain,omitempty"`
+	Path                string   `json:"path,omitempty"`
+	ValidateCertificate *bool    `json:"validateCertificate,omitempty"`
+	StatusCodes         []string `json:"statusCodes,omitempty"`
+}
+
+// Status is the observed state of the profile.
+type Status struct {
+	// Services contains the Services using the profile, in the format
+	// namespace/name.
+	Services []string `json:"services,omitempty"`
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	hrobotmodels "github.com/syself/hrobot-go/models"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// Classes of errors, used as label values of [OperationErrors].
+const (
+	ErrorClassNotFound     = "not_found"
+	ErrorClassConflict     = "conflict"
+	ErrorClassRateLimit    = "rate_limit"
+	ErrorClassInvalidInput = "invalid_input"
+	ErrorClassTimeout      = "timeout"
+	ErrorClassOther        = "other"
+)
+
+// ErrorClass returns the class of err, based on the error codes of the
+// Hetzner Cloud and Robot APIs.
+func ErrorClass(err error) string {
+	switch {
+	case hcloud.IsError(err, hcloud.ErrorCodeNotFound):
+		return ErrorClassNotFound
+	case hcloud.IsError(err, hcloud.ErrorCodeConflict, hcloud.ErrorCodeLocked, hcloud.ErrorCodeUniquenessError):
+		return ErrorClassConflict
+	case hcloud.IsError(err, hcloud.ErrorCodeRateLimitExceeded):
+		return ErrorClassRateLimit
+	case hcloud.IsError(err, hcloud.ErrorCodeInvalidInput):
+		return ErrorClassInvalidInput
+	case hcloud.IsError(err, hcloud.ErrorCodeTimeout):
+		return ErrorClassTimeout
+	}
+
+	var actionErr hcloud.ActionError
+	if errors.As(err, &actionErr) && (actionErr.Code == string(hcloud.ErrorCodeConflict) || actionErr.Code == string(hcloud.ErrorCodeLocked)) {
+		return ErrorClassConflict
+	}
+
+	var robotErr hrobotmodels.Error
+	if errors.As(err, &robotErr) {
+		switch robotErr.Code {
+		case hrobotmodels.ErrorCodeNotFound, hrobotmodels.ErrorCodeServerNotFound, hrobotmodels.ErrorCodeIPNotFound:
+			return ErrorClassNotFound
+		case hrobotmodels.ErrorCodeConflict:
+			return ErrorClassConflict
+		case hrobotmodels.ErrorCodeRateLimitExceeded:
+			return ErrorClassRateLimit
+		case hrobotmodels.ErrorCodeInvalidInput:
+			return ErrorClassInvalidInput
+		}
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorClassTimeout
+	}
+
+	return ErrorClassOther
+}
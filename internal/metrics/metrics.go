@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -37,8 +38,116 @@ var OperationCalled = prometheus.NewCounterVec(prometheus.CounterOpts{
 	Help: "The total number of operation was called",
 }, []string{"op"})
 
+var OperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name: "cloud_controller_manager_operation_duration_seconds",
+	Help: "The duration of operations in seconds",
+	// 10ms to ~5min, which covers parsing annotations as well as waiting for actions.
+	Buckets: prometheus.ExponentialBuckets(0.01, 2.5, 12),
+}, []string{"op"})
+
+var OperationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "cloud_controller_manager_operation_errors_total",
+	Help: "The total number of operations, which returned an error",
+}, []string{"op", "class"})
+
+var OperationRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "cloud_controller_manager_operation_retries_total",
+	Help: "The total number of retries of mutations, which failed because a resource was locked or in conflict",
+}, []string{"op", "class"})
+
+var ManagedLoadBalancers = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "cloud_controller_manager_load_balancers",
+	Help: "The number of Load Balancers managed by the cloud controller manager",
+})
+
+var LoadBalancerTargets = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "cloud_controller_manager_load_balancer_targets",
+	Help: "The number of targets of a Load Balancer managed by the cloud controller manager",
+}, []string{"load_balancer"})
+
+var Routes = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "cloud_controller_manager_routes",
+	Help: "The number of routes in the Network managed by the cloud controller manager",
+})
+
+var HCloudRateLimitLimit = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "cloud_controller_manager_hcloud_rate_limit_limit",
+	Help: "The rate limit of the Hetzner Cloud API, as reported by the last response",
+})
+
+var HCloudRateLimitRemaining = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "cloud_controller_manager_hcloud_rate_limit_remaining",
+	Help: "The remaining requests to the Hetzner Cloud API, as reported by the last response",
+})
+
+var HCloudRateLimitReset = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "cloud_controller_manager_hcloud_rate_limit_reset_timestamp_seconds",
+	Help: "The time when the rate limit of the Hetzner Cloud API is fully recovered, as reported by the last response",
+})
+
+var HCloudRateLimitThrottled = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "cloud_controller_manager_hcloud_rate_limit_throttled_total",
+	Help: "The total number of requests to the Hetzner Cloud API, which were delayed or rejected to preserve the rate limit",
+}, []string{"reason"})
+
 func init() {
-	GetRegistry().MustRegister(OperationCalled)
+	GetRegistry().MustRegister(
+		OperationCalled,
+		OperationDuration,
+		OperationErrors,
+		OperationRetries,
+		ManagedLoadBalancers,
+		LoadBalancerTargets,
+		Routes,
+		HCloudRateLimitLimit,
+		HCloudRateLimitRemaining,
+		HCloudRateLimitReset,
+		HCloudRateLimitThrottled,
+	)
+}
+
+// ObserveOperation counts a call of op. The returned function records the
+// duration and error of op. It is meant to be deferred with a pointer to the
+// error result of op, or nil if op does not return an error:
+//
+//	defer metrics.ObserveOperation(op)(&err)
+func ObserveOperation(op string) func(err *error) {
+	OperationCalled.WithLabelValues(op).Inc()
+	start := time.Now()
+
+	return func(err *error) {
+		OperationDuration.WithLabelValues(op).Observe(time.Since(start).Seconds())
+		if err != nil && *err != nil {
+			OperationErrors.WithLabelValues(op, ErrorClass(*err)).Inc()
+		}
+	}
+}
+
+var (
+	loadBalancersMu sync.Mutex
+	loadBalancers   = map[string]struct{}{}
+)
+
+// SetLoadBalancerTargets records the number of targets of the Load Balancer
+// with the given name, and counts it as managed Load Balancer.
+func SetLoadBalancerTargets(name string, targets int) {
+	loadBalancersMu.Lock()
+	defer loadBalancersMu.Unlock()
+
+	loadBalancers[name] = struct{}{}
+	LoadBalancerTargets.WithLabelValues(name).Set(float64(targets))
+	ManagedLoadBalancers.Set(float64(len(loadBalancers)))
+}
+
+// DeleteLoadBalancer removes the Load Balancer with the given name from the
+// metrics, after it was deleted or renamed.
+func DeleteLoadBalancer(name string) {
+	loadBalancersMu.Lock()
+	defer loadBalancersMu.Unlock()
+
+	delete(loadBalancers, name)
+	LoadBalancerTargets.DeleteLabelValues(name)
+	ManagedLoadBalancers.Set(float64(len(loadBalancers)))
 }
 
 func GetRegistry() prometheus.Registerer {
@@ -49,10 +158,15 @@ func GetHandler() http.Handler {
 	return legacyregistry.Handler()
 }
 
-func Serve(address string) {
+// Serve serves the metrics and the additional handlers, e.g. the health
+// endpoints, on address.
+func Serve(address string, handlers map[string]http.Handler) {
 	// The metrics are also served by k8s.io/cloud-provider on the secure serving port.
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", GetHandler())
+	for pattern, handler := range handlers {
+		mux.Handle(pattern, handler)
+	}
 
 	server := &http.Server{
 		Addr:         address,
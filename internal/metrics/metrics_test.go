@@ -2,13 +2,23 @@ package metrics_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+
 	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
 )
 
 func TestMetrics(t *testing.T) {
@@ -44,3 +54,64 @@ func TestMetrics(t *testing.T) {
 		t.Fatal("kubernetes_build_info included in our metrics", m)
 	}
 }
+
+func TestObserveOperation(t *testing.T) {
+	t.Parallel()
+
+	func() {
+		var err error
+		defer metrics.ObserveOperation("test/success")(&err)
+	}()
+
+	func() {
+		var err error = hcloud.Error{Code: hcloud.ErrorCodeNotFound}
+		defer metrics.ObserveOperation("test/failure")(&err)
+	}()
+
+	func() {
+		defer metrics.ObserveOperation("test/no-error")(nil)
+	}()
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.OperationCalled.WithLabelValues("test/success")))
+	for _, op := range []string{"test/success", "test/failure", "test/no-error"} {
+		var m dto.Metric
+		require.NoError(t, metrics.OperationDuration.WithLabelValues(op).(prometheus.Histogram).Write(&m))
+		assert.Equal(t, uint64(1), m.GetHistogram().GetSampleCount(), op)
+	}
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.OperationErrors.WithLabelValues("test/success", metrics.ErrorClassNotFound)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.OperationErrors.WithLabelValues("test/failure", metrics.ErrorClassNotFound)))
+}
+
+func TestErrorClass(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{hcloud.Error{Code: hcloud.ErrorCodeNotFound}, metrics.ErrorClassNotFound},
+		{fmt.Errorf("wrapped: %w", hcloud.Error{Code: hcloud.ErrorCodeLocked}), metrics.ErrorClassConflict},
+		{hcloud.ActionError{Code: string(hcloud.ErrorCodeLocked)}, metrics.ErrorClassConflict},
+		{hcloud.Error{Code: hcloud.ErrorCodeRateLimitExceeded}, metrics.ErrorClassRateLimit},
+		{hcloud.Error{Code: hcloud.ErrorCodeInvalidInput}, metrics.ErrorClassInvalidInput},
+		{hcloud.Error{Code: hcloud.ErrorCodeTimeout}, metrics.ErrorClassTimeout},
+		{hrobotmodels.Error{Code: hrobotmodels.ErrorCodeServerNotFound}, metrics.ErrorClassNotFound},
+		{fmt.Errorf("wrapped: %w", hrobotmodels.Error{Code: hrobotmodels.ErrorCodeRateLimitExceeded}), metrics.ErrorClassRateLimit},
+		{context.DeadlineExceeded, metrics.ErrorClassTimeout},
+		{errors.New("something"), metrics.ErrorClassOther},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, metrics.ErrorClass(tt.err), tt.err.Error())
+	}
+}
+
+func TestSetLoadBalancerTargets(t *testing.T) {
+	metrics.SetLoadBalancerTargets("lb-1", 3)
+	metrics.SetLoadBalancerTargets("lb-2", 1)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.ManagedLoadBalancers))
+	assert.Equal(t, 3.0, testutil.ToFloat64(metrics.LoadBalancerTargets.WithLabelValues("lb-1")))
+
+	metrics.DeleteLoadBalancer("lb-1")
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.ManagedLoadBalancers))
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.LoadBalancerTargets))
+}
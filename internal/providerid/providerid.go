@@ -44,6 +44,18 @@ func (e *UnkownPrefixError) Error() string {
 // If a format is ever dropped from this method the Nodes that still use that
 // format will get abandoned and can no longer be processed by HCCM.
 func ToServerID(providerID string) (id int64, isCloudServer bool, err error) {
+	_, id, isCloudServer, err = parse(providerID)
+	return id, isCloudServer, err
+}
+
+// ToProject parses the project from a ProviderID. It is empty for Cloud Servers
+// of the default project and for Robot Servers.
+func ToProject(providerID string) (string, error) {
+	project, _, _, err := parse(providerID)
+	return project, err
+}
+
+func parse(providerID string) (project string, id int64, isCloudServer bool, err error) {
 	idString := ""
 	switch {
 	case strings.HasPrefix(providerID, prefixRobot):
@@ -57,19 +69,27 @@ func ToServerID(providerID string) (id int64, isCloudServer bool, err error) {
 		isCloudServer = true
 		idString = strings.ReplaceAll(providerID, prefixCloud, "")
 
+		// Servers of additional projects use the format hcloud://<project>/<id>.
+		if before, after, found := strings.Cut(idString, "/"); found {
+			if before == "" {
+				return "", 0, false, fmt.Errorf("providerID is missing a project: %s", providerID)
+			}
+			project, idString = before, after
+		}
+
 	default:
-		return 0, false, &UnkownPrefixError{providerID}
+		return "", 0, false, &UnkownPrefixError{providerID}
 	}
 
 	if idString == "" {
-		return 0, false, fmt.Errorf("providerID is missing a serverID: %s", providerID)
+		return "", 0, false, fmt.Errorf("providerID is missing a serverID: %s", providerID)
 	}
 
 	id, err = strconv.ParseInt(idString, 10, 64)
 	if err != nil {
-		return 0, false, fmt.Errorf("unable to parse server id: %s", providerID)
+		return "", 0, false, fmt.Errorf("unable to parse server id: %s", providerID)
 	}
-	return id, isCloudServer, nil
+	return project, id, isCloudServer, nil
 }
 
 // FromCloudServerID generates the canonical ProviderID for a Cloud Server.
@@ -77,6 +97,16 @@ func FromCloudServerID(serverID int64) string {
 	return fmt.Sprintf("%s%d", prefixCloud, serverID)
 }
 
+// FromCloudServerIDInProject generates the canonical ProviderID for a Cloud
+// Server in project. Servers of the default project use [FromCloudServerID], so
+// their ProviderID does not change when additional projects are configured.
+func FromCloudServerIDInProject(project string, serverID int64) string {
+	if project == "" {
+		return FromCloudServerID(serverID)
+	}
+	return fmt.Sprintf("%s%s/%d", prefixCloud, project, serverID)
+}
+
 // FromRobotServerNumber generates the canonical ProviderID for a Robot Server.
 func FromRobotServerNumber(serverNumber int) string {
 	return fmt.Sprintf("%s%d", prefixRobot, serverNumber)
@@ -34,6 +34,33 @@ func TestFromCloudServerID(t *testing.T) {
 	}
 }
 
+func TestFromCloudServerIDInProject(t *testing.T) {
+	assert.Equal(t, "hcloud://1234", FromCloudServerIDInProject("", 1234))
+	assert.Equal(t, "hcloud://team-a/1234", FromCloudServerIDInProject("team-a", 1234))
+}
+
+func TestToProject(t *testing.T) {
+	tests := []struct {
+		providerID string
+		want       string
+	}{
+		{providerID: "hcloud://1234", want: ""},
+		{providerID: "hcloud://team-a/1234", want: "team-a"},
+		{providerID: "hrobot://4321", want: ""},
+		{providerID: "hcloud://bm-4321", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.providerID, func(t *testing.T) {
+			got, err := ToProject(tt.providerID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := ToProject("hcloud://team-a/my-cloud")
+	assert.EqualError(t, err, "unable to parse server id: hcloud://team-a/my-cloud")
+}
+
 func TestFromRobotServerNumber(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -92,6 +119,34 @@ func TestToServerID(t *testing.T) {
 			wantIsCloudServer: false,
 			wantErr:           errors.New("providerID is missing a serverID: hcloud://"),
 		},
+		{
+			name:              "[cloud] project",
+			providerID:        "hcloud://team-a/1234",
+			wantID:            1234,
+			wantIsCloudServer: true,
+			wantErr:           nil,
+		},
+		{
+			name:              "[cloud] project with invalid id",
+			providerID:        "hcloud://team-a/my-cloud",
+			wantID:            0,
+			wantIsCloudServer: false,
+			wantErr:           errors.New("unable to parse server id: hcloud://team-a/my-cloud"),
+		},
+		{
+			name:              "[cloud] project with missing id",
+			providerID:        "hcloud://team-a/",
+			wantID:            0,
+			wantIsCloudServer: false,
+			wantErr:           errors.New("providerID is missing a serverID: hcloud://team-a/"),
+		},
+		{
+			name:              "[cloud] missing project",
+			providerID:        "hcloud:///1234",
+			wantID:            0,
+			wantIsCloudServer: false,
+			wantErr:           errors.New("providerID is missing a project: hcloud:///1234"),
+		},
 		{
 			name:              "[robot] simple id",
 			providerID:        "hrobot://4321",
@@ -186,6 +241,35 @@ func FuzzRoundTripCloud(f *testing.F) {
 	})
 }
 
+func FuzzRoundTripCloudProject(f *testing.F) {
+	f.Add("team-a", int64(123123123))
+
+	f.Fuzz(func(t *testing.T, project string, serverID int64) {
+		// Project names are validated by the configuration.
+		if project == "" || strings.Contains(project, "/") || strings.HasPrefix(project, "bm-") {
+			t.Skip()
+		}
+		providerID := FromCloudServerIDInProject(project, serverID)
+		gotProject, err := ToProject(providerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, isCloudServer, err := ToServerID(providerID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotProject != project {
+			t.Fatalf("expected %q, got %q", project, gotProject)
+		}
+		if id != serverID {
+			t.Fatalf("expected %d, got %d", serverID, id)
+		}
+		if !isCloudServer {
+			t.Fatalf("expected %t, got %t", true, isCloudServer)
+		}
+	})
+}
+
 func FuzzRoundTripRobot(f *testing.F) {
 	f.Add(123123123)
 
@@ -208,6 +292,7 @@ func FuzzToServerId(f *testing.F) {
 	f.Add("hcloud://123123123")
 	f.Add("hrobot://123123123")
 	f.Add("hcloud://bm-123123123")
+	f.Add("hcloud://team-a/123123123")
 
 	f.Fuzz(func(t *testing.T, providerID string) {
 		_, _, err := ToServerID(providerID)
@@ -221,6 +306,9 @@ func FuzzToServerId(f *testing.F) {
 			if strings.HasPrefix(err.Error(), "unable to parse server id") {
 				return
 			}
+			if strings.HasPrefix(err.Error(), "providerID is missing a project") {
+				return
+			}
 
 			t.Fatal(err)
 		}
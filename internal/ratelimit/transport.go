@@ -0,0 +1,195 @@
+// Package ratelimit preserves the rate limit of the Hetzner Cloud API, which
+// is shared by all clients of a project.
+//
+// The [Transport] tracks the rate limit from the RateLimit-* response headers.
+// While the remaining requests are low, requests marked with
+// [WithLowPriority] are delayed until enough requests are available again.
+// After a 429 response, all requests are delayed with an exponential backoff.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+)
+
+const (
+	// lowPriorityReserve is the fraction of the rate limit, which is reserved
+	// for requests without low priority.
+	lowPriorityReserve = 0.1
+
+	backoffBase = time.Second
+	backoffMax  = 10 * time.Second
+)
+
+// ErrThrottled is returned, if a request could not be sent before its
+// deadline, because the rate limit must be preserved.
+var ErrThrottled = errors.New("request throttled to preserve the rate limit")
+
+type lowPriorityKey struct{}
+
+// WithLowPriority marks all requests with ctx as not urgent, e.g. refreshes
+// of caches or health checks.
+func WithLowPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, lowPriorityKey{}, true)
+}
+
+// IsLowPriority returns whether ctx was marked with [WithLowPriority].
+func IsLowPriority(ctx context.Context) bool {
+	low, _ := ctx.Value(lowPriorityKey{}).(bool)
+	return low
+}
+
+// Transport is a [http.RoundTripper], which delays requests to preserve the
+// rate limit. It must be shared by all clients using the same project.
+type Transport struct {
+	// Base is used to send the requests. If nil, [http.DefaultTransport] is used.
+	Base http.RoundTripper
+
+	mu        sync.Mutex
+	limit     int
+	remaining int
+	// observedAt is the time when limit and remaining were reported.
+	observedAt time.Time
+	// refillRate is the number of requests per second, which become available
+	// again.
+	refillRate   float64
+	backoffs     int
+	blockedUntil time.Time
+}
+
+// NewTransport returns a [Transport] using base.
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.observe(resp)
+	return resp, nil
+}
+
+// wait blocks until the request can be sent. It fails early, if this would
+// exceed the deadline of ctx.
+func (t *Transport) wait(ctx context.Context) error {
+	low := IsLowPriority(ctx)
+
+	counted := false
+	for {
+		delay, reason := t.delay(low)
+		if delay <= 0 {
+			return nil
+		}
+
+		if !counted {
+			metrics.HCloudRateLimitThrottled.WithLabelValues(reason).Inc()
+			counted = true
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return fmt.Errorf("%w: next request possible in %s (%s)", ErrThrottled, delay.Round(time.Second), reason)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns for how long a request must be delayed and why.
+func (t *Transport) delay(low bool) (time.Duration, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var delay time.Duration
+	var reason string
+
+	if now.Before(t.blockedUntil) {
+		delay, reason = t.blockedUntil.Sub(now), "backoff"
+	}
+
+	if low && t.limit > 0 && t.refillRate > 0 {
+		reserve := float64(t.limit) * lowPriorityReserve
+		// The remaining requests recover continuously.
+		remaining := min(float64(t.limit), float64(t.remaining)+now.Sub(t.observedAt).Seconds()*t.refillRate)
+		if remaining < reserve {
+			if d := time.Duration((reserve - remaining) / t.refillRate * float64(time.Second)); d > delay {
+				delay, reason = d, "budget"
+			}
+		}
+	}
+
+	return delay, reason
+}
+
+// observe updates the rate limit from the headers of resp, and starts a
+// backoff on 429 responses.
+func (t *Transport) observe(resp *http.Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+
+	limit, errLimit := strconv.Atoi(resp.Header.Get("RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(resp.Header.Get("RateLimit-Remaining"))
+	reset, errReset := strconv.ParseInt(resp.Header.Get("RateLimit-Reset"), 10, 64)
+	if errLimit == nil && errRemaining == nil && errReset == nil {
+		t.limit = limit
+		t.remaining = remaining
+		t.observedAt = now
+
+		// RateLimit-Reset is the time when all requests are available again.
+		resetAt := time.Unix(reset, 0)
+		if resetAt.After(now) && limit > remaining {
+			t.refillRate = float64(limit-remaining) / resetAt.Sub(now).Seconds()
+		}
+
+		metrics.HCloudRateLimitLimit.Set(float64(limit))
+		metrics.HCloudRateLimitRemaining.Set(float64(remaining))
+		metrics.HCloudRateLimitReset.Set(float64(reset))
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.backoffs = 0
+		return
+	}
+
+	t.backoffs++
+	if until := now.Add(backoff(t.backoffs)); until.After(t.blockedUntil) {
+		t.blockedUntil = until
+	}
+}
+
+// backoff returns an exponential backoff with jitter for the nth consecutive
+// 429 response.
+func backoff(n int) time.Duration {
+	d := backoffMax
+	if n < 8 {
+		d = min(backoffBase<<(n-1), backoffMax)
+	}
+	// Spread the delay between d/2 and d, so not all requests are sent at once.
+	return d/2 + rand.N(d/2+1) // #nosec G404 -- No cryptographic randomness needed
+}
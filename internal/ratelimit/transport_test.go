@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/metrics"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// fakeAPI responds with the given status and rate limit headers.
+type fakeAPI struct {
+	status    int
+	limit     int
+	remaining int
+	reset     time.Duration
+	requests  []time.Time
+}
+
+func (f *fakeAPI) RoundTrip(*http.Request) (*http.Response, error) {
+	f.requests = append(f.requests, time.Now())
+
+	resp := &http.Response{StatusCode: f.status, Header: http.Header{}, Body: http.NoBody}
+	if f.limit > 0 {
+		resp.Header.Set("RateLimit-Limit", strconv.Itoa(f.limit))
+		resp.Header.Set("RateLimit-Remaining", strconv.Itoa(f.remaining))
+		resp.Header.Set("RateLimit-Reset", strconv.FormatInt(time.Now().Add(f.reset).Unix(), 10))
+	}
+	return resp, nil
+}
+
+func send(ctx context.Context, t *testing.T, transport http.RoundTripper) error {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.hetzner.cloud/v1/servers", nil)
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func TestTransport_Headers(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		api := &fakeAPI{status: http.StatusOK, limit: 3600, remaining: 3000, reset: 600 * time.Second}
+		transport := NewTransport(api)
+
+		require.NoError(t, send(context.Background(), t, transport))
+
+		assert.Equal(t, float64(3600), testutil.ToFloat64(metrics.HCloudRateLimitLimit))
+		assert.Equal(t, float64(3000), testutil.ToFloat64(metrics.HCloudRateLimitRemaining))
+		assert.Equal(t, float64(time.Now().Add(600*time.Second).Unix()), testutil.ToFloat64(metrics.HCloudRateLimitReset))
+		assert.InDelta(t, 1.0, transport.refillRate, 0.01)
+	})
+}
+
+func TestTransport_LowPriority(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		// 1 request per second becomes available again.
+		api := &fakeAPI{status: http.StatusOK, limit: 3600, remaining: 300, reset: 3300 * time.Second}
+		transport := NewTransport(api)
+		require.NoError(t, send(context.Background(), t, transport))
+
+		throttled := testutil.ToFloat64(metrics.HCloudRateLimitThrottled.WithLabelValues("budget"))
+
+		// Requests without low priority are not delayed.
+		start := time.Now()
+		require.NoError(t, send(context.Background(), t, transport))
+		assert.Equal(t, start, api.requests[1])
+
+		// Low priority requests wait until the reserve of 360 requests is available.
+		require.NoError(t, send(WithLowPriority(context.Background()), t, transport))
+		assert.Equal(t, start.Add(60*time.Second), api.requests[2])
+		assert.Equal(t, throttled+1, testutil.ToFloat64(metrics.HCloudRateLimitThrottled.WithLabelValues("budget")))
+	})
+}
+
+func TestTransport_LowPriorityDeadline(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		api := &fakeAPI{status: http.StatusOK, limit: 3600, remaining: 0, reset: 3600 * time.Second}
+		transport := NewTransport(api)
+		require.NoError(t, send(context.Background(), t, transport))
+
+		ctx, cancel := context.WithTimeout(WithLowPriority(context.Background()), 15*time.Second)
+		defer cancel()
+
+		err := send(ctx, t, transport)
+		require.ErrorIs(t, err, ErrThrottled)
+		assert.Len(t, api.requests, 1)
+	})
+}
+
+func TestTransport_Backoff(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		api := &fakeAPI{status: http.StatusTooManyRequests}
+		transport := NewTransport(api)
+
+		start := time.Now()
+		require.NoError(t, send(context.Background(), t, transport))
+
+		// All requests wait for the backoff.
+		api.status = http.StatusOK
+		require.NoError(t, send(context.Background(), t, transport))
+		delay := api.requests[1].Sub(start)
+		assert.GreaterOrEqual(t, delay, backoffBase/2)
+		assert.LessOrEqual(t, delay, backoffBase)
+
+		// A successful response ends the backoff.
+		start = time.Now()
+		require.NoError(t, send(context.Background(), t, transport))
+		assert.Equal(t, start, api.requests[2])
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		n        int
+		min, max time.Duration
+	}{
+		{n: 1, min: 500 * time.Millisecond, max: time.Second},
+		{n: 2, min: time.Second, max: 2 * time.Second},
+		{n: 4, min: 4 * time.Second, max: 8 * time.Second},
+		{n: 5, min: 5 * time.Second, max: 10 * time.Second},
+		{n: 100, min: 5 * time.Second, max: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			for range 100 {
+				d := backoff(tt.n)
+				assert.GreaterOrEqual(t, d, tt.min)
+				assert.LessOrEqual(t, d, tt.max)
+			}
+		})
+	}
+}
+
+func TestTransport_Error(t *testing.T) {
+	transport := NewTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, context.DeadlineExceeded
+	}))
+
+	err := send(context.Background(), t, transport)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -1,6 +1,7 @@
 package robot
 
 import (
+	"slices"
 	"sync"
 	"time"
 
@@ -19,14 +20,37 @@ type cacheRobotClient struct {
 	// cache
 	servers     []hrobotmodels.Server
 	serversByID map[int]*hrobotmodels.Server
+
+	resetTimeout time.Duration
+	// resetMutex is separate, so reset status lookups do not wait for an
+	// update of the server list
+	resetMutex sync.Mutex
+	resetsByID map[int]resetEntry
+	// resetAvailable contains the servers with reset options. It is nil, if
+	// the inner client can not list them.
+	resetAvailable           map[int]bool
+	resetAvailableLastUpdate time.Time
+}
+
+type resetEntry struct {
+	reset       *hrobotmodels.Reset
+	refreshedAt time.Time
 }
 
-func NewCachedClient(cacheTimeout time.Duration, robotClient hrobot.RobotClient) hrobot.RobotClient {
+// NewCachedClient returns a client which caches the server list for
+// cacheTimeout and the reset status of each server for resetCacheTimeout. If
+// robotClient was returned by [NewClient], the servers with reset options are
+// listed with one request and cached for cacheTimeout, so the reset status of
+// servers without reset options is not requested.
+func NewCachedClient(cacheTimeout, resetCacheTimeout time.Duration, robotClient hrobot.RobotClient) hrobot.RobotClient {
 	return &cacheRobotClient{
 		RobotClient: robotClient,
 		timeout:     cacheTimeout,
 
 		serversByID: make(map[int]*hrobotmodels.Server),
+
+		resetTimeout: resetCacheTimeout,
+		resetsByID:   make(map[int]resetEntry),
 	}
 }
 
@@ -84,7 +108,92 @@ func (c *cacheRobotClient) updateCacheIfNecessary() error {
 	return nil
 }
 
-// ResetGet does not use the cache, as we need up to date information for its function.
+// ResetGet is called for every node, which is not ready, on every sync of the
+// node lifecycle controller, so the reset status is cached for a short time.
+// The operating status is only returned for single servers by the API, so it
+// can not be refreshed for all servers at once.
 func (c *cacheRobotClient) ResetGet(id int) (*hrobotmodels.Reset, error) {
-	return c.RobotClient.ResetGet(id)
+	c.resetMutex.Lock()
+	defer c.resetMutex.Unlock()
+
+	if e, found := c.resetsByID[id]; found && time.Since(e.refreshedAt) < c.resetTimeout {
+		return e.reset, nil
+	}
+
+	if err := c.updateResetAvailableIfNecessary(); err != nil {
+		return nil, err
+	}
+	if c.resetAvailable != nil && !c.resetAvailable[id] {
+		// The API responds with the same error for servers without reset options.
+		return nil, hrobotmodels.Error{Code: hrobotmodels.ErrorCodeResetNotAvailable, Message: "reset not available"}
+	}
+
+	reset, err := c.RobotClient.ResetGet(id)
+	if err != nil {
+		return nil, err
+	}
+	c.resetsByID[id] = resetEntry{reset: reset, refreshedAt: time.Now()}
+	return reset, nil
+}
+
+// Make sure to lock the resetMutex before calling
+// updateResetAvailableIfNecessary. The reset options of a server only change
+// with its hardware, so they are cached like the server list.
+func (c *cacheRobotClient) updateResetAvailableIfNecessary() error {
+	lister, ok := c.RobotClient.(resetLister)
+	if !ok {
+		return nil
+	}
+
+	nextUpdate := c.resetAvailableLastUpdate.Add(c.timeout)
+	if time.Now().Before(nextUpdate) {
+		return nil
+	}
+
+	resets, err := lister.ResetGetList()
+	if err != nil {
+		return err
+	}
+
+	resetAvailable := make(map[int]bool, len(resets))
+	for _, reset := range resets {
+		resetAvailable[reset.ServerNumber] = true
+	}
+
+	c.resetAvailable = resetAvailable
+	c.resetAvailableLastUpdate = time.Now()
+	return nil
+}
+
+// CacheSnapshot is the content of the cache of a client returned by
+// [NewCachedClient].
+type CacheSnapshot struct {
+	LastUpdate time.Time             `json:"lastUpdate"`
+	Age        string                `json:"age"`
+	Servers    []hrobotmodels.Server `json:"servers"`
+}
+
+// GetCacheSnapshot returns the content of the cache of a client returned by
+// [NewCachedClient], also if it is wrapped by [NewRateLimitedClient]. It
+// returns false if client is any other client.
+func GetCacheSnapshot(client hrobot.RobotClient) (CacheSnapshot, bool) {
+	if rl, ok := client.(*rateLimitClient); ok {
+		client = rl.RobotClient
+	}
+	c, ok := client.(*cacheRobotClient)
+	if !ok {
+		return CacheSnapshot{}, false
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	snapshot := CacheSnapshot{
+		LastUpdate: c.lastUpdate,
+		Servers:    slices.Clone(c.servers),
+	}
+	if !c.lastUpdate.IsZero() {
+		snapshot.Age = time.Since(c.lastUpdate).Round(time.Second).String()
+	}
+	return snapshot, true
 }
@@ -0,0 +1,77 @@
+package robot
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/mocks"
+)
+
+func TestCacheResetGet(t *testing.T) {
+	mock := mocks.RobotClient{}
+	mock.On("ResetGet", 1).Return(&hrobotmodels.Reset{ServerNumber: 1, OperatingStatus: "running"}, nil)
+
+	client := NewCachedClient(time.Minute, time.Minute, &mock).(*cacheRobotClient)
+
+	for range 3 {
+		reset, err := client.ResetGet(1)
+		require.NoError(t, err)
+		assert.Equal(t, "running", reset.OperatingStatus)
+	}
+	mock.AssertNumberOfCalls(t, "ResetGet", 1)
+
+	// Expired
+	entry := client.resetsByID[1]
+	entry.refreshedAt = time.Now().Add(-2 * time.Minute)
+	client.resetsByID[1] = entry
+	_, err := client.ResetGet(1)
+	require.NoError(t, err)
+	mock.AssertNumberOfCalls(t, "ResetGet", 2)
+}
+
+func TestCacheResetGetList(t *testing.T) {
+	requests := map[string]int{}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		switch r.URL.Path {
+		case "/reset":
+			// The list does not contain the operating status.
+			w.Write([]byte(`[
+				{"reset": {"server_ip": "192.0.2.1", "server_ipv6_net": "2001:db8:1::", "server_number": 1, "type": ["sw", "hw", "man"]}},
+				{"reset": {"server_ip": "192.0.2.2", "server_ipv6_net": "2001:db8:2::", "server_number": 2, "type": ["hw", "man"]}}
+			]`))
+		case "/reset/1":
+			json.NewEncoder(w).Encode(hrobotmodels.ResetResponse{
+				Reset: hrobotmodels.Reset{ServerNumber: 1, OperatingStatus: "running"},
+			})
+		case "/reset/2":
+			json.NewEncoder(w).Encode(hrobotmodels.ResetResponse{
+				Reset: hrobotmodels.Reset{ServerNumber: 2, OperatingStatus: "shut off"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	client := NewCachedClient(time.Minute, time.Minute, c)
+
+	for range 2 {
+		for id, want := range map[int]string{1: "running", 2: "shut off"} {
+			reset, err := client.ResetGet(id)
+			require.NoError(t, err)
+			assert.Equal(t, want, reset.OperatingStatus)
+		}
+
+		// Servers missing in the list have no reset options.
+		_, err := client.ResetGet(3)
+		assert.True(t, hrobotmodels.IsError(err, hrobotmodels.ErrorCodeResetNotAvailable))
+	}
+
+	assert.Equal(t, map[string]int{"/reset": 1, "/reset/1": 1, "/reset/2": 1}, requests)
+}
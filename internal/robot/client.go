@@ -0,0 +1,102 @@
+package robot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	hrobot "github.com/syself/hrobot-go"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+)
+
+const defaultBaseURL = "https://robot-ws.your-server.de"
+
+// resetLister is implemented by clients which can list the reset options of
+// all servers with one request.
+type resetLister interface {
+	ResetGetList() ([]hrobotmodels.Reset, error)
+}
+
+type client struct {
+	hrobot.RobotClient // embed inner client to forward all unoverridden methods
+
+	httpClient *http.Client
+	baseURL    string
+	user       string
+	password   string
+}
+
+// NewClient returns a client for the Robot API. In addition to the methods of
+// [hrobot.RobotClient], it can list the reset options of all servers with one
+// request, which is used by [NewCachedClient].
+func NewClient(user, password string, httpClient *http.Client) hrobot.RobotClient {
+	return &client{
+		RobotClient: hrobot.NewBasicAuthClientWithCustomHttpClient(user, password, httpClient),
+
+		httpClient: httpClient,
+		baseURL:    defaultBaseURL,
+		user:       user,
+		password:   password,
+	}
+}
+
+func (c *client) SetBaseURL(baseURL string) {
+	c.baseURL = baseURL
+	c.RobotClient.SetBaseURL(baseURL)
+}
+
+func (c *client) SetCredentials(user, password string) error {
+	if err := c.RobotClient.SetCredentials(user, password); err != nil {
+		return err
+	}
+	c.user = user
+	c.password = password
+	return nil
+}
+
+// ResetGetList returns the reset options of all servers. Servers without reset
+// options are not included. Unlike [hrobot.RobotClient.ResetGet], the API does
+// not return the operating status of the servers in the list.
+func (c *client) ResetGetList() ([]hrobotmodels.Reset, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/reset", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "hrobot-client/"+c.GetVersion())
+	req.SetBasicAuth(c.user, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		var errorResponse hrobotmodels.ErrorResponse
+		if err := json.Unmarshal(body, &errorResponse); err != nil || errorResponse.Error.Code == "" {
+			return nil, fmt.Errorf("server responded with status code %v", resp.StatusCode)
+		}
+		// The API responds with NOT_FOUND, if no server has reset options.
+		if errorResponse.Error.Code == hrobotmodels.ErrorCodeNotFound {
+			return nil, nil
+		}
+		return nil, errorResponse.Error
+	}
+
+	var resetResponses []hrobotmodels.ResetResponse
+	if err := json.Unmarshal(body, &resetResponses); err != nil {
+		return nil, err
+	}
+
+	resets := make([]hrobotmodels.Reset, 0, len(resetResponses))
+	for _, resetResponse := range resetResponses {
+		resets = append(resets, resetResponse.Reset)
+	}
+	return resets, nil
+}
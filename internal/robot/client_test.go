@@ -0,0 +1,86 @@
+package robot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	hrobotmodels "github.com/syself/hrobot-go/models"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := NewClient("user", "password", server.Client()).(*client)
+	c.SetBaseURL(server.URL)
+	return c
+}
+
+func TestClientResetGetList(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ := r.BasicAuth()
+		assert.Equal(t, "user", user)
+		assert.Equal(t, "password", password)
+		assert.Equal(t, "/reset", r.URL.Path)
+
+		w.Write([]byte(`[
+			{"reset": {"server_ip": "192.0.2.1", "server_ipv6_net": "2001:db8:1::", "server_number": 1, "type": ["sw", "hw", "man"]}},
+			{"reset": {"server_ip": "192.0.2.2", "server_ipv6_net": "2001:db8:2::", "server_number": 2, "type": ["hw", "man"]}}
+		]`))
+	})
+
+	resets, err := c.ResetGetList()
+	require.NoError(t, err)
+	assert.Equal(t, []hrobotmodels.Reset{
+		{ServerIP: "192.0.2.1", ServerIPv6Net: "2001:db8:1::", ServerNumber: 1, Type: []string{"sw", "hw", "man"}},
+		{ServerIP: "192.0.2.2", ServerIPv6Net: "2001:db8:2::", ServerNumber: 2, Type: []string{"hw", "man"}},
+	}, resets)
+}
+
+func TestClientResetGetListErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		code    hrobotmodels.ErrorCode
+		wantErr string
+	}{
+		{
+			name:   "no server with reset options",
+			status: http.StatusNotFound,
+			code:   hrobotmodels.ErrorCodeNotFound,
+		},
+		{
+			name:    "rate limit exceeded",
+			status:  http.StatusForbidden,
+			code:    hrobotmodels.ErrorCodeRateLimitExceeded,
+			wantErr: "RATE_LIMIT_EXCEEDED",
+		},
+		{
+			name:    "without error code",
+			status:  http.StatusInternalServerError,
+			wantErr: "server responded with status code 500",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				if tt.code != "" {
+					json.NewEncoder(w).Encode(hrobotmodels.ErrorResponse{Error: hrobotmodels.Error{Code: tt.code}})
+				}
+			})
+
+			resets, err := c.ResetGetList()
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, resets)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package robot
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	hrobot "github.com/syself/hrobot-go"
@@ -12,9 +13,16 @@ import (
 type rateLimitClient struct {
 	hrobot.RobotClient // embed inner client to forward all unoverridden methods
 
-	waitTime    time.Duration
+	// waitTimeMu is necessary, as the wait time can be changed at runtime
+	waitTimeMu sync.Mutex
+	waitTime   time.Duration
+
+	// mu is necessary, as the status is read by the health checks
+	mu          sync.Mutex
 	exceeded    bool
 	lastChecked time.Time
+	lastRequest time.Time
+	lastError   error
 }
 
 func NewRateLimitedClient(rateLimitWaitTime time.Duration, robotClient hrobot.RobotClient) hrobot.RobotClient {
@@ -25,6 +33,57 @@ func NewRateLimitedClient(rateLimitWaitTime time.Duration, robotClient hrobot.Ro
 	}
 }
 
+// SetRateLimitWaitTime changes the wait time of a client returned by
+// [NewRateLimitedClient]. It returns false if client is any other client.
+func SetRateLimitWaitTime(client hrobot.RobotClient, rateLimitWaitTime time.Duration) bool {
+	c, ok := client.(*rateLimitClient)
+	if !ok {
+		return false
+	}
+
+	c.waitTimeMu.Lock()
+	defer c.waitTimeMu.Unlock()
+	c.waitTime = rateLimitWaitTime
+	return true
+}
+
+// Status is the state of the Robot API, as observed by the requests of a
+// client returned by [NewRateLimitedClient].
+type Status struct {
+	// RateLimited is true, while no requests are sent, because the rate limit
+	// was exceeded.
+	RateLimited bool
+	// NextTry is the time when requests are sent again, if RateLimited is true.
+	NextTry time.Time
+	// LastRequest is the time of the last request. It is zero, if no request
+	// was sent yet.
+	LastRequest time.Time
+	// LastError is the error of the last request, or nil if it succeeded.
+	LastError error
+}
+
+// GetStatus returns the status of a client returned by
+// [NewRateLimitedClient]. It returns false if client is any other client.
+func GetStatus(client hrobot.RobotClient) (Status, bool) {
+	c, ok := client.(*rateLimitClient)
+	if !ok {
+		return Status{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	status := Status{
+		RateLimited: c.isExceededLocked(),
+		LastRequest: c.lastRequest,
+		LastError:   c.lastError,
+	}
+	if status.RateLimited {
+		status.NextTry = c.lastChecked.Add(c.getWaitTime())
+	}
+	return status, true
+}
+
 func (c *rateLimitClient) ServerGet(id int) (*hrobotmodels.Server, error) {
 	if c.isExceeded() {
 		return nil, c.getRateLimitError()
@@ -61,11 +120,17 @@ func (c *rateLimitClient) set() {
 }
 
 func (c *rateLimitClient) isExceeded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.isExceededLocked()
+}
+
+func (c *rateLimitClient) isExceededLocked() bool {
 	if !c.exceeded {
 		return false
 	}
 
-	if time.Now().Before(c.lastChecked.Add(c.waitTime)) {
+	if time.Now().Before(c.lastChecked.Add(c.getWaitTime())) {
 		return true
 	}
 	// Waiting time is over. Should try again
@@ -75,6 +140,12 @@ func (c *rateLimitClient) isExceeded() bool {
 }
 
 func (c *rateLimitClient) handleError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastRequest = time.Now()
+	c.lastError = err
+
 	if err == nil {
 		return
 	}
@@ -85,10 +156,19 @@ func (c *rateLimitClient) handleError(err error) {
 }
 
 func (c *rateLimitClient) getRateLimitError() error {
-	if !c.isExceeded() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.isExceededLocked() {
 		return nil
 	}
 
-	nextPossibleCall := c.lastChecked.Add(c.waitTime)
+	nextPossibleCall := c.lastChecked.Add(c.getWaitTime())
 	return fmt.Errorf("rate limit exceeded, next try at %q", nextPossibleCall.String())
 }
+
+func (c *rateLimitClient) getWaitTime() time.Duration {
+	c.waitTimeMu.Lock()
+	defer c.waitTimeMu.Unlock()
+	return c.waitTime
+}
@@ -66,3 +66,40 @@ func TestRateLimitGetRateLimitError(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, strings.HasPrefix(err.Error(), "rate limit exceeded, next try at "))
 }
+
+func TestSetRateLimitWaitTime(t *testing.T) {
+	client := NewRateLimitedClient(5*time.Minute, &mocks.RobotClient{})
+	assert.True(t, SetRateLimitWaitTime(client, time.Minute))
+	assert.Equal(t, time.Minute, client.(*rateLimitClient).getWaitTime())
+
+	assert.False(t, SetRateLimitWaitTime(&mocks.RobotClient{}, time.Minute))
+}
+
+func TestGetStatus(t *testing.T) {
+	mock := mocks.RobotClient{}
+	client := NewRateLimitedClient(5*time.Minute, &mock)
+
+	status, ok := GetStatus(client)
+	assert.True(t, ok)
+	assert.Equal(t, Status{}, status)
+
+	mock.On("ServerGetList").Return([]hrobotmodels.Server{}, nil).Once()
+	_, _ = client.ServerGetList()
+
+	status, _ = GetStatus(client)
+	assert.False(t, status.RateLimited)
+	assert.False(t, status.LastRequest.IsZero())
+	assert.NoError(t, status.LastError)
+
+	rateLimitErr := hrobotmodels.Error{Code: hrobotmodels.ErrorCodeRateLimitExceeded, Message: "Rate limit exceeded"}
+	mock.On("ServerGetList").Return(nil, rateLimitErr).Once()
+	_, _ = client.ServerGetList()
+
+	status, _ = GetStatus(client)
+	assert.True(t, status.RateLimited)
+	assert.WithinDuration(t, time.Now().Add(5*time.Minute), status.NextTry, time.Second)
+	assert.Equal(t, rateLimitErr, status.LastError)
+
+	_, ok = GetStatus(&mocks.RobotClient{})
+	assert.False(t, ok)
+}
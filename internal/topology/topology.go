@@ -0,0 +1,133 @@
+// Package topology maps the placement of servers to the region and zone of
+// their nodes.
+package topology
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/legacydatacenter"
+)
+
+// Level is a level of the Hetzner infrastructure, which can be used as the
+// region or zone of a node.
+type Level string
+
+const (
+	LevelNetworkZone Level = "network-zone"
+	LevelLocation    Level = "location"
+	LevelDatacenter  Level = "datacenter"
+)
+
+const (
+	DefaultRegionLevel = LevelLocation
+	DefaultZoneLevel   = LevelDatacenter
+)
+
+// Levels lists all supported values of [Level], from the coarsest to the
+// finest.
+var Levels = []Level{
+	LevelNetworkZone,
+	LevelLocation,
+	LevelDatacenter,
+}
+
+// robotNetworkZones maps the locations of Robot datacenters to the network
+// zone of the Hetzner Cloud location at the same site.
+var robotNetworkZones = map[string]string{
+	"fsn1": "eu-central",
+	"nbg1": "eu-central",
+	"hel1": "eu-central",
+}
+
+// Placement describes where a server is located.
+type Placement struct {
+	NetworkZone string
+	Location    string
+	Datacenter  string
+}
+
+// CloudPlacement returns the placement of a Hetzner Cloud Server. Datacenters
+// are deprecated in the Hetzner Cloud API, so the legacy datacenter name of the
+// location is used.
+func CloudPlacement(location, networkZone string) Placement {
+	return Placement{
+		NetworkZone: networkZone,
+		Location:    location,
+		Datacenter:  legacydatacenter.NameFromLocation(location),
+	}
+}
+
+// RobotPlacement returns the placement of a Robot server in the datacenter dc,
+// e.g. "FSN1-DC14".
+func RobotPlacement(dc string) Placement {
+	datacenter := strings.ToLower(dc)
+	location, _, _ := strings.Cut(datacenter, "-")
+	return Placement{
+		NetworkZone: robotNetworkZones[location],
+		Location:    location,
+		Datacenter:  datacenter,
+	}
+}
+
+func (p Placement) value(level Level) string {
+	switch level {
+	case LevelNetworkZone:
+		return p.NetworkZone
+	case LevelLocation:
+		return p.Location
+	case LevelDatacenter:
+		return p.Datacenter
+	default:
+		return ""
+	}
+}
+
+// Mapper maps the placement of a server to the region and zone of its node.
+type Mapper struct {
+	// RegionLevel is the level used as region. Defaults to
+	// [DefaultRegionLevel].
+	RegionLevel Level
+	// ZoneLevel is the level used as zone. Defaults to [DefaultZoneLevel].
+	ZoneLevel Level
+}
+
+// Region returns the region of a server with the placement p.
+func (m Mapper) Region(p Placement) string {
+	return p.lookup(withDefault(m.RegionLevel, DefaultRegionLevel))
+}
+
+// Zone returns the zone of a server with the placement p.
+func (m Mapper) Zone(p Placement) string {
+	return p.lookup(withDefault(m.ZoneLevel, DefaultZoneLevel))
+}
+
+// lookup returns the value of level. If it is not known, e.g. the network zone
+// of a Robot server in an unknown location, the value of the next finer level
+// is used.
+func (p Placement) lookup(level Level) string {
+	i := slices.Index(Levels, level)
+	if i < 0 {
+		return ""
+	}
+	for _, l := range Levels[i:] {
+		if value := p.value(l); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// withDefault returns level, or defaultLevel if level is empty.
+func withDefault(level, defaultLevel Level) Level {
+	if level == "" {
+		return defaultLevel
+	}
+	return level
+}
+
+// IsHierarchy reports whether the zone level is not coarser than the region
+// level, after applying the defaults for empty levels.
+func IsHierarchy(regionLevel, zoneLevel Level) bool {
+	return slices.Index(Levels, withDefault(zoneLevel, DefaultZoneLevel)) >= slices.Index(Levels, withDefault(regionLevel, DefaultRegionLevel))
+}
@@ -0,0 +1,68 @@
+package topology
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapper(t *testing.T) {
+	cloud := CloudPlacement("fsn1", "eu-central")
+	robot := RobotPlacement("FSN1-DC14")
+	unknownRobot := RobotPlacement("XYZ1-DC1")
+
+	tests := []struct {
+		name       string
+		mapper     Mapper
+		placement  Placement
+		wantRegion string
+		wantZone   string
+	}{
+		{
+			name:       "cloud defaults",
+			placement:  cloud,
+			wantRegion: "fsn1",
+			wantZone:   "fsn1-dc14",
+		},
+		{
+			name:       "robot defaults",
+			placement:  robot,
+			wantRegion: "fsn1",
+			wantZone:   "fsn1-dc14",
+		},
+		{
+			name:       "cloud network zone and location",
+			mapper:     Mapper{RegionLevel: LevelNetworkZone, ZoneLevel: LevelLocation},
+			placement:  cloud,
+			wantRegion: "eu-central",
+			wantZone:   "fsn1",
+		},
+		{
+			name:       "robot network zone and location",
+			mapper:     Mapper{RegionLevel: LevelNetworkZone, ZoneLevel: LevelLocation},
+			placement:  robot,
+			wantRegion: "eu-central",
+			wantZone:   "fsn1",
+		},
+		{
+			name:       "unknown network zone falls back to location",
+			mapper:     Mapper{RegionLevel: LevelNetworkZone, ZoneLevel: LevelDatacenter},
+			placement:  unknownRobot,
+			wantRegion: "xyz1",
+			wantZone:   "xyz1-dc1",
+		},
+		{
+			name:       "new cloud location without legacy datacenter",
+			mapper:     Mapper{RegionLevel: LevelNetworkZone},
+			placement:  CloudPlacement("new1", "eu-central"),
+			wantRegion: "eu-central",
+			wantZone:   "new1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantRegion, tt.mapper.Region(tt.placement))
+			assert.Equal(t, tt.wantZone, tt.mapper.Zone(tt.placement))
+		})
+	}
+}
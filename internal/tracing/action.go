@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// ActionClient records a span for every wait for actions, as they usually
+// take much longer than the API calls.
+type ActionClient struct {
+	hcloud.IActionClient
+}
+
+// NewActionClient returns client wrapped in an [ActionClient].
+func NewActionClient(client hcloud.IActionClient) *ActionClient {
+	return &ActionClient{IActionClient: client}
+}
+
+func (c *ActionClient) WaitFor(ctx context.Context, actions ...*hcloud.Action) (err error) {
+	ids := make([]int64, 0, len(actions))
+	commands := make([]string, 0, len(actions))
+	for _, action := range actions {
+		if action == nil {
+			continue
+		}
+		ids = append(ids, action.ID)
+		commands = append(commands, action.Command)
+	}
+
+	ctx, endSpan := Start(ctx, "hcloud/ActionClient.WaitFor",
+		attribute.Int64Slice("hcloud.action.ids", ids),
+		attribute.StringSlice("hcloud.action.commands", commands),
+	)
+	defer endSpan(&err)
+
+	return c.IActionClient.WaitFor(ctx, actions...)
+}
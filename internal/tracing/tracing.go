@@ -0,0 +1,130 @@
+// Package tracing provides OpenTelemetry tracing for the operations of the
+// hcloud-cloud-controller-manager and its API calls.
+//
+// Tracing is disabled by default. Once enabled with [Setup], spans are
+// exported with OTLP over gRPC, which is configured with the standard
+// OTEL_EXPORTER_OTLP_* environment variables.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.39.0"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	serviceName = "hcloud-cloud-controller-manager"
+	scopeName   = "github.com/hetznercloud/hcloud-cloud-controller-manager"
+)
+
+var (
+	provider *sdktrace.TracerProvider
+	// tracer is nil, as long as tracing is not enabled.
+	tracer trace.Tracer
+)
+
+// Setup enables tracing and installs the global tracer provider. The
+// resource attributes can be extended with OTEL_RESOURCE_ATTRIBUTES, and the
+// sampler can be configured with OTEL_TRACES_SAMPLER.
+func Setup(ctx context.Context, version string) error {
+	const op = "tracing/Setup"
+
+	exporter, err := otlptracegrpc.New(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(serviceName),
+			semconv.ServiceVersion(version),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	tracer = provider.Tracer(scopeName)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	return nil
+}
+
+// Shutdown exports all remaining spans. It does nothing, if tracing is not
+// enabled.
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+// Start starts a span named op as child of the span in ctx. The returned
+// function ends the span and records the error. It is meant to be deferred
+// with a pointer to the error result of op:
+//
+//	ctx, endSpan := tracing.Start(ctx, op)
+//	defer endSpan(&err)
+//
+// If tracing is not enabled, ctx is returned unchanged.
+func Start(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, func(err *error)) {
+	if tracer == nil {
+		return ctx, func(*error) {}
+	}
+
+	ctx, span := tracer.Start(ctx, op, trace.WithAttributes(attrs...))
+
+	return ctx, func(err *error) {
+		if err != nil && *err != nil {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+		span.End()
+	}
+}
+
+// Service returns the attributes identifying svc.
+func Service(svc *corev1.Service) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.K8SNamespaceName(svc.Namespace),
+		attribute.String("k8s.service.name", svc.Name),
+	}
+}
+
+// Node returns the attributes identifying node.
+func Node(node *corev1.Node) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.K8SNodeName(node.Name),
+		attribute.String("k8s.node.provider_id", node.Spec.ProviderID),
+	}
+}
+
+// NewTransport wraps base, so that every request is recorded as client span.
+// If tracing is not enabled, base is returned unchanged.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if provider == nil {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/mocks"
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+func setupRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tracer = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer(scopeName)
+	t.Cleanup(func() { tracer = nil })
+
+	return recorder
+}
+
+func TestStart(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	op := func() (err error) {
+		ctx, endSpan := Start(t.Context(), "parent", attribute.String("k8s.service.name", "my-svc"))
+		defer endSpan(&err)
+
+		_, endChild := Start(ctx, "child")
+		endChild(nil)
+
+		return errors.New("failed")
+	}
+	assert.EqualError(t, op(), "failed")
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 2) {
+		child, parent := spans[0], spans[1]
+
+		assert.Equal(t, "child", child.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), child.Parent().SpanID())
+		assert.Equal(t, codes.Unset, child.Status().Code)
+
+		assert.Equal(t, "parent", parent.Name())
+		assert.Equal(t, codes.Error, parent.Status().Code)
+		assert.Equal(t, "failed", parent.Status().Description)
+		assert.Contains(t, parent.Attributes(), attribute.String("k8s.service.name", "my-svc"))
+	}
+}
+
+func TestActionClient_WaitFor(t *testing.T) {
+	recorder := setupRecorder(t)
+
+	actionClient := &mocks.ActionClient{}
+	actionClient.On("WaitFor", mock.Anything, mock.Anything).Return(nil)
+
+	client := NewActionClient(actionClient)
+	err := client.WaitFor(t.Context(),
+		&hcloud.Action{ID: 1, Command: "attach_to_network"},
+		&hcloud.Action{ID: 2, Command: "add_target"},
+	)
+	assert.NoError(t, err)
+	actionClient.AssertExpectations(t)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "hcloud/ActionClient.WaitFor", spans[0].Name())
+		assert.ElementsMatch(t, []attribute.KeyValue{
+			attribute.Int64Slice("hcloud.action.ids", []int64{1, 2}),
+			attribute.StringSlice("hcloud.action.commands", []string{"attach_to_network", "add_target"}),
+		}, spans[0].Attributes())
+	}
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx, endSpan := Start(t.Context(), "op")
+	endSpan(nil)
+
+	assert.Equal(t, t.Context(), ctx)
+}
+
+func TestNewTransport(t *testing.T) {
+	// Tracing is not enabled with Setup, the transport must not be wrapped.
+	assert.Nil(t, NewTransport(nil))
+}
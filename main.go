@@ -31,13 +31,14 @@ import (
 	cloudprovider "k8s.io/cloud-provider"
 	"k8s.io/cloud-provider/app"
 	"k8s.io/cloud-provider/app/config"
-	"k8s.io/cloud-provider/names"
 	"k8s.io/cloud-provider/options"
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/logs"
 	"k8s.io/klog/v2"
 
 	hcloud "github.com/hetznercloud/hcloud-cloud-controller-manager/hcloud"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/agent"
+	"github.com/hetznercloud/hcloud-cloud-controller-manager/internal/doctor"
 )
 
 func main() {
@@ -48,8 +49,29 @@ func main() {
 
 	setupCoverageSignalHandler()
 
-	fss := cliflag.NamedFlagSets{}
-	command := app.NewCloudControllerManagerCommand(ccmOptions, cloudInitializer, app.DefaultInitFuncConstructors, names.CCMControllerAliases(), fss, wait.NeverStop)
+	cb := app.NewBuilder()
+	cb.SetOptions(ccmOptions)
+	cb.SetCloudInitializer(cloudInitializer)
+	cb.SetStopChannel(wait.NeverStop)
+
+	cb.RegisterDefaultControllers()
+	cb.RegisterController(hcloud.LoadBalancerClassControllerName, app.ControllerInitFuncConstructor{
+		InitContext: app.ControllerInitContext{
+			ClientName: "service-controller",
+		},
+		Constructor: hcloud.StartLoadBalancerClassControllerWrapper,
+	}, nil)
+
+	// Webhooks are only served if the CloudControllerManagerWebhook feature
+	// gate is enabled.
+	cb.RegisterWebhook(hcloud.ServiceValidationWebhookName, app.WebhookConfig{
+		Path:             hcloud.ServiceValidationWebhookPath,
+		AdmissionHandler: hcloud.ValidateServiceAdmission,
+	})
+
+	command := cb.BuildCommand()
+	command.AddCommand(doctor.NewCommand(hcloud.Version()))
+	command.AddCommand(agent.NewCommand(hcloud.Version()))
 
 	pflag.CommandLine.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
 	logs.InitLogs()
@@ -62,9 +84,7 @@ func main() {
 }
 
 func cloudInitializer(config *config.CompletedConfig) cloudprovider.Interface {
-	nodeLister := config.SharedInformers.Core().V1().Nodes().Lister()
-
-	cloud, err := hcloud.NewCloud(config.ComponentConfig.KubeCloudShared.ClusterCIDR, nodeLister)
+	cloud, err := hcloud.NewCloud(config.ComponentConfig.KubeCloudShared.ClusterCIDR, config.SharedInformers)
 	if err != nil {
 		klog.Fatalf("Cloud provider could not be initialized: %v", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
 	"os"
@@ -14,6 +15,7 @@ import (
 
 type TemplateData struct {
 	ConstTable string
+	Registry   string
 }
 
 type ConstantDocTable struct {
@@ -163,6 +165,24 @@ func (t *ConstantDocTable) String(hasReadOnlyColumn bool) string {
 	return tableStr.String()
 }
 
+// Registry returns the entries of a Go map literal from the constant names to
+// their metadata.
+func (t *ConstantDocTable) Registry() string {
+	registry := strings.Builder{}
+
+	constValues := make([]string, len(t.entries))
+	for constValue, entry := range t.entries {
+		constValues[entry.pos] = constValue
+	}
+
+	for _, constValue := range constValues {
+		entry := t.entries[constValue]
+		fmt.Fprintf(&registry, "\t%s: {Type: %q, ReadOnly: %t},\n", entry.constName, entry.Type, entry.ReadOnly)
+	}
+
+	return registry.String()
+}
+
 func (t *ConstantDocTable) visitFunc() func(n ast.Node) bool {
 	return func(n ast.Node) bool {
 		genDecl, ok := n.(*ast.GenDecl)
@@ -217,47 +237,81 @@ func parseMetadataValue(line, key string) string {
 	return ""
 }
 
-func generateDocs(templatePath string, constFilePath string, outputPath string, hasReadOnlyColumn bool) error {
+func parseDocTable(constFilePath string) (*ConstantDocTable, error) {
+	// Parse AST
+	astNode, err := parser.ParseFile(&token.FileSet{}, constFilePath, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing file: %w", err)
+	}
+
+	// Create table from AST
+	return NewDocTable().FromAST(astNode)
+}
+
+func executeTemplate(templatePath string, tmplData TemplateData) ([]byte, error) {
 	// Read template file
 	templateContent, err := os.ReadFile(templatePath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Parse AST
-	astNode, err := parser.ParseFile(&token.FileSet{}, constFilePath, nil, parser.ParseComments)
+	tmpl, err := template.New("consts").Parse(string(templateContent))
 	if err != nil {
-		return fmt.Errorf("error parsing file: %w", err)
+		return nil, fmt.Errorf("error parsing template: %w", err)
 	}
 
-	// Create table from AST
-	docTable, err := NewDocTable().FromAST(astNode)
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, tmplData); err != nil {
+		return nil, fmt.Errorf("error executing template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func generateDocs(templatePath string, constFilePath string, outputPath string, hasReadOnlyColumn bool) error {
+	docTable, err := parseDocTable(constFilePath)
 	if err != nil {
 		return err
 	}
 
 	// Create Markdown file from template
-	tmpl, err := template.New("consts").Parse(string(templateContent))
+	content, err := executeTemplate(templatePath, TemplateData{ConstTable: docTable.String(hasReadOnlyColumn)})
 	if err != nil {
-		return fmt.Errorf("error parsing template: %w", err)
+		return err
 	}
 
-	tmplData := TemplateData{ConstTable: docTable.String(hasReadOnlyColumn)}
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, tmplData); err != nil {
-		return fmt.Errorf("error executing template: %w", err)
+	result := strings.TrimRight(string(content), "\n") + "\n" // end-of-file fix
+
+	return writeFile(outputPath, []byte(result))
+}
+
+func generateRegistry(templatePath string, constFilePath string, outputPath string) error {
+	docTable, err := parseDocTable(constFilePath)
+	if err != nil {
+		return err
 	}
 
-	result := strings.TrimRight(buf.String(), "\n") + "\n" // end-of-file fix
+	// Create Go file from template
+	content, err := executeTemplate(templatePath, TemplateData{Registry: docTable.Registry()})
+	if err != nil {
+		return err
+	}
 
-	// Write file to output path
+	result, err := format.Source(content)
+	if err != nil {
+		return fmt.Errorf("error formatting source: %w", err)
+	}
+
+	return writeFile(outputPath, result)
+}
+
+func writeFile(outputPath string, content []byte) error {
 	file, err := os.Create(outputPath)
 	if err != nil {
 		return fmt.Errorf("error creating file: %w", err)
 	}
 	defer file.Close()
 
-	if _, err := file.WriteString(result); err != nil {
+	if _, err := file.Write(content); err != nil {
 		return fmt.Errorf("error writing to file: %w", err)
 	}
 
@@ -271,6 +325,10 @@ func main() {
 	lbAnnotationsPath := "../internal/annotation/load_balancer.go"
 	lbOutputPath := "../docs/reference/load_balancer_annotations.md"
 
+	// Generate Load Balancer annotations registry
+	lbRegistryTemplatePath := "./load_balancer_registry.go.tmpl"
+	lbRegistryOutputPath := "../internal/annotation/load_balancer_registry.go"
+
 	// Generate Load Balancer env documentation
 	lbEnvTemplatePath := "./load_balancer_envs.md.tmpl"
 	lbEnvPath := "../internal/config/load_balancer_envs.go"
@@ -281,6 +339,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := generateRegistry(lbRegistryTemplatePath, lbAnnotationsPath, lbRegistryOutputPath); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if err := generateDocs(lbEnvTemplatePath, lbEnvPath, lbEnvOutputPath, false); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)